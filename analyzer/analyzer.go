@@ -0,0 +1,176 @@
+// Package analyzer checks the replacements that are passed at every translation call against the placeholders
+// of the message in the catalog of the default language.
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+	"golang.org/x/tools/go/analysis"
+)
+
+// keyType is the fully qualified name of the translation key type.
+const keyType = "github.com/wvell/messages.Key"
+
+// Analyzer reports the placeholders of a message that are missing in the replacements map literal of a call, and the replacements
+// that the message does not use. Calls with a replacements map that is not a literal, or with keys that are not constants, are skipped.
+var Analyzer = &analysis.Analyzer{
+	Name: "messages",
+	Doc:  "check the replacements of translation calls against the placeholders in the catalog of the default language",
+	Run:  run,
+}
+
+var (
+	catalogFile string
+	provided    string
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&catalogFile, "catalog", "", "The translation file of the default language, e.g. translations/en.json. Nothing is checked without a catalog.")
+	Analyzer.Flags.StringVar(&provided, "provided", "", "Comma separated replacement names that are provided from the context, see messages.WithReplacementProvider.")
+}
+
+// implicit are the replacements that can be passed without a placeholder in the message.
+var implicit = []string{messages.AttributeKey, messages.TimeZoneKey}
+
+var (
+	catalogsMu sync.Mutex
+	catalogs   = make(map[string]*messages.RawMessages)
+)
+
+// loadCatalog reads the catalog once, the analyzer runs for every package.
+func loadCatalog(file string) (*messages.RawMessages, error) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	if catalog, ok := catalogs[file]; ok {
+		return catalog, nil
+	}
+
+	catalog, err := messages.NewParser(afero.NewOsFs()).MessagesFromFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading catalog %s: %w", file, err)
+	}
+
+	catalogs[file] = catalog
+	return catalog, nil
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if catalogFile == "" {
+		return nil, nil
+	}
+
+	catalog, err := loadCatalog(catalogFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var providedNames []string
+	for _, name := range strings.Split(provided, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			providedNames = append(providedNames, strings.ToLower(name))
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			key, replacements, ok := translationCall(pass.TypesInfo, call)
+			if !ok {
+				return true
+			}
+
+			value, ok := catalog.Messages[key]
+			if !ok {
+				return true
+			}
+
+			placeholders := messages.Placeholders(value)
+			for _, name := range placeholders {
+				if _, ok := replacements[name]; !ok && !slices.Contains(providedNames, name) {
+					pass.Reportf(call.Pos(), "replacement %q of message %q is missing", name, key)
+				}
+			}
+
+			for name, lit := range replacements {
+				if !slices.Contains(placeholders, name) && !slices.Contains(implicit, name) && !slices.Contains(providedNames, name) {
+					pass.Reportf(lit.Pos(), "replacement %q is not used by message %q", name, key)
+				}
+			}
+
+			return true
+		})
+	}
+
+	return nil, nil
+}
+
+// translationCall returns the catalog key and the replacements of a call with a constant translation key and a replacements map literal
+// with constant keys. A nil replacements map has no replacements. Ok is false for other calls.
+func translationCall(info *types.Info, call *ast.CallExpr) (string, map[string]ast.Expr, bool) {
+	sig, ok := info.TypeOf(call.Fun).(*types.Signature)
+	if !ok || sig.Variadic() || sig.Params().Len() != len(call.Args) {
+		return "", nil, false
+	}
+
+	var key string
+	var replacements map[string]ast.Expr
+	for i, arg := range call.Args {
+		param := sig.Params().At(i).Type()
+
+		if named, ok := param.(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path()+"."+named.Obj().Name() == keyType {
+			value := info.Types[arg].Value
+			if value == nil || value.Kind() != constant.String {
+				return "", nil, false
+			}
+
+			key, _, _ = messages.SplitCasingKey(constant.StringVal(value))
+			continue
+		}
+
+		if m, ok := param.Underlying().(*types.Map); ok && types.Identical(m.Key().Underlying(), types.Typ[types.String]) {
+			if info.Types[arg].IsNil() {
+				replacements = map[string]ast.Expr{}
+				continue
+			}
+
+			lit, ok := ast.Unparen(arg).(*ast.CompositeLit)
+			if !ok {
+				return "", nil, false
+			}
+
+			replacements = make(map[string]ast.Expr, len(lit.Elts))
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					return "", nil, false
+				}
+
+				value := info.Types[kv.Key].Value
+				if value == nil || value.Kind() != constant.String {
+					return "", nil, false
+				}
+
+				replacements[constant.StringVal(value)] = kv.Key
+			}
+		}
+	}
+
+	if key == "" || replacements == nil {
+		return "", nil, false
+	}
+
+	return key, replacements, true
+}
@@ -0,0 +1,15 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	require.NoError(t, Analyzer.Flags.Set("catalog", "testdata/en.json"))
+	require.NoError(t, Analyzer.Flags.Set("provided", "tenant"))
+
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "app")
+}
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"context"
+
+	"github.com/wvell/messages"
+)
+
+var tr *messages.Translator
+
+const welcome messages.Key = "welcome"
+
+func Translate(ctx context.Context, name string, replacements map[string]any) {
+	tr.Translate(ctx, "welcome", map[string]any{"user": name, "count": 3})
+	tr.Translate(ctx, welcome, map[string]any{"user": name})                                 // want `replacement "count" of message "welcome" is missing`
+	tr.Translate(ctx, "welcome!upper", map[string]any{"user": name, "count": 3, "extra": 1}) // want `replacement "extra" is not used by message "welcome"`
+	tr.Translate(ctx, "bye", nil)
+	tr.Translate(ctx, "bye", map[string]any{"timezone": "Europe/Amsterdam", "tenant": "acme"})
+	tr.Translate(ctx, "bye", map[string]any{"attribute": "email"})
+	tr.Translate(ctx, "required", nil) // want `replacement "attribute" of message "required" is missing`
+	tr.Translate(ctx, "escaped", map[string]any{})
+	tr.Translate(ctx, "missing.key", map[string]any{"user": name})
+
+	// Replacements that are not a literal are not checked.
+	tr.Translate(ctx, "welcome", replacements)
+}
@@ -0,0 +1,11 @@
+package messages
+
+import "context"
+
+type Key string
+
+type Translator struct{}
+
+func (t *Translator) Translate(ctx context.Context, key Key, replacements map[string]any) string {
+	return string(key)
+}
@@ -0,0 +1,178 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+// ArchiveSuffix is the suffix of the archive file of a language, e.g. "en_archive.json" for "en.json".
+// The archive holds the messages that are no longer used, until they are purged. See FileStore.Archive.
+const ArchiveSuffix = "_archive.json"
+
+// isArchiveFile reports if the name is the archive file of a language.
+func isArchiveFile(name string) bool {
+	return strings.HasSuffix(name, ArchiveSuffix)
+}
+
+// WithArchive also serves the archived messages of the translation files, the messages in the translation file take precedence.
+// Use it to keep serving the messages that are archived by "msgextractor -remove -archive" until they are purged.
+func WithArchive() Opt {
+	return func(t *Translator) {
+		t.serveArchive = true
+	}
+}
+
+// archiveFile returns the archive file of the language, it is named after the translation file of the language.
+func (s *FileStore) archiveFile(lang LanguageID) (string, error) {
+	files, err := s.parser.TranslationFilesFromDir(s.dir)
+	if err != nil {
+		return "", err
+	}
+
+	file, ok := files[lang.String()]
+	if !ok {
+		file = filepath.Join(s.dir, lang.String()+".json")
+	}
+
+	return archiveFileOf(file), nil
+}
+
+// archiveFileOf returns the archive file of the translation file, e.g. "en_archive.json" for "en.json".
+func archiveFileOf(file string) string {
+	return strings.TrimSuffix(file, ".json") + ArchiveSuffix
+}
+
+// LoadArchive returns the archived messages of the language, the messages are empty if the language has no archive.
+func (s *FileStore) LoadArchive(lang LanguageID) (*RawMessages, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.loadArchive(lang)
+}
+
+// loadArchive is LoadArchive for a caller that holds s.mu.
+func (s *FileStore) loadArchive(lang LanguageID) (*RawMessages, error) {
+	file, err := s.archiveFile(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.fs.Stat(file); errors.Is(err, fs.ErrNotExist) {
+		return &RawMessages{Messages: make(map[string]string), Attributes: make(map[string]string)}, nil
+	}
+
+	archive, err := s.parser.MessagesFromFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive %s: %w", file, err)
+	}
+
+	return archive, nil
+}
+
+// Archive moves the messages of the keys from msgs to the archive of the language. The archive is saved, msgs is not.
+// Archived messages that already exist are replaced.
+func (s *FileStore) Archive(lang LanguageID, msgs *RawMessages, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	archive, err := s.loadArchive(lang)
+	if err != nil {
+		return err
+	}
+
+	moved := 0
+	for _, key := range keys {
+		value, ok := msgs.Messages[key]
+		if !ok {
+			continue
+		}
+
+		archive.Messages[key] = value
+		delete(msgs.Messages, key)
+		moved++
+	}
+
+	if moved == 0 {
+		return nil
+	}
+
+	return s.saveArchive(lang, archive)
+}
+
+// Purge removes the keys from the archive of the language for good and returns the number of removed messages.
+// Every archived message is removed when no keys are given.
+func (s *FileStore) Purge(lang LanguageID, keys ...string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	archive, err := s.loadArchive(lang)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(keys) == 0 {
+		keys = sorted.Keys(archive.Messages)
+	}
+
+	purged := 0
+	for _, key := range keys {
+		if _, ok := archive.Messages[key]; ok {
+			delete(archive.Messages, key)
+			purged++
+		}
+	}
+
+	if purged == 0 {
+		return 0, nil
+	}
+
+	return purged, s.saveArchive(lang, archive)
+}
+
+// saveArchive writes the archive of the language, the file is removed when the archive is empty. The caller holds s.mu.
+func (s *FileStore) saveArchive(lang LanguageID, archive *RawMessages) error {
+	file, err := s.archiveFile(lang)
+	if err != nil {
+		return err
+	}
+
+	if len(archive.Messages) == 0 {
+		if err := s.fs.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing archive: %w", err)
+		}
+
+		return nil
+	}
+
+	content, err := archive.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshaling archive: %w", err)
+	}
+
+	if err := afero.WriteFile(s.fs, file, content, 0o644); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+
+	return nil
+}
+
+// archiveLayer returns the layer with the archived messages of the language below it, the layer takes precedence.
+func (s *FileStore) archiveLayer(languageID string, layer *RawMessages) (*RawMessages, error) {
+	lang, err := ParseLanguage(languageID)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := s.LoadArchive(lang)
+	if err != nil || layer == nil {
+		return archive, err
+	}
+
+	return layered(archive, layer), nil
+}
@@ -0,0 +1,94 @@
+package messages
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+func TestArchive(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en_US.json", []byte(`{"welcome": "Welcome", "bye": "Bye", "old": "Old"}`), 0o600))
+
+	store := NewFileStore(fs, "translations")
+	en, err := ParseLanguage("en-US")
+	require.NoError(t, err)
+
+	msgs, err := store.parser.MessagesFromFile("translations/en_US.json")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Archive(en, msgs, "bye", "old", "missing"))
+	require.NoError(t, store.Save(en, msgs))
+	require.Equal(t, map[string]string{"welcome": "Welcome"}, msgs.Messages)
+
+	// The archive is named after the translation file and is not a translation file itself.
+	archive, err := NewParser(fs).MessagesFromFile("translations/en_US" + ArchiveSuffix)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"bye": "Bye", "old": "Old"}, archive.Messages)
+
+	files, err := NewParser(fs).TranslationFilesFromDir("translations")
+	require.NoError(t, err)
+	require.Equal(t, []string{"en-US"}, sorted.Keys(files))
+
+	ctx, err := WithLanguage(context.Background(), "en-US")
+	require.NoError(t, err)
+
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+	require.Equal(t, "bye", tr.Translate(ctx, "bye", nil))
+
+	tr, err = NewTranslator(fs, "translations", WithArchive())
+	require.NoError(t, err)
+	require.Equal(t, "Bye", tr.Translate(ctx, "bye", nil))
+	require.Equal(t, "Welcome", tr.Translate(ctx, "welcome", nil))
+
+	purged, err := store.Purge(en, "old")
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+
+	// The reload sees the purged archive.
+	require.NoError(t, tr.Reload(ctx))
+	require.Equal(t, "old", tr.Translate(ctx, "old", nil))
+	require.Equal(t, "Bye", tr.Translate(ctx, "bye", nil))
+
+	// The archive is removed when everything is purged.
+	purged, err = store.Purge(en)
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+
+	_, err = fs.Stat("translations/en_US" + ArchiveSuffix)
+	require.Error(t, err)
+}
+
+func TestArchiveConcurrent(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en_US.json", []byte(`{}`), 0o600))
+
+	store := NewFileStore(fs, "translations")
+	en, err := ParseLanguage("en-US")
+	require.NoError(t, err)
+
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			msgs := &RawMessages{Messages: map[string]string{key: strings.ToUpper(key)}}
+			require.NoError(t, store.Archive(en, msgs, key))
+		}()
+	}
+	wg.Wait()
+
+	// No archived message is lost to a concurrent load-modify-save.
+	archive, err := store.LoadArchive(en)
+	require.NoError(t, err)
+	require.Equal(t, keys, sorted.Keys(archive.Messages))
+}
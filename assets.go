@@ -0,0 +1,124 @@
+package messages
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultAssetPatterns are the glob patterns of the templates, SQL and YAML files that reference translation keys, e.g. for KeysInAssets.
+var DefaultAssetPatterns = []string{"*.tmpl", "*.gohtml", "*.html", "*.sql", "*.yaml", "*.yml"}
+
+// AssetReference is a translation key that is found in a non-Go file.
+type AssetReference struct {
+	Key  string
+	File string
+	Line int
+}
+
+func (r AssetReference) String() string {
+	return fmt.Sprintf("%s:%d", r.File, r.Line)
+}
+
+// KeysInAssets searches the files in dir, and all subdirectories, that match one of the glob patterns for the given keys.
+// This can be used to check that a key that is not found in the go source code is really unused before removing it.
+// The returned map holds the references per key, keys without references are not in the map.
+//
+// A key only matches when it is not part of a longer key, so "user" does not match "user.name".
+// Hidden directories and the directories that are skipped by the extractor by default are not searched.
+func KeysInAssets(dir string, patterns []string, keys []string) (map[string][]AssetReference, error) {
+	references := make(map[string][]AssetReference)
+	if len(keys) == 0 || len(patterns) == 0 {
+		return references, nil
+	}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path != dir && (strings.HasPrefix(entry.Name(), ".") || slices.Contains(defaultSkipDirs, entry.Name())) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !matchesAny(patterns, entry.Name()) {
+			return nil
+		}
+
+		return keysInFile(path, keys, references)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching assets: %w", err)
+	}
+
+	return references, nil
+}
+
+// keysInFile adds the references to keys in the given file to references.
+func keysInFile(file string, keys []string, references map[string][]AssetReference) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := scanner.Text()
+		for _, key := range keys {
+			if containsKey(text, key) {
+				references[key] = append(references[key], AssetReference{Key: key, File: file, Line: line})
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// containsKey reports if text contains key, where key is not part of a longer key.
+func containsKey(text, key string) bool {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], key)
+		if i == -1 {
+			return false
+		}
+
+		start := offset + i
+		end := start + len(key)
+		if (start == 0 || !isKeyChar(text[start-1])) && (end == len(text) || !isKeyChar(text[end])) {
+			return true
+		}
+
+		offset = start + 1
+	}
+}
+
+func isKeyChar(c byte) bool {
+	return c == '.' || c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,22 @@
+package messages
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysInAssets(t *testing.T) {
+	references, err := KeysInAssets("./testdata/assets", DefaultAssetPatterns, []string{"mail.subject", "mail.body", "notification.sent", "unused"})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string][]AssetReference{
+		"mail.subject": {
+			{Key: "mail.subject", File: filepath.Join("testdata", "assets", "templates", "mail.tmpl"), Line: 1},
+		},
+		"notification.sent": {
+			{Key: "notification.sent", File: filepath.Join("testdata", "assets", "query.sql"), Line: 2},
+		},
+	}, references)
+}
@@ -0,0 +1,55 @@
+package messages
+
+import (
+	"context"
+	"time"
+)
+
+var actorKey = ctxKey("actor")
+
+// AuditEvent describes a change of a message at runtime, e.g. an edit in the Editor.
+type AuditEvent struct {
+	// Time is the time of the change.
+	Time time.Time
+	// Actor is who changed the message, see WithActor. It is empty if the ctx of the change has no actor.
+	Actor string
+	// Source is what changed the message, e.g. "editor" or "redis".
+	Source   string
+	Language LanguageID
+	Key      Key
+	// Old is the message before the change, it is empty if the message did not exist.
+	Old string
+	// New is the message after the change, it is empty if the message is removed.
+	New string
+}
+
+// AuditHook receives the audit events, e.g. to write them to an audit log. It is called synchronously after the change.
+type AuditHook func(ctx context.Context, event AuditEvent)
+
+// WithActor sets who makes the changes in the ctx, e.g. the email address of the authenticated user, see AuditEvent.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromCtx returns who makes the changes in the ctx, it is empty if the ctx has no actor.
+func ActorFromCtx(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// audit calls the hook with the change, nothing is done when there is no hook or the message did not change.
+func audit(ctx context.Context, hook AuditHook, source string, lang LanguageID, key Key, old, new string) {
+	if hook == nil || old == new {
+		return
+	}
+
+	hook(ctx, AuditEvent{
+		Time:     time.Now(),
+		Actor:    ActorFromCtx(ctx),
+		Source:   source,
+		Language: lang,
+		Key:      key,
+		Old:      old,
+		New:      new,
+	})
+}
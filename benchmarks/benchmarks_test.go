@@ -0,0 +1,154 @@
+package benchmarks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+)
+
+// sizes are the number of keys of the catalogs that are benchmarked.
+var sizes = []int{10, 1_000, 50_000}
+
+// catalog returns a filesystem with an en and nl translation file of size keys.
+// The messages are a mix of plain messages, messages with placeholders, modifiers and conditions.
+func catalog(b *testing.B, size int) afero.Fs {
+	b.Helper()
+
+	fs := afero.NewMemMapFs()
+	for _, lang := range []string{"en", "nl"} {
+		raw := make(map[string]any, size+1)
+		for i := range size {
+			key := fmt.Sprintf("key.%d", i)
+			switch i % 4 {
+			case 0:
+				raw[key] = fmt.Sprintf("Plain message %d in %s", i, lang)
+			case 1:
+				raw[key] = fmt.Sprintf("Hello :User, you have :count messages (%d)", i)
+			case 2:
+				raw[key] = fmt.Sprintf("You used :ratio|percent(1) of your storage (%d)", i)
+			case 3:
+				raw[key] = fmt.Sprintf(":count == 0 ? No items (%d) | :count items", i)
+			}
+		}
+		raw["attributes"] = map[string]string{"first_name": "first name"}
+
+		content, err := json.Marshal(raw)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		err = afero.WriteFile(fs, "translations/"+lang+".json", content, 0o644)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return fs
+}
+
+func translator(b *testing.B, size int) *messages.Translator {
+	b.Helper()
+
+	tr, err := messages.NewTranslator(catalog(b, size), "translations")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return tr
+}
+
+func languageCtx(b *testing.B) context.Context {
+	b.Helper()
+
+	ctx, err := messages.WithLanguage(context.Background(), "nl")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return ctx
+}
+
+func BenchmarkLoad(b *testing.B) {
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("keys=%d", size), func(b *testing.B) {
+			fs := catalog(b, size)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var tr *messages.Translator
+			for range b.N {
+				var err error
+				tr, err = messages.NewTranslator(fs, "translations")
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(tr.MemStats().Bytes), "catalog-B")
+		})
+	}
+}
+
+func BenchmarkTranslate(b *testing.B) {
+	cases := []struct {
+		name         string
+		key          messages.Key
+		replacements map[string]any
+	}{
+		{name: "plain", key: "key.4"},
+		{name: "placeholders", key: "key.5", replacements: map[string]any{"user": "jan", "count": 3}},
+		{name: "modifier", key: "key.6", replacements: map[string]any{"ratio": 0.25}},
+		{name: "condition", key: "key.7", replacements: map[string]any{"count": 0}},
+		{name: "missing", key: "missing.key"},
+	}
+
+	for _, size := range sizes {
+		tr := translator(b, size)
+		ctx := languageCtx(b)
+
+		for _, c := range cases {
+			b.Run(fmt.Sprintf("keys=%d/%s", size, c.name), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for range b.N {
+					tr.Translate(ctx, c.key, c.replacements)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkTranslateParallel(b *testing.B) {
+	for _, size := range sizes {
+		tr := translator(b, size)
+		ctx := languageCtx(b)
+		replacements := map[string]any{"user": "jan", "count": 3}
+
+		b.Run(fmt.Sprintf("keys=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					tr.Translate(ctx, "key.5", replacements)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkTranslateMemo(b *testing.B) {
+	tr := translator(b, 1_000)
+	ctx := messages.WithMemo(languageCtx(b))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		tr.Translate(ctx, "key.4", nil)
+	}
+}
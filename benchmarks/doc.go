@@ -0,0 +1,4 @@
+// Package benchmarks measures the performance of the translator with representative catalogs of 10, 1k and 50k keys.
+//
+//	go test -run '^$' -bench . -benchmem ./benchmarks
+package benchmarks
@@ -0,0 +1,37 @@
+package messages
+
+import (
+	"golang.org/x/text/language"
+)
+
+// The unicode bidi isolation characters, see WithBidiIsolation.
+const (
+	firstStrongIsolate    = "\u2068"
+	popDirectionalIsolate = "\u2069"
+)
+
+// rtlScripts are the scripts that are written from right to left.
+var rtlScripts = map[string]bool{
+	"Adlm": true, "Arab": true, "Hebr": true, "Mand": true, "Nkoo": true,
+	"Rohg": true, "Samr": true, "Syrc": true, "Thaa": true,
+}
+
+// WithBidiIsolation wraps the replacement values in the messages of right-to-left languages, like Arabic and Hebrew,
+// with the unicode characters FSI and PDI. The direction of the value is then isolated from the message,
+// so a left-to-right value like an email address or URL does not change the order of the words around it.
+func WithBidiIsolation() Opt {
+	return func(t *Translator) {
+		t.bidiIsolation = true
+	}
+}
+
+// isRTL reports if the language is written from right to left.
+func isRTL(lang language.Tag) bool {
+	script, _ := lang.Script()
+	return rtlScripts[script.String()]
+}
+
+// bidiIsolate wraps the value with the bidi isolation characters.
+func bidiIsolate(value string) string {
+	return firstStrongIsolate + value + popDirectionalIsolate
+}
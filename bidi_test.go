@@ -0,0 +1,31 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBidiIsolation(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/ar.json", []byte(`{"sent": "تم الإرسال إلى :email", "empty": "مرحبا :name"}`), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"sent": "Sent to :email"}`), 0o600))
+
+	tr, err := NewTranslator(fs, "translations", WithBidiIsolation())
+	require.NoError(t, err)
+
+	ar, err := WithLanguage(context.Background(), "ar")
+	require.NoError(t, err)
+
+	require.Equal(t, "تم الإرسال إلى \u2068john@example.com\u2069", tr.Translate(ar, "sent", map[string]any{"email": "john@example.com"}))
+	// Empty values are not wrapped.
+	require.Equal(t, "مرحبا ", tr.Translate(ar, "empty", nil))
+
+	// Left-to-right languages are not changed.
+	en, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	require.Equal(t, "Sent to john@example.com", tr.Translate(en, "sent", map[string]any{"email": "john@example.com"}))
+}
@@ -0,0 +1,268 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/wvell/messages/internal/sorted"
+	"github.com/wvell/messages/internal/wire"
+)
+
+var (
+	ErrInvalidBundle = fmt.Errorf("invalid bundle")
+)
+
+// MarshalBundle encodes the translations as a protobuf Bundle, see proto/messages/v1/catalog.proto.
+// The languages and keys are sorted, so the same translations always have the same encoding.
+func MarshalBundle(languages map[LanguageID]*RawMessages, version Version) []byte {
+	byLanguage := make(map[string]*RawMessages, len(languages))
+	for lang, raw := range languages {
+		byLanguage[lang.String()] = raw
+	}
+
+	var b []byte
+	if version.ETag != "" {
+		b = protowire.AppendTag(b, wire.BundleVersion, protowire.BytesType)
+		b = protowire.AppendString(b, version.ETag)
+	}
+
+	if !version.LastModified.IsZero() {
+		b = protowire.AppendTag(b, wire.BundleLastModified, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(version.LastModified.UnixMilli()))
+	}
+
+	for _, lang := range sorted.Keys(byLanguage) {
+		raw := byLanguage[lang]
+
+		var msgs []byte
+		msgs = appendStringMap(msgs, wire.MessagesMessages, raw.Messages)
+		msgs = appendStringMap(msgs, wire.MessagesAttributes, raw.Attributes)
+
+		var entry []byte
+		entry = protowire.AppendTag(entry, wire.MapKey, protowire.BytesType)
+		entry = protowire.AppendString(entry, lang)
+		entry = protowire.AppendTag(entry, wire.MapValue, protowire.BytesType)
+		entry = protowire.AppendBytes(entry, msgs)
+
+		b = protowire.AppendTag(b, wire.BundleLanguages, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+
+	return b
+}
+
+// appendStringMap appends the map<string, string> field to b.
+func appendStringMap(b []byte, num protowire.Number, m map[string]string) []byte {
+	for _, key := range sorted.Keys(m) {
+		var entry []byte
+		entry = protowire.AppendTag(entry, wire.MapKey, protowire.BytesType)
+		entry = protowire.AppendString(entry, key)
+		entry = protowire.AppendTag(entry, wire.MapValue, protowire.BytesType)
+		entry = protowire.AppendString(entry, m[key])
+
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+
+	return b
+}
+
+// UnmarshalBundle decodes a protobuf Bundle, see proto/messages/v1/catalog.proto.
+// The keys and values are normalized to NFC, like the translation files.
+func UnmarshalBundle(data []byte) (map[LanguageID]*RawMessages, Version, error) {
+	languages := make(map[LanguageID]*RawMessages)
+	var version Version
+
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+		switch {
+		case num == wire.BundleVersion && typ == protowire.BytesType:
+			version.ETag = string(value)
+		case num == wire.BundleLastModified && typ == protowire.VarintType:
+			version.LastModified = time.UnixMilli(int64(varint))
+		case num == wire.BundleLanguages && typ == protowire.BytesType:
+			languageID, msgs, err := consumeMapEntry(value)
+			if err != nil {
+				return err
+			}
+
+			lang, err := ParseLanguage(string(languageID))
+			if err != nil {
+				return err
+			}
+
+			raw := &RawMessages{Messages: make(map[string]string), Attributes: make(map[string]string)}
+			if err := consumeMessages(msgs, raw); err != nil {
+				return err
+			}
+
+			languages[lang] = raw
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, Version{}, err
+	}
+
+	return languages, version, nil
+}
+
+// consumeMessages decodes a Messages message into raw. The keys and values are normalized to NFC.
+func consumeMessages(b []byte, raw *RawMessages) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType || (num != wire.MessagesMessages && num != wire.MessagesAttributes) {
+			return nil
+		}
+
+		key, value, err := consumeMapEntry(value)
+		if err != nil {
+			return err
+		}
+
+		if num == wire.MessagesMessages {
+			raw.Messages[norm.NFC.String(string(key))] = norm.NFC.String(string(value))
+		} else {
+			raw.Attributes[norm.NFC.String(string(key))] = norm.NFC.String(string(value))
+		}
+
+		return nil
+	})
+}
+
+// consumeFields calls fn for every field in b. The value of a length-delimited field is passed as value, the value of a varint as varint.
+// Unknown fields are skipped.
+func consumeFields(b []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("%w: %w", ErrInvalidBundle, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		var value []byte
+		var varint uint64
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: %w", ErrInvalidBundle, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		if err := fn(num, typ, value, varint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// consumeMapEntry returns the key and value of a map entry.
+func consumeMapEntry(b []byte) (key, value []byte, err error) {
+	err = consumeFields(b, func(num protowire.Number, typ protowire.Type, v []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+
+		switch num {
+		case wire.MapKey:
+			key = v
+		case wire.MapValue:
+			value = v
+		}
+
+		return nil
+	})
+
+	return key, value, err
+}
+
+// PushLoader is a Loader for translations that are pushed to the application, e.g. by a CatalogClient or the Bundles of MarshalBundle on a message queue.
+// Push a bundle and call Reload on the Translator to use it:
+//
+//	for {
+//		data, err := receive(ctx)
+//		...
+//		err = loader.PushBundle(data)
+//		...
+//		err = tr.Reload(ctx)
+//	}
+type PushLoader struct {
+	mu        sync.Mutex
+	languages map[LanguageID]*RawMessages
+	version   Version
+	// Pushed is closed when the first bundle is pushed.
+	pushed chan struct{}
+}
+
+var _ Loader = (*PushLoader)(nil)
+
+// NewPushLoader returns a loader without translations, Load waits until the first bundle is pushed.
+func NewPushLoader() *PushLoader {
+	return &PushLoader{pushed: make(chan struct{})}
+}
+
+// Push replaces the translations of the loader.
+func (l *PushLoader) Push(languages map[LanguageID]*RawMessages, version Version) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if languages == nil {
+		languages = make(map[LanguageID]*RawMessages)
+	}
+
+	first := l.languages == nil
+	l.languages = languages
+	l.version = version
+
+	if first {
+		close(l.pushed)
+	}
+}
+
+// PushBundle decodes the protobuf Bundle and replaces the translations of the loader.
+func (l *PushLoader) PushBundle(data []byte) error {
+	languages, version, err := UnmarshalBundle(data)
+	if err != nil {
+		return err
+	}
+
+	l.Push(languages, version)
+	return nil
+}
+
+// currentVersion returns the version of the last pushed bundle.
+func (l *PushLoader) currentVersion() Version {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.version
+}
+
+// Load returns the pushed translations, it waits until the first bundle is pushed or ctx is done.
+func (l *PushLoader) Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error) {
+	select {
+	case <-l.pushed:
+	case <-ctx.Done():
+		return nil, Version{}, ctx.Err()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.version.ETag != "" && l.version.ETag == since.ETag {
+		return nil, l.version, ErrNotModified
+	}
+
+	return l.languages, l.version, nil
+}
@@ -0,0 +1,73 @@
+package messages
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestBundle(t *testing.T) {
+	en, err := ParseLanguage("en")
+	require.NoError(t, err)
+	nlBE, err := ParseLanguage("nl-BE")
+	require.NoError(t, err)
+
+	languages := map[LanguageID]*RawMessages{
+		en:   {Messages: map[string]string{"welcome": "Welcome :Name", "bye": "Bye"}, Attributes: map[string]string{"first_name": "first name"}},
+		nlBE: {Messages: map[string]string{"welcome": "Welkom :Name"}, Attributes: map[string]string{}},
+	}
+	version := Version{ETag: "v1", LastModified: time.UnixMilli(1700000000000)}
+
+	data := MarshalBundle(languages, version)
+	require.Equal(t, data, MarshalBundle(languages, version))
+
+	decoded, decodedVersion, err := UnmarshalBundle(data)
+	require.NoError(t, err)
+	require.Equal(t, languages, decoded)
+	require.Equal(t, version.ETag, decodedVersion.ETag)
+	require.True(t, version.LastModified.Equal(decodedVersion.LastModified))
+
+	// Unknown fields are skipped.
+	withUnknown := protowire.AppendTag(data, 99, protowire.VarintType)
+	withUnknown = protowire.AppendVarint(withUnknown, 1)
+	decoded, _, err = UnmarshalBundle(withUnknown)
+	require.NoError(t, err)
+	require.Equal(t, languages, decoded)
+
+	_, _, err = UnmarshalBundle(data[:len(data)-1])
+	require.ErrorIs(t, err, ErrInvalidBundle)
+}
+
+func TestPushLoader(t *testing.T) {
+	loader := NewPushLoader()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err := NewTranslatorFromLoader(ctx, loader)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	en, err := ParseLanguage("en")
+	require.NoError(t, err)
+
+	require.NoError(t, loader.PushBundle(MarshalBundle(map[LanguageID]*RawMessages{
+		en: {Messages: map[string]string{"welcome": "Welcome"}},
+	}, Version{ETag: "v1"})))
+
+	tr, err := NewTranslatorFromLoader(context.Background(), loader)
+	require.NoError(t, err)
+
+	enCtx := ToCtx(context.Background(), "en")
+	require.Equal(t, "Welcome", tr.Translate(enCtx, "welcome", nil))
+
+	_, _, err = loader.Load(context.Background(), Version{ETag: "v1"})
+	require.ErrorIs(t, err, ErrNotModified)
+
+	loader.Push(map[LanguageID]*RawMessages{
+		en: {Messages: map[string]string{"welcome": "Hello"}},
+	}, Version{ETag: "v2"})
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, "Hello", tr.Translate(enCtx, "welcome", nil))
+}
@@ -0,0 +1,179 @@
+package messages
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// calendarNames holds the month and weekday names of a language, the weekdays start at Sunday like time.Weekday.
+type calendarNames struct {
+	months        [12]string
+	shortMonths   [12]string
+	weekdays      [7]string
+	shortWeekdays [7]string
+}
+
+var calendars = map[string]calendarNames{
+	"en": {
+		months:        [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	},
+	"nl": {
+		months:        [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		shortMonths:   [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		weekdays:      [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		shortWeekdays: [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+	},
+	"de": {
+		months:        [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths:   [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortWeekdays: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+	},
+	"fr": {
+		months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortWeekdays: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	},
+	"es": {
+		months:        [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	},
+	"it": {
+		months:        [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths:   [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		weekdays:      [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		shortWeekdays: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	},
+	"pt": {
+		months:        [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		shortMonths:   [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		weekdays:      [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		shortWeekdays: [7]string{"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."},
+	},
+}
+
+// sundayRegions are the regions where the week starts on Sunday.
+var sundayRegions = map[string]bool{"US": true, "CA": true, "BR": true, "MX": true, "JP": true, "IL": true}
+
+// calendarFor returns the names of the language, English is used for languages without names.
+func calendarFor(lang language.Tag) calendarNames {
+	base, _ := lang.Base()
+	if names, ok := calendars[base.String()]; ok {
+		return names
+	}
+
+	return calendars["en"]
+}
+
+// MonthName returns the name of the month in the language, e.g. "maart" for March in Dutch.
+// The abbreviated name is returned when short is true, e.g. "mrt".
+func MonthName(lang language.Tag, month time.Month, short bool) string {
+	if month < time.January || month > time.December {
+		return ""
+	}
+
+	names := calendarFor(lang)
+	if short {
+		return names.shortMonths[month-1]
+	}
+
+	return names.months[month-1]
+}
+
+// WeekdayName returns the name of the weekday in the language, e.g. "maandag" for Monday in Dutch.
+// The abbreviated name is returned when short is true, e.g. "ma".
+func WeekdayName(lang language.Tag, day time.Weekday, short bool) string {
+	if day < time.Sunday || day > time.Saturday {
+		return ""
+	}
+
+	names := calendarFor(lang)
+	if short {
+		return names.shortWeekdays[day]
+	}
+
+	return names.weekdays[day]
+}
+
+// FirstWeekday returns the first day of the week in the region of the language, e.g. Sunday for en-US and Monday for en-GB.
+// Monday is returned for a language without region, like in ISO 8601.
+func FirstWeekday(lang language.Tag) time.Weekday {
+	if region, confidence := lang.Region(); confidence == language.Exact && sundayRegions[region.String()] {
+		return time.Sunday
+	}
+
+	return time.Monday
+}
+
+// Month returns the name of the month in the language of the ctx.
+func (t *Translator) Month(ctx context.Context, month time.Month) string {
+	return MonthName(t.tag(ctx), month, false)
+}
+
+// Months returns the names of the months in the language of the ctx, starting at January. The abbreviated names are returned when short is true.
+func (t *Translator) Months(ctx context.Context, short bool) []string {
+	lang := t.tag(ctx)
+
+	months := make([]string, 0, 12)
+	for month := time.January; month <= time.December; month++ {
+		months = append(months, MonthName(lang, month, short))
+	}
+
+	return months
+}
+
+// Weekdays returns the names of the weekdays in the language of the ctx, starting at the first day of the week of the region,
+// e.g. for a date picker. The abbreviated names are returned when short is true.
+func (t *Translator) Weekdays(ctx context.Context, short bool) []string {
+	lang := t.tag(ctx)
+	first := FirstWeekday(lang)
+
+	weekdays := make([]string, 0, 7)
+	for i := 0; i < 7; i++ {
+		weekdays = append(weekdays, WeekdayName(lang, (first+time.Weekday(i))%7, short))
+	}
+
+	return weekdays
+}
+
+// tag returns the language of the ctx including the region, the default language is used when the ctx has no language.
+func (t *Translator) tag(ctx context.Context) language.Tag {
+	messages, region := t.messages(ctx)
+	if messages == nil {
+		return language.Und
+	}
+
+	return messages.tag(region)
+}
+
+// monthModifier formats the month of a time.Time or time.Month, ":at|month(short)" returns the abbreviated name.
+func monthModifier(lang language.Tag, value any, arg string) string {
+	switch v := value.(type) {
+	case time.Time:
+		return MonthName(lang, v.Month(), arg == "short")
+	case time.Month:
+		return MonthName(lang, v, arg == "short")
+	}
+
+	return formatReplacement(value)
+}
+
+// weekdayModifier formats the weekday of a time.Time or time.Weekday, ":at|weekday(short)" returns the abbreviated name.
+func weekdayModifier(lang language.Tag, value any, arg string) string {
+	switch v := value.(type) {
+	case time.Time:
+		return WeekdayName(lang, v.Weekday(), arg == "short")
+	case time.Weekday:
+		return WeekdayName(lang, v, arg == "short")
+	}
+
+	return formatReplacement(value)
+}
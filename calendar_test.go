@@ -0,0 +1,51 @@
+package messages
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestCalendarNames(t *testing.T) {
+	require.Equal(t, "maart", MonthName(language.Dutch, time.March, false))
+	require.Equal(t, "mrt", MonthName(language.Dutch, time.March, true))
+	require.Equal(t, "März", MonthName(language.German, time.March, false))
+	require.Equal(t, "March", MonthName(language.Japanese, time.March, false))
+	require.Equal(t, "", MonthName(language.Dutch, 13, false))
+
+	require.Equal(t, "maandag", WeekdayName(language.Dutch, time.Monday, false))
+	require.Equal(t, "sáb.", WeekdayName(language.MustParse("pt-BR"), time.Saturday, true))
+
+	require.Equal(t, time.Sunday, FirstWeekday(language.AmericanEnglish))
+	require.Equal(t, time.Monday, FirstWeekday(language.BritishEnglish))
+	require.Equal(t, time.Monday, FirstWeekday(language.English))
+}
+
+func TestTranslatorCalendar(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"month": "In :at|month", "day": "On :day|weekday(short)"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"month": "In :At|month(short)"}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "en"}))
+	require.NoError(t, err)
+
+	at := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	en := ToCtx(context.Background(), "en-US")
+	nl := ToCtx(context.Background(), "nl")
+
+	require.Equal(t, "In March", tr.Translate(en, "month", map[string]any{"at": at}))
+	require.Equal(t, "In Mrt", tr.Translate(nl, "month", map[string]any{"at": at}))
+	require.Equal(t, "On Fri", tr.Translate(en, "day", map[string]any{"day": time.Friday}))
+
+	require.Equal(t, "maart", tr.Month(nl, time.March))
+	require.Equal(t, []string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"}, tr.Months(nl, true))
+	require.Equal(t, []string{"ma", "di", "wo", "do", "vr", "za", "zo"}, tr.Weekdays(nl, true))
+	require.Equal(t, []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}, tr.Weekdays(en, true))
+
+	// The default language is used for a language without translations.
+	require.Equal(t, "March", tr.Month(ToCtx(context.Background(), "fr"), time.March))
+}
@@ -0,0 +1,64 @@
+package messages
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// casingSeparator separates a key from a casing directive, e.g. "button.save!upper".
+const casingSeparator = "!"
+
+// casings holds the casing directives that can be added to a key when it is translated.
+var casings = map[string]func(lang language.Tag, s string) string{
+	// Upper cases the whole message: "Save changes" -> "SAVE CHANGES".
+	"upper": func(lang language.Tag, s string) string {
+		return cases.Upper(lang).String(s)
+	},
+	// Lower cases the whole message: "Save Changes" -> "save changes".
+	"lower": func(lang language.Tag, s string) string {
+		return cases.Lower(lang).String(s)
+	},
+	// Sentence cases the message, only the first letter is upper case: "SAVE CHANGES" -> "Save changes".
+	"sentence": func(lang language.Tag, s string) string {
+		runes := []rune(cases.Lower(lang).String(s))
+		if len(runes) > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+
+		return string(runes)
+	},
+	// Title cases every word of the message: "save changes" -> "Save Changes".
+	"title": func(lang language.Tag, s string) string {
+		return cases.Title(lang).String(s)
+	},
+}
+
+// splitCasingDirective splits a key with a casing directive like "button.save!upper" in the key "button.save" and the casing function.
+// Ok is false if the key has no known casing directive.
+func splitCasingDirective(key Key) (Key, func(lang language.Tag, s string) string, bool) {
+	i := strings.LastIndex(string(key), casingSeparator)
+	if i <= 0 {
+		return key, nil, false
+	}
+
+	casing, ok := casings[string(key[i+1:])]
+	if !ok {
+		return key, nil, false
+	}
+
+	return key[:i], casing, true
+}
+
+// SplitCasingKey splits a key with a casing directive like "button.save!upper" in the key "button.save" and the directive "upper".
+// Ok is false if the key has no known casing directive.
+func SplitCasingKey(key string) (base, casing string, ok bool) {
+	b, _, ok := splitCasingDirective(Key(key))
+	if !ok {
+		return key, "", false
+	}
+
+	return string(b), key[len(b)+len(casingSeparator):], true
+}
@@ -0,0 +1,40 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCasingDirectives(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/casing")
+	require.NoError(t, err)
+
+	cases := []struct {
+		lang     string
+		key      Key
+		expected string
+	}{
+		{lang: "en", key: "button.save!upper", expected: "SAVE CHANGES FOR JOHN"},
+		{lang: "en", key: "button.save!lower", expected: "save changes for john"},
+		{lang: "en", key: "button.save!sentence", expected: "Save changes for john"},
+		{lang: "en", key: "button.save!title", expected: "Save Changes For John"},
+		// Upper casing follows the rules of the language, i is upper cased as İ in Turkish.
+		{lang: "tr", key: "button.save!upper", expected: "DEĞİŞİKLİKLERİ KAYDET JOHN"},
+		// A key that exists is never treated as a directive.
+		{lang: "en", key: "literal!upper", expected: "A key with an exclamation mark"},
+		{lang: "en", key: "button.save!unknown", expected: "button.save!unknown"},
+		{lang: "en", key: "missing!upper", expected: "missing!upper"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.lang+"_"+string(c.key), func(t *testing.T) {
+			ctx, err := WithLanguage(context.Background(), c.lang)
+			require.NoError(t, err)
+
+			require.Equal(t, c.expected, tr.Translate(ctx, c.key, map[string]any{"name": "john"}))
+		})
+	}
+}
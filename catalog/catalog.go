@@ -0,0 +1,329 @@
+// Package catalog reads, changes and writes the translation files of a directory, so tools can work on the translation files
+// without running msgextractor. A Catalog holds the messages of every language and the metadata of the keys:
+//
+//	c, err := catalog.Load(fs, "translations")
+//	if err != nil {
+//		return err
+//	}
+//
+//	imported, err := catalog.Load(fs, "imported")
+//	if err != nil {
+//		return err
+//	}
+//
+//	if err := c.Merge(imported, messages.MergeTheirs()); err != nil {
+//		return err
+//	}
+//
+//	return c.Write(fs, "translations")
+//
+// The translation files are written like msgextractor writes them: sorted by key and with the plural forms grouped.
+package catalog
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+	"github.com/wvell/messages/internal/sorted"
+	"golang.org/x/exp/maps"
+)
+
+// Catalog holds the translations of a translations directory.
+type Catalog struct {
+	// Languages holds the messages and attributes by language.
+	Languages map[messages.LanguageID]*messages.RawMessages
+	// Metadata holds the metadata of the keys, it is the same for all languages, see messages.MetadataFile.
+	Metadata messages.Metadata
+}
+
+// New returns an empty catalog.
+func New() *Catalog {
+	return &Catalog{
+		Languages: make(map[messages.LanguageID]*messages.RawMessages),
+		Metadata:  messages.Metadata{},
+	}
+}
+
+// Load reads the translation files and the metadata of the directory. The overlay files of an environment are not applied,
+// so a loaded catalog can be written back without copying the overlay into the translation files.
+func Load(fsys afero.Fs, dir string, opts ...messages.ParserOpt) (*Catalog, error) {
+	parser := messages.NewParser(fsys, opts...)
+
+	files, err := parser.TranslationFilesFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	c := New()
+	for languageID, file := range files {
+		lang, err := messages.ParseLanguage(languageID)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Languages[lang], err = parser.MessagesFromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading file %s: %w", file, err)
+		}
+	}
+
+	c.Metadata, err = parser.MetadataFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// LanguageIDs returns the languages of the catalog in sorted order.
+func (c *Catalog) LanguageIDs() []messages.LanguageID {
+	languages := maps.Keys(c.Languages)
+	slices.SortFunc(languages, func(a, b messages.LanguageID) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+
+	return languages
+}
+
+// Keys returns the keys of the messages of all languages in sorted order, without the region, plural form and variant, see BaseKey.
+func (c *Catalog) Keys() []string {
+	var keys []string
+	for _, msgs := range c.Languages {
+		for key := range msgs.Messages {
+			keys = append(keys, BaseKey(key))
+		}
+	}
+
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// Merge merges the messages and attributes of every language of src into the catalog with messages.Merge, languages that only exist in
+// src are added. The metadata of keys that only exist in src is added. The catalog is not changed when a conflict can not be resolved.
+func (c *Catalog) Merge(src *Catalog, strategy messages.MergeStrategy) error {
+	merged := make(map[messages.LanguageID]*messages.RawMessages, len(src.Languages))
+
+	var errs []error
+	for _, lang := range src.LanguageIDs() {
+		dst := clone(c.Languages[lang])
+		if err := messages.Merge(dst, src.Languages[lang], strategy); err != nil {
+			errs = append(errs, fmt.Errorf("language %s: %w", lang, err))
+			continue
+		}
+
+		merged[lang] = dst
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for lang, msgs := range merged {
+		c.Languages[lang] = msgs
+	}
+
+	if c.Metadata == nil {
+		c.Metadata = messages.Metadata{}
+	}
+	for key, keyMetadata := range src.Metadata {
+		if _, ok := c.Metadata[key]; !ok {
+			c.Metadata[key] = keyMetadata
+		}
+	}
+
+	return nil
+}
+
+// Diff returns the changes of every language in b compared to a, languages without changes are omitted.
+// The messages of a language that only exists in one of the catalogs are all added or removed.
+func Diff(a, b *Catalog) map[messages.LanguageID]messages.Changes {
+	diffs := make(map[messages.LanguageID]messages.Changes)
+	for lang, msgs := range a.Languages {
+		if changes := messages.Diff(msgs, b.Languages[lang]); !changes.Empty() {
+			diffs[lang] = changes
+		}
+	}
+
+	for lang, msgs := range b.Languages {
+		if _, ok := a.Languages[lang]; ok {
+			continue
+		}
+
+		if changes := messages.Diff(nil, msgs); !changes.Empty() {
+			diffs[lang] = changes
+		}
+	}
+
+	return diffs
+}
+
+// Write writes the translation file of every language to the directory, files that do not exist are created.
+// The metadata file is written when the catalog has metadata.
+func (c *Catalog) Write(fsys afero.Fs, dir string, opts ...messages.ParserOpt) error {
+	store := messages.NewFileStore(fsys, dir, opts...)
+	for _, lang := range c.LanguageIDs() {
+		if err := store.Save(lang, c.Languages[lang]); err != nil {
+			return fmt.Errorf("saving %s: %w", lang, err)
+		}
+	}
+
+	if len(c.Metadata) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c.Metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding metadata: %w", err)
+	}
+
+	return afero.WriteFile(fsys, filepath.Join(dir, messages.MetadataFile), append(data, '\n'), 0o644)
+}
+
+// AddDescriptions adds the descriptions by key to the metadata file of the directory, keys that have a description keep it.
+// The metadata file is edited as raw JSON, so the other fields are written as they are.
+func AddDescriptions(fsys afero.Fs, dir string, descriptions map[string]string) error {
+	if len(descriptions) == 0 {
+		return nil
+	}
+
+	file := filepath.Join(dir, messages.MetadataFile)
+	metadata := make(map[string]map[string]json.RawMessage)
+	data, err := afero.ReadFile(fsys, file)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("reading metadata: %w", err)
+	default:
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			return fmt.Errorf("decoding metadata: %w", err)
+		}
+	}
+
+	for key, description := range descriptions {
+		if metadata[key] == nil {
+			metadata[key] = make(map[string]json.RawMessage)
+		}
+		if _, ok := metadata[key]["description"]; ok {
+			continue
+		}
+
+		value, err := json.Marshal(description)
+		if err != nil {
+			return err
+		}
+		metadata[key]["description"] = value
+	}
+
+	data, err = json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding metadata: %w", err)
+	}
+
+	return afero.WriteFile(fsys, file, append(data, '\n'), 0o644)
+}
+
+// RenamePlaceholderMetadata renames the placeholder from to to in the samples and placeholder types of the key in the metadata file
+// of the directory, see messages.RenamePlaceholder. The names are compared case insensitive. It returns messages.ErrPlaceholderExists
+// if the metadata of the key already has the placeholder to. The metadata file is edited as raw JSON, so the other fields are written as they are.
+func RenamePlaceholderMetadata(fsys afero.Fs, dir, key, from, to string) error {
+	file := filepath.Join(dir, messages.MetadataFile)
+	data, err := afero.ReadFile(fsys, file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading metadata: %w", err)
+	}
+
+	metadata := make(map[string]map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return fmt.Errorf("decoding metadata: %w", err)
+	}
+
+	renamed := false
+	for _, field := range []string{"samples", "placeholders"} {
+		raw, ok := metadata[key][field]
+		if !ok {
+			continue
+		}
+
+		values := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return fmt.Errorf("decoding metadata %s.%s: %w", key, field, err)
+		}
+
+		for _, name := range maps.Keys(values) {
+			if !strings.EqualFold(from, to) && strings.EqualFold(name, to) {
+				return fmt.Errorf("%w: metadata %s.%s has placeholder %s", messages.ErrPlaceholderExists, key, field, name)
+			}
+		}
+
+		for _, name := range maps.Keys(values) {
+			if !strings.EqualFold(name, from) {
+				continue
+			}
+
+			value := values[name]
+			delete(values, name)
+			values[to] = value
+			renamed = true
+		}
+
+		if metadata[key][field], err = json.Marshal(values); err != nil {
+			return err
+		}
+	}
+
+	if !renamed {
+		return nil
+	}
+
+	data, err = json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding metadata: %w", err)
+	}
+
+	return afero.WriteFile(fsys, file, append(data, '\n'), 0o644)
+}
+
+// SortedKeys returns the keys of the messages in sorted order, the order of the keys in the translation files.
+func SortedKeys(msgs *messages.RawMessages) []string {
+	return sorted.Keys(msgs.Messages)
+}
+
+// BaseKey returns the key without the region, plural form and variant, the key that is used in the source code.
+// E.g. "color" for "color#exp42@GB" and "cats" for "cats[one]".
+func BaseKey(key string) string {
+	key, _, _ = messages.SplitRegionKey(key)
+	key, _, _ = messages.SplitPluralKey(key)
+	key, _, _ = messages.SplitVariantKey(key)
+
+	return key
+}
+
+// clone returns a copy of the messages, so a failed merge does not change the catalog.
+func clone(msgs *messages.RawMessages) *messages.RawMessages {
+	c := &messages.RawMessages{
+		Messages:   make(map[string]string),
+		Attributes: make(map[string]string),
+	}
+	if msgs == nil {
+		return c
+	}
+
+	for key, value := range msgs.Messages {
+		c.Messages[key] = value
+	}
+	for name, value := range msgs.Attributes {
+		c.Attributes[name] = value
+	}
+
+	return c
+}
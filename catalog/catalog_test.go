@@ -0,0 +1,137 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/messages"
+)
+
+var (
+	en = messages.LanguageID{Language: "en"}
+	nl = messages.LanguageID{Language: "nl"}
+	de = messages.LanguageID{Language: "de"}
+)
+
+func TestCatalog(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"hello": "Hello", "cats": {"one": "One cat", "other": ":count cats"}, "color@GB": "Colour"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"hello": "Hallo", "attributes": {"email": "e-mail"}}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{"hello": {"description": "Greeting", "max_length": 10}}`), 0o644))
+
+	c, err := Load(fs, "translations")
+	require.NoError(t, err)
+	require.Equal(t, []messages.LanguageID{en, nl}, c.LanguageIDs())
+	require.Equal(t, []string{"cats", "color", "hello"}, c.Keys())
+	require.Equal(t, []string{"cats[one]", "cats[other]", "color@GB", "hello"}, SortedKeys(c.Languages[en]))
+	require.Equal(t, "Greeting", c.Metadata["hello"].Description)
+
+	src := New()
+	src.Languages[nl] = &messages.RawMessages{Messages: map[string]string{"hello": "Hoi", "bye": "Doei"}}
+	src.Languages[de] = &messages.RawMessages{Messages: map[string]string{"hello": "Hallo"}}
+	src.Metadata["bye"] = messages.KeyMetadata{Description: "Farewell"}
+
+	// A conflict that can not be resolved does not change the catalog.
+	require.ErrorIs(t, c.Merge(src, messages.MergeErrorOnConflict()), messages.ErrMergeConflict)
+	require.Equal(t, "Hallo", c.Languages[nl].Messages["hello"])
+	require.NotContains(t, c.Languages, de)
+
+	before, err := Load(fs, "translations")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Merge(src, messages.MergeTheirs()))
+	require.Equal(t, map[string]string{"hello": "Hoi", "bye": "Doei"}, c.Languages[nl].Messages)
+	require.Equal(t, map[string]string{"email": "e-mail"}, c.Languages[nl].Attributes)
+	require.Equal(t, "Farewell", c.Metadata["bye"].Description)
+
+	require.Equal(t, map[messages.LanguageID]messages.Changes{
+		nl: {
+			Added:    []messages.Change{{Key: "bye", New: "Doei"}},
+			Modified: []messages.Change{{Key: "hello", Old: "Hallo", New: "Hoi"}},
+		},
+		de: {
+			Added: []messages.Change{{Key: "hello", New: "Hallo"}},
+		},
+	}, Diff(before, c))
+
+	require.NoError(t, c.Write(fs, "translations"))
+	data, err := afero.ReadFile(fs, "translations/de.json")
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"attributes\": {},\n  \"hello\": \"Hallo\"\n}", string(data))
+
+	written, err := Load(fs, "translations")
+	require.NoError(t, err)
+	require.Empty(t, Diff(c, written))
+	require.Equal(t, c.Metadata, written.Metadata)
+
+	// The metadata is written without the fields that are not set.
+	data, err = afero.ReadFile(fs, "translations/metadata.json")
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "bye": {
+    "description": "Farewell"
+  },
+  "hello": {
+    "description": "Greeting",
+    "max_length": 10
+  }
+}
+`, string(data))
+
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c.Metadata["bye"] = messages.KeyMetadata{Description: "Farewell", Updated: updated}
+	require.NoError(t, c.Write(fs, "translations"))
+	written, err = Load(fs, "translations")
+	require.NoError(t, err)
+	require.Equal(t, updated, written.Metadata["bye"].Updated)
+	require.True(t, written.Metadata["bye"].ValidFrom.IsZero())
+}
+
+func TestAddDescriptions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{"hello": {"description": "Greeting", "max_length": 10}}`), 0o644))
+
+	require.NoError(t, AddDescriptions(fs, "translations", map[string]string{"hello": "Hello", "bye": "Farewell"}))
+
+	metadata, err := messages.NewParser(fs).MetadataFromDir("translations")
+	require.NoError(t, err)
+	require.Equal(t, messages.Metadata{
+		"hello": {Description: "Greeting", MaxLength: 10},
+		"bye":   {Description: "Farewell"},
+	}, metadata)
+}
+
+func TestRenamePlaceholderMetadata(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{
+		"welcome": {"description": "Greeting", "samples": {"user": "Jan", "count": 3}, "placeholders": {"user": "string"}},
+		"bye": {"samples": {"user": "Piet"}}
+	}`), 0o644))
+
+	require.NoError(t, RenamePlaceholderMetadata(fs, "translations", "welcome", "user", "username"))
+
+	metadata, err := messages.NewParser(fs).MetadataFromDir("translations")
+	require.NoError(t, err)
+	require.Equal(t, messages.Metadata{
+		"welcome": {
+			Description:  "Greeting",
+			Samples:      map[string]any{"username": "Jan", "count": int64(3)},
+			Placeholders: messages.PlaceholderTypes{"username": "string"},
+		},
+		"bye": {Samples: map[string]any{"user": "Piet"}},
+	}, metadata)
+
+	err = RenamePlaceholderMetadata(fs, "translations", "welcome", "count", "username")
+	require.ErrorIs(t, err, messages.ErrPlaceholderExists)
+
+	// Without a metadata file there is nothing to rename.
+	require.NoError(t, RenamePlaceholderMetadata(fs, "other", "welcome", "user", "username"))
+}
+
+func TestBaseKey(t *testing.T) {
+	require.Equal(t, "color", BaseKey("color#exp42@GB"))
+	require.Equal(t, "cats", BaseKey("cats[one]@GB"))
+	require.Equal(t, "hello", BaseKey("hello"))
+}
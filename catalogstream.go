@@ -0,0 +1,216 @@
+package messages
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CatalogStreamContentType is the content type of the catalog stream of CatalogServer: a sequence of Bundles of
+// proto/messages/v1/catalog.proto, each prefixed with its length as a 4 byte big endian unsigned integer.
+const CatalogStreamContentType = "application/vnd.wvell.messages.bundle-stream"
+
+// maxCatalogFrame is the maximum size of a bundle on the stream, a bundle holds the translations of all languages.
+const maxCatalogFrame = 64 << 20
+
+// CatalogServer is an http.Handler that streams the translations from a central i18n service to the subscribers,
+// e.g. a CatalogClient. Publish the translations every time they change, the subscribers receive them as a Bundle:
+//
+//	server := messages.NewCatalogServer()
+//	server.Publish(languages, messages.Version{ETag: "v2"})
+//
+//	mux.Handle("/catalog", server)
+//
+// A subscription is a GET request that is answered with a CatalogStreamContentType stream, which ends when the subscriber
+// goes away. The Version.ETag of the bundles is compared with the If-None-Match header of the request, a subscriber that
+// already has the version does not receive it again. The stream is plain HTTP, it works over HTTP/1.1 and HTTP/2.
+type CatalogServer struct {
+	mu      sync.Mutex
+	bundle  []byte
+	version string
+	// Published counts the published bundles, a stream sends a bundle once.
+	published uint64
+	// Changed is closed and replaced when a new bundle is published.
+	changed chan struct{}
+}
+
+// NewCatalogServer returns a server without translations, the subscribers wait until the first bundle is published.
+func NewCatalogServer() *CatalogServer {
+	return &CatalogServer{changed: make(chan struct{})}
+}
+
+// Publish sends the translations to the subscribers.
+func (s *CatalogServer) Publish(languages map[LanguageID]*RawMessages, version Version) {
+	bundle := MarshalBundle(languages, version)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bundle = bundle
+	s.version = version.ETag
+	s.published++
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// current returns the published bundle, its number and the channel that is closed when it changes.
+func (s *CatalogServer) current() ([]byte, string, uint64, chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.bundle, s.version, s.published, s.changed
+}
+
+func (s *CatalogServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	since := strings.Trim(r.Header.Get("If-None-Match"), `"`)
+
+	w.Header().Set("Content-Type", CatalogStreamContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	_ = rc.Flush()
+
+	var sent uint64
+	for {
+		bundle, version, published, changed := s.current()
+		if published != sent && (version == "" || version != since) {
+			if err := writeCatalogFrame(w, bundle); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+
+			sent, since = published, version
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// CatalogClient subscribes to the catalog stream of a CatalogServer and pushes the bundles to a PushLoader:
+//
+//	loader := messages.NewPushLoader()
+//	client := messages.NewCatalogClient("https://i18n.example.com/catalog", nil)
+//	go func() {
+//		for ctx.Err() == nil {
+//			err := client.Subscribe(ctx, loader, func() { pushed <- struct{}{} })
+//			...
+//		}
+//	}()
+type CatalogClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewCatalogClient returns a client for the catalog stream at url. The http.DefaultClient is used when client is nil,
+// the client should not have a Timeout because the stream stays open, cancel the ctx of Subscribe instead.
+func NewCatalogClient(url string, client *http.Client) *CatalogClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &CatalogClient{url: url, client: client}
+}
+
+// Subscribe pushes the bundles of the stream to the loader and calls onPush after every bundle, e.g. to reload the Translator.
+// The version of the loader is sent in the If-None-Match header, so a bundle that the loader already has is not sent again.
+// Subscribe returns when the stream ends: nil if the server ended it, ctx.Err() if ctx is done, or the error of the
+// request or a bundle that can not be decoded. Call it again to resubscribe.
+func (c *CatalogClient) Subscribe(ctx context.Context, loader *PushLoader, onPush func()) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", CatalogStreamContentType)
+	if version := loader.currentVersion(); version.ETag != "" {
+		req.Header.Set("If-None-Match", version.ETag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("subscribing to catalog: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("subscribing to catalog: unexpected status %s", resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != CatalogStreamContentType {
+		return fmt.Errorf("subscribing to catalog: unexpected content type %q", contentType)
+	}
+
+	for {
+		bundle, err := readCatalogFrame(resp.Body)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("subscribing to catalog: %w", err)
+		}
+
+		if err := loader.PushBundle(bundle); err != nil {
+			return err
+		}
+
+		if onPush != nil {
+			onPush()
+		}
+	}
+}
+
+// writeCatalogFrame writes the bundle with its length prefix.
+func writeCatalogFrame(w io.Writer, bundle []byte) error {
+	var prefix [4]byte
+	binary.BigEndian.PutUint32(prefix[:], uint32(len(bundle)))
+
+	if _, err := w.Write(prefix[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(bundle)
+	return err
+}
+
+// readCatalogFrame reads a length prefixed bundle, io.EOF is returned when the stream ends between bundles.
+func readCatalogFrame(r io.Reader) ([]byte, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: truncated bundle", ErrInvalidBundle)
+		}
+
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(prefix[:])
+	if size > maxCatalogFrame {
+		return nil, fmt.Errorf("%w: bundle of %d bytes is larger than %d bytes", ErrInvalidBundle, size, maxCatalogFrame)
+	}
+
+	bundle := make([]byte, size)
+	if _, err := io.ReadFull(r, bundle); err != nil {
+		return nil, fmt.Errorf("%w: truncated bundle", ErrInvalidBundle)
+	}
+
+	return bundle, nil
+}
@@ -0,0 +1,70 @@
+package messages
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogStream(t *testing.T) {
+	en := LanguageID{Language: "en"}
+	catalogs := NewCatalogServer()
+
+	// The stream is plain HTTP/1.1 without TLS.
+	server := httptest.NewServer(catalogs)
+	defer server.Close()
+
+	loader := NewPushLoader()
+	client := NewCatalogClient(server.URL, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pushed := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Subscribe(ctx, loader, func() { pushed <- struct{}{} })
+	}()
+
+	catalogs.Publish(map[LanguageID]*RawMessages{en: {Messages: map[string]string{"welcome": "Welcome"}}}, Version{ETag: "v1"})
+	<-pushed
+
+	tr, err := NewTranslatorFromLoader(context.Background(), loader)
+	require.NoError(t, err)
+	require.Equal(t, "Welcome", tr.Translate(ToCtx(context.Background(), "en"), "welcome", nil))
+
+	// A new bundle is pushed to the subscribers.
+	catalogs.Publish(map[LanguageID]*RawMessages{en: {Messages: map[string]string{"welcome": "Hello"}}}, Version{ETag: "v2"})
+	<-pushed
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, "Hello", tr.Translate(ToCtx(context.Background(), "en"), "welcome", nil))
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	// A subscriber that already has the version does not receive it again.
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, client.Subscribe(ctx, loader, func() { t.Error("bundle pushed again") }), context.DeadlineExceeded)
+}
+
+func TestCatalogStreamErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/truncated" {
+			w.Header().Set("Content-Type", CatalogStreamContentType)
+			_, _ = w.Write([]byte{0, 0, 0, 10, 1})
+			return
+		}
+
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	err := NewCatalogClient(server.URL+"/truncated", nil).Subscribe(context.Background(), NewPushLoader(), nil)
+	require.ErrorIs(t, err, ErrInvalidBundle)
+
+	err = NewCatalogClient(server.URL+"/missing", nil).Subscribe(context.Background(), NewPushLoader(), nil)
+	require.ErrorContains(t, err, "unexpected status 404")
+}
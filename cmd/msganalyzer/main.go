@@ -0,0 +1,13 @@
+// Command msganalyzer checks the replacements of translation calls against the placeholders in the catalog of the default language:
+//
+//	msganalyzer -catalog translations/en.json ./...
+package main
+
+import (
+	"github.com/wvell/messages/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
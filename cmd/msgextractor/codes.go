@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+)
+
+// codes prints the messages of the error codes in the metadata file of a language as JSON.
+func codes(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("codes", flag.ExitOnError)
+	dir := flags.String("dst", "", "The directory that contains the translation files and the metadata file.")
+	lang := flags.String("lang", "", "The language of the messages, e.g. nl or en-GB.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor codes -dst ./translations -lang nl
+
+Codes prints the messages of the error codes that are declared in the metadata file as a JSON object keyed by code,
+e.g. for support documentation or client SDKs. The messages are rendered with the sample replacements.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *lang == "" {
+		flags.Usage()
+		return fmt.Errorf("-lang is required")
+	}
+
+	tr, err := messages.NewTranslator(afero.NewOsFs(), *dir)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := messages.WithLanguage(context.Background(), *lang)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(tr.CodeTable(ctx))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+	"golang.org/x/exp/maps"
+)
+
+// diff prints the messages that are added, removed and modified in the second directory compared to the first directory.
+func diff(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor diff ./old/translations ./new/translations
+
+Diff prints the messages and attributes that are added (+), removed (-) and modified (~) in the second directory, by language.
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("two directories are required")
+	}
+
+	diffs, err := messages.DiffDirs(afero.NewOsFs(), flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	languages := maps.Keys(diffs)
+	slices.SortFunc(languages, func(a, b messages.LanguageID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	for _, lang := range languages {
+		changes := diffs[lang]
+		for _, change := range changes.Added {
+			fmt.Fprintf(out, "+ %s %s: %q\n", lang, changeKey(change), change.New)
+		}
+		for _, change := range changes.Removed {
+			fmt.Fprintf(out, "- %s %s: %q\n", lang, changeKey(change), change.Old)
+		}
+		for _, change := range changes.Modified {
+			fmt.Fprintf(out, "~ %s %s: %q -> %q\n", lang, changeKey(change), change.Old, change.New)
+		}
+	}
+
+	return nil
+}
+
+// changeKey returns the key of the change, attributes are prefixed with "attributes.".
+func changeKey(change messages.Change) string {
+	if change.Attribute {
+		return "attributes." + change.Key
+	}
+
+	return change.Key
+}
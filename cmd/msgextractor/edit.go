@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+// quitCommand stops the editor, the entries that are already translated are kept.
+const quitCommand = ":q"
+
+// edit walks the untranslated and stale keys of a language and writes the translations that are entered back to the translation file.
+func edit(args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("edit", flag.ExitOnError)
+	dir := flags.String("dst", "", "The directory that contains the translation files.")
+	lang := flags.String("lang", "", "The language to translate, e.g. nl.")
+	noLock := flags.Bool("no-lock", false, "Do not lock the translations directory.")
+	defaultLang := flags.String("default-lang", "", "The language that is translated from, e.g. en.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor edit -dst ./translations -lang nl -default-lang en
+
+Edit shows every key that is untranslated (missing or empty) or stale (the same as the default language) in -lang,
+with the text of the default language, the description and a preview with the sample replacements from the metadata file.
+Type the translation and press enter to save it, press enter without a translation to skip the key and type :q to quit.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *lang == "" || *defaultLang == "" {
+		flags.Usage()
+		return fmt.Errorf("-lang and -default-lang are required")
+	}
+
+	languageID, err := messages.ParseLanguage(*lang)
+	if err != nil {
+		return err
+	}
+
+	defaultLanguageID, err := messages.ParseLanguage(*defaultLang)
+	if err != nil {
+		return fmt.Errorf("parsing default language: %w", err)
+	}
+
+	if !*noLock {
+		unlock, err := lockDir(*dir, log.Default())
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	fs := afero.NewOsFs()
+	parser := messages.NewParser(fs)
+
+	files, err := parser.TranslationFilesFromDir(*dir)
+	if err != nil {
+		return err
+	}
+
+	file, ok := files[languageID.String()]
+	if !ok {
+		return fmt.Errorf("language %s not found in translation files %q", languageID, sorted.Keys(files))
+	}
+
+	defaultFile, ok := files[defaultLanguageID.String()]
+	if !ok {
+		return fmt.Errorf("default language %s not found in translation files %q", defaultLanguageID, sorted.Keys(files))
+	}
+
+	store := messages.NewFileStore(fs, *dir)
+
+	translations, err := parser.MessagesFromFile(file)
+	if err != nil {
+		return fmt.Errorf("reading language file %s: %w", file, err)
+	}
+
+	defaultTranslations, err := parser.MessagesFromFile(defaultFile)
+	if err != nil {
+		return fmt.Errorf("reading default language file: %w", err)
+	}
+
+	metadata, err := parser.MetadataFromDir(*dir)
+	if err != nil {
+		return err
+	}
+
+	// Region overrides, like "color@GB", are specific to the default language and are not translated.
+	var keys []string
+	for _, key := range sorted.Keys(defaultTranslations.Messages) {
+		if _, _, ok := messages.SplitRegionKey(key); ok {
+			continue
+		}
+
+		value := translations.Messages[key]
+		if value == "" || value == defaultTranslations.Messages[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		fmt.Fprintf(out, "All keys of %s are translated.\n", languageID)
+		return nil
+	}
+
+	ctx, err := messages.WithLanguage(context.Background(), languageID.String())
+	if err != nil {
+		return err
+	}
+
+	defaultCtx, err := messages.WithLanguage(context.Background(), defaultLanguageID.String())
+	if err != nil {
+		return err
+	}
+
+	// The other languages are loaded leniently, so an error in another translation file does not reject the entries.
+	tr, err := messages.NewTranslator(fs, *dir, messages.WithLenientLoad())
+	if err != nil {
+		return err
+	}
+	if err := tr.LoadErrors()[languageID]; err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(in)
+	for i := 0; i < len(keys); i++ {
+		key := keys[i]
+
+		fmt.Fprintf(out, "\n[%d/%d] %s\n", i+1, len(keys), key)
+		if description := metadata[key].Description; description != "" {
+			fmt.Fprintf(out, "  description: %s\n", description)
+		}
+		fmt.Fprintf(out, "  %s: %s\n", defaultLanguageID, defaultTranslations.Messages[key])
+		fmt.Fprintf(out, "  preview: %s\n", tr.Preview(defaultCtx, messages.Key(key)))
+		if current := translations.Messages[key]; current != "" {
+			fmt.Fprintf(out, "  current: %s\n", current)
+		}
+		fmt.Fprintf(out, "%s> ", languageID)
+
+		if !scanner.Scan() {
+			break
+		}
+
+		input := strings.TrimSpace(scanner.Text())
+		if input == quitCommand {
+			break
+		}
+		if input == "" {
+			continue
+		}
+
+		previous, existed := translations.Messages[key]
+		translations.Messages[key] = input
+		if err := store.Save(languageID, translations); err != nil {
+			return err
+		}
+
+		// Load the translations to validate the entry, an invalid placeholder is reverted and the key is asked again.
+		// Only an error of the edited language rejects the entry.
+		updated, err := messages.NewTranslator(fs, *dir, messages.WithLenientLoad())
+		if err != nil {
+			return err
+		}
+
+		if err := updated.LoadErrors()[languageID]; err != nil {
+			fmt.Fprintf(out, "  invalid translation: %v\n", err)
+
+			if existed {
+				translations.Messages[key] = previous
+			} else {
+				delete(translations.Messages, key)
+			}
+
+			if err := store.Save(languageID, translations); err != nil {
+				return err
+			}
+
+			i--
+			continue
+		}
+
+		tr = updated
+		fmt.Fprintf(out, "  saved: %s\n", tr.Preview(ctx, messages.Key(key)))
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// The conditions that -fail-on can fail the run on.
+const (
+	// Keys that are found in the source code but are missing in a translation file, they are added by the run.
+	conditionMissingKeys = "missing-keys"
+	// Keys with an empty translation after the run.
+	conditionUntranslated = "untranslated"
+	// Keys in a translation file that are not found in the source code.
+	conditionUnused = "unused"
+	// Reserved keys that are used in the source code, see messages.WithReservedKeys.
+	conditionInvalidKeys = "invalid-keys"
+	// Issues that messages.Lint finds, it requires -default-lang.
+	conditionLint = "lint"
+)
+
+var conditions = []string{conditionMissingKeys, conditionUntranslated, conditionUnused, conditionInvalidKeys, conditionLint}
+
+// summary counts the conditions of a run and decides if the run fails.
+type summary struct {
+	failOn []string
+	counts map[string]int
+}
+
+// newSummary returns the summary for the comma separated -fail-on conditions.
+func newSummary(failOn string) (*summary, error) {
+	s := &summary{counts: make(map[string]int)}
+	if failOn == "" {
+		return s, nil
+	}
+
+	for _, condition := range strings.Split(failOn, ",") {
+		condition = strings.TrimSpace(condition)
+		if !slices.Contains(conditions, condition) {
+			return nil, fmt.Errorf("unknown -fail-on condition %q, use one of %s", condition, strings.Join(conditions, ","))
+		}
+
+		s.failOn = append(s.failOn, condition)
+	}
+
+	return s, nil
+}
+
+// add counts n occurrences of the condition.
+func (s *summary) add(condition string, n int) {
+	s.counts[condition] += n
+}
+
+// print writes the summary table of all conditions.
+func (s *summary) print(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONDITION\tCOUNT\tRESULT")
+	for _, condition := range conditions {
+		result := "ok"
+		switch {
+		case !slices.Contains(s.failOn, condition):
+			result = "ignored"
+		case s.counts[condition] > 0:
+			result = "FAIL"
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%s\n", condition, s.counts[condition], result)
+	}
+	w.Flush()
+}
+
+// err returns an error with the -fail-on conditions that occurred, nil when the run passes.
+func (s *summary) err() error {
+	var failed []string
+	for _, condition := range s.failOn {
+		if n := s.counts[condition]; n > 0 {
+			failed = append(failed, fmt.Sprintf("%s (%d)", condition, n))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("failed on %s", strings.Join(failed, ", "))
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+	"github.com/wvell/messages/catalog"
+	"github.com/wvell/messages/importer"
+)
+
+// importFormats are the formats of the import command, by the file extension that selects them.
+var importFormats = map[string]string{
+	".toml": "goi18n",
+	".yml":  "rails",
+	".yaml": "rails",
+	".php":  "laravel",
+}
+
+// importCatalogs converts the message files of other translation libraries and merges them into the translation files.
+func importCatalogs(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dir := flags.String("dst", "", "The directory that contains the translation files.")
+	format := flags.String("format", "", "The format of the files: goi18n, rails or laravel. By default the format of the file extension, JSON files need a format.")
+	lang := flags.String("lang", "", "The language of the files, by default the language in the file, e.g. nl for active.nl.toml or lang/nl/validation.php.")
+	keep := flags.Bool("keep", false, "Keep the existing translations when a key is in both, by default the imported translation is used.")
+	noLock := flags.Bool("no-lock", false, "Do not lock the translations directory.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor import -dst ./translations active.en.toml active.nl.toml
+
+Import converts the message files of other translation libraries and merges them into the translation files:
+
+  goi18n   go-i18n v2 message files in the TOML or JSON format, e.g. active.nl.toml.
+  rails    Rails YAML locale files, e.g. config/locales/nl.yml.
+  laravel  Laravel PHP and JSON lang files, e.g. lang/nl/validation.php and lang/nl.json.
+
+Placeholders become placeholders like :name and messages with plural forms become plural messages.
+Descriptions are added to the metadata file for the keys that have no metadata yet. Messages that are not converted exactly are printed.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("no files given")
+	}
+
+	if !*noLock {
+		unlock, err := lockDir(*dir, log.Default())
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	osFs := afero.NewOsFs()
+	c, err := catalog.Load(osFs, *dir)
+	if err != nil {
+		return err
+	}
+
+	strategy := messages.MergeTheirs()
+	if *keep {
+		strategy = messages.MergeOurs()
+	}
+
+	for _, filename := range flags.Args() {
+		data, err := afero.ReadFile(osFs, filename)
+		if err != nil {
+			return err
+		}
+
+		catalogs, err := importFile(filename, data, *format)
+		if err != nil {
+			return err
+		}
+
+		for _, imported := range catalogs {
+			if err := mergeImported(c, *lang, strategy, imported, filename, out); err != nil {
+				return err
+			}
+		}
+	}
+
+	return c.Write(osFs, *dir)
+}
+
+// importFile converts a file in the format, or in the format of the file extension if the format is empty.
+func importFile(filename string, data []byte, format string) ([]*importer.Catalog, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if format == "" {
+		format = importFormats[ext]
+	}
+
+	var (
+		imported *importer.Catalog
+		err      error
+	)
+	switch format {
+	case "goi18n":
+		imported, err = importer.GoI18n(filename, data)
+	case "rails":
+		return importer.RailsYAML(data)
+	case "laravel":
+		if ext == ".json" {
+			imported, err = importer.LaravelJSON(filename, data)
+		} else {
+			imported, err = importer.LaravelPHP(filename, data)
+		}
+	case "":
+		return nil, fmt.Errorf("file %s: unknown format, use -format", filename)
+	default:
+		return nil, fmt.Errorf("unknown format %q, use goi18n, rails or laravel", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []*importer.Catalog{imported}, nil
+}
+
+// mergeImported merges an imported catalog into the catalog, the language of the imported catalog is added when it is new.
+func mergeImported(c *catalog.Catalog, lang string, strategy messages.MergeStrategy, imported *importer.Catalog, filename string, out io.Writer) error {
+	language := imported.Language
+	if lang != "" {
+		var err error
+		language, err = messages.ParseLanguage(lang)
+		if err != nil {
+			return err
+		}
+	}
+	if language.Empty() {
+		return fmt.Errorf("file %s: no language in the file name, use -lang", filename)
+	}
+
+	src := catalog.New()
+	src.Languages[language] = imported.Messages
+	src.Metadata = imported.Metadata
+	if err := c.Merge(src, strategy); err != nil {
+		return fmt.Errorf("file %s: %w", filename, err)
+	}
+
+	fmt.Fprintf(out, "%s: imported %d messages from %s\n", language, len(imported.Messages.Messages), filename)
+	for _, warning := range imported.Warnings {
+		fmt.Fprintf(out, "%s: warning: %s\n", language, warning)
+	}
+
+	return nil
+}
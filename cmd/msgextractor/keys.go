@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/wvell/messages"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+// keys writes a key constants file in every package that uses translation keys.
+func keys(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("keys", flag.ExitOnError)
+	src := flags.String("src", ".", "The directory that contains the go source files. The search is recursive and includes all subdirectories with go files.")
+	rewrite := flags.Bool("rewrite", false, "Replace the key literals in the calls with the generated constants.")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), `Usage: msgextractor keys -src . [-rewrite]
+
+Keys writes a %s file with typed constants for the translation keys that are used in every package, e.g.:
+
+	//go:generate msgextractor keys -src .
+
+With -rewrite the string literals that are passed as a key are replaced with the constants.
+Keys with a casing directive, e.g. "button.save!upper", are not replaced.
+
+Flags:
+`, messages.KeyConstantsFile)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	pkgs, err := messages.PackageKeysFromSourceCode(context.Background(), *src)
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range pkgs {
+		generated, err := pkg.Generate()
+		if err != nil {
+			return fmt.Errorf("package %s: %w", pkg.Dir, err)
+		}
+
+		file := filepath.Join(pkg.Dir, messages.KeyConstantsFile)
+		if err := os.WriteFile(file, generated, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s: %d keys\n", file, len(pkg.Keys))
+
+		if !*rewrite {
+			continue
+		}
+
+		files, err := pkg.Rewrite()
+		if err != nil {
+			return err
+		}
+
+		for _, file := range sorted.Keys(files) {
+			if err := os.WriteFile(file, files[file], 0o644); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%s: replaced key literals\n", file)
+		}
+	}
+
+	return nil
+}
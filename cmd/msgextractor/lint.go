@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+)
+
+// lint prints the issues in the translation files and fails when there are issues.
+func lint(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	dir := flags.String("dst", "", "The directory that contains the translation files.")
+	defaultLang := flags.String("default-lang", "", "The default language, its messages define the placeholders of a key, e.g. en.")
+	src := flags.String("src", "", "The directory with the go source files. Attributes that the source code does not pass as a constant :attribute replacement are reported as unused warnings, they do not fail the lint.")
+	placeholders := flags.String("placeholders", "", "Comma separated placeholders that are available in every message, e.g. the placeholders of a replacement provider.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor lint -dst ./translations -default-lang en
+
+Lint reports placeholders that the message of the default language does not have, they are replaced with nothing at runtime,
+placeholders with the name of an attribute in the same file and suspicious placeholders, like the :user in :user_name.
+Attributes are reported when they are unused, missing in a language or have their name as value. It exits with an error when there are issues.
+With -src the unused attributes are warnings, attributes that the code passes dynamically, e.g. the field names of a validator, are not found.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *defaultLang == "" {
+		flags.Usage()
+		return fmt.Errorf("-default-lang is required")
+	}
+
+	lang, err := messages.ParseLanguage(*defaultLang)
+	if err != nil {
+		return err
+	}
+
+	var opts []messages.LintOpt
+	if *placeholders != "" {
+		opts = append(opts, messages.WithLintPlaceholders(strings.Split(*placeholders, ",")...))
+	}
+
+	if *src != "" {
+		extraction, err := messages.ExtractFromSourceCodeCtx(context.Background(), *src)
+		if err != nil {
+			return fmt.Errorf("error reading attributes from src: %w", err)
+		}
+
+		opts = append(opts, messages.WithLintAttributes(extraction.Attributes...))
+	}
+
+	issues, err := messages.Lint(afero.NewOsFs(), *dir, lang, opts...)
+	if err != nil {
+		return err
+	}
+
+	if *src != "" {
+		var warnings []messages.LintIssue
+		issues, warnings = splitLintWarnings(issues)
+		for _, warning := range warnings {
+			fmt.Fprintf(out, "warning: %s\n", warning)
+		}
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintln(out, issue)
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("found %d lint issue(s)", len(issues))
+	}
+
+	return nil
+}
+
+// splitLintWarnings returns the unused attributes as warnings, they are found with the attributes that the source code passes as a
+// constant and the code can pass attributes dynamically, e.g. the field names of a validator.
+func splitLintWarnings(all []messages.LintIssue) (issues, warnings []messages.LintIssue) {
+	for _, issue := range all {
+		if issue.Rule == messages.LintUnusedAttribute {
+			warnings = append(warnings, issue)
+			continue
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues, warnings
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+)
+
+// lockName is the name of the lock file in the translations directory. It is hidden, so it is not read as a translation file,
+// and it is removed when the lock is released.
+const lockName = ".msgextractor.lock"
+
+// lockDir takes an advisory lock on the translations directory, so concurrent runs, e.g. parallel CI jobs, do not interleave
+// their writes. It waits for the lock when another run holds it. Call the returned function to release the lock.
+func lockDir(dir string, logger *log.Logger) (func(), error) {
+	path := filepath.Join(dir, lockName)
+	unlock, err := lockFile(path, func() {
+		logger.Printf("waiting for %s, another msgextractor run is writing the translations", path)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("locking %s: %w", dir, err)
+	}
+
+	return unlock, nil
+}
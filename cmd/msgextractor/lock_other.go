@@ -0,0 +1,72 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile locks the file with flock, waiting is called when another process holds the lock.
+// The lock is released by the operating system when the process exits, so a crashed run does not leave a stale lock.
+// The file is removed when the lock is released, a run that waited on the removed file locks a new file.
+func lockFile(path string, waiting func()) (func(), error) {
+	waited := false
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+		if err != nil {
+			return nil, err
+		}
+
+		err = flock(f, syscall.LOCK_EX|syscall.LOCK_NB)
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			if !waited {
+				waiting()
+				waited = true
+			}
+			err = flock(f, syscall.LOCK_EX)
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		// The run that held the lock removed the file, lock the file that is at the path now.
+		if !lockedPath(f, path) {
+			f.Close()
+			continue
+		}
+
+		return func() {
+			_ = os.Remove(path)
+			_ = flock(f, syscall.LOCK_UN)
+			f.Close()
+		}, nil
+	}
+}
+
+// lockedPath reports if the locked file is still the file at the path.
+func lockedPath(f *os.File, path string) bool {
+	locked, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(locked, current)
+}
+
+// flock calls syscall.Flock and retries when it is interrupted by a signal.
+func flock(f *os.File, how int) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
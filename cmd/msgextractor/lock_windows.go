@@ -0,0 +1,41 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// lockFile creates the file exclusively and keeps it open, waiting is called when another process holds the lock.
+// The file is removed when the lock is released. Windows does not remove a file that another process has open, so a file
+// that can be removed is a stale lock of a run that crashed, it is removed and the lock is taken.
+func lockFile(path string, waiting func()) (func(), error) {
+	waited := false
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			return func() {
+				f.Close()
+				_ = os.Remove(path)
+			}, nil
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		if os.Remove(path) == nil {
+			continue
+		}
+
+		if !waited {
+			waiting()
+			waited = true
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -1,25 +1,118 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/afero"
 	"github.com/wvell/messages"
-	"golang.org/x/exp/maps"
+	"github.com/wvell/messages/catalog"
+	"github.com/wvell/messages/internal/sorted"
 	"golang.org/x/exp/slices"
 )
 
+// options holds the command line options.
+type options struct {
+	srcDir          string
+	translationsDir string
+	defaultLang     string
+	overwrite       bool
+	// Move the removed translations to the archive of the language instead of deleting them.
+	archive bool
+	// Print progress of the extraction to stderr.
+	progress bool
+	// Suppress all informational output, only errors are printed.
+	quiet bool
+	// Search hidden directories and directories ignored by .gitignore.
+	includeHidden bool
+	noGitignore   bool
+	// Follow symlinked directories in src.
+	followSymlinks bool
+	// Maximum duration of the extraction, zero means no limit.
+	timeout time.Duration
+	// Do not lock the translations directory.
+	noLock bool
+	// Comma separated conditions that fail the run, see conditions.
+	failOn string
+	// Extractor options, see the messages.ExtractOpt functions.
+	keyTypes       string
+	excludes       string
+	tags           string
+	tests          bool
+	followWrappers bool
+	reportErrors   bool
+	reservedKeys   string
+	// Glob patterns of non-Go files that are searched for keys before they are removed.
+	assetPatterns string
+	assetsDir     string
+}
+
+// command is a subcommand of msgextractor, action describes what it does in the error message, e.g. "previewing message".
+type command struct {
+	run    func(args []string) error
+	action string
+}
+
+// commands are the subcommands by name, without a subcommand msgextractor extracts the keys.
+// import-goi18n is the name of import before it imported other formats, it is kept for existing scripts.
+var commands = map[string]command{
+	"preview":            {func(args []string) error { return preview(args, os.Stdout) }, "previewing message"},
+	"diff":               {func(args []string) error { return diff(args, os.Stdout) }, "comparing translations"},
+	"lint":               {func(args []string) error { return lint(args, os.Stdout) }, "linting translations"},
+	"report":             {func(args []string) error { return report(args, os.Stdout) }, "reporting key usage"},
+	"mergetool":          {func(args []string) error { return mergetool(args, os.Stderr) }, "merging translations"},
+	"codes":              {func(args []string) error { return codes(args, os.Stdout) }, "exporting error codes"},
+	"rename-placeholder": {func(args []string) error { return renamePlaceholder(args, os.Stdout) }, "renaming placeholder"},
+	"keys":               {func(args []string) error { return keys(args, os.Stdout) }, "generating key constants"},
+	"purge":              {func(args []string) error { return purge(args, os.Stdout) }, "purging archived translations"},
+	"pack":               {func(args []string) error { return pack(args, os.Stdout) }, "packing translations"},
+	"import":             {func(args []string) error { return importCatalogs(args, os.Stdout) }, "importing translations"},
+	"import-goi18n":      {func(args []string) error { return importCatalogs(args, os.Stdout) }, "importing translations"},
+	"edit":               {func(args []string) error { return edit(args, os.Stdin, os.Stdout) }, "editing translations"},
+}
+
 func main() {
-	var srcDir, translationDir, defaultLang string
-	var overwrite bool
-	flag.StringVar(&srcDir, "src", ".", "The directory that contains the go source files where the translations are used. The search is recursive and includes all subdirectories with go files.")
-	flag.StringVar(&translationDir, "dst", "", "The directory that contains the translation files.")
-	flag.StringVar(&defaultLang, "default-lang", "", "Provide a default language to use when adding new translations. If not provided, new translations will be added as empty strings.")
-	flag.BoolVar(&overwrite, "remove", false, "Remove will remove all translations in the translation files that have not been found in src. Transformers are never removed.")
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				log.Fatalf("error %s: %v", cmd.action, err)
+			}
+
+			return
+		}
+	}
+
+	var opts options
+	flag.StringVar(&opts.srcDir, "src", ".", "The directory that contains the go source files where the translations are used. The search is recursive and includes all subdirectories with go files.")
+	flag.StringVar(&opts.translationsDir, "dst", "", "The directory that contains the translation files.")
+	flag.StringVar(&opts.defaultLang, "default-lang", "", "Provide a default language to use when adding new translations. If not provided, new translations will be added as empty strings.")
+	flag.BoolVar(&opts.overwrite, "remove", false, "Remove will remove all translations in the translation files that have not been found in src. Transformers are never removed.")
+	flag.BoolVar(&opts.archive, "archive", false, "Move the translations that -remove removes to the archive of the language, e.g. en_archive.json, instead of deleting them. Use \"msgextractor purge\" to delete them for good.")
+	flag.BoolVar(&opts.progress, "progress", false, "Print the progress of the extraction (directories, packages loaded and keys found) to stderr.")
+	flag.BoolVar(&opts.quiet, "quiet", false, "Suppress all informational output. Only errors are printed.")
+	flag.BoolVar(&opts.includeHidden, "include-hidden", false, "Also search hidden directories (directories that start with a dot) in src.")
+	flag.BoolVar(&opts.noGitignore, "no-gitignore", false, "Also search directories in src that are ignored by a .gitignore file.")
+	flag.BoolVar(&opts.followSymlinks, "follow-symlinks", false, "Follow symlinked directories in src. Every directory is searched at most once.")
+	flag.StringVar(&opts.keyTypes, "key-types", "", "Comma separated fully qualified types that are translation keys next to github.com/wvell/messages.Key, e.g. example.com/i18n.MsgID.")
+	flag.StringVar(&opts.excludes, "exclude", "", "Comma separated glob patterns of directories in src to skip, e.g. mocks,internal/generated/*.")
+	flag.StringVar(&opts.tags, "tags", "", "Comma separated build tags to use when loading the go source files.")
+	flag.BoolVar(&opts.tests, "tests", false, "Also extract translation keys from _test.go files.")
+	flag.BoolVar(&opts.followWrappers, "follow-wrappers", false, "Also extract keys passed as a string to functions that use the string as a translation key.")
+	flag.BoolVar(&opts.reportErrors, "report-errors", false, "Print package errors, like compile errors, as warnings instead of failing.")
+	flag.StringVar(&opts.reservedKeys, "reserved-keys", strings.Join(messages.DefaultReservedKeys, ","), "Comma separated keys that can not be used as a translation key. They are reported as warnings and left out of the translation files, attributes is always reserved.")
+	flag.StringVar(&opts.assetPatterns, "assets", "", "Comma separated glob patterns of non-Go files (templates, SQL, YAML) that are searched for keys before -remove removes them, e.g. "+strings.Join(messages.DefaultAssetPatterns, ",")+". Keys that are found are reported and kept. By default no files are searched.")
+	flag.StringVar(&opts.assetsDir, "assets-dir", "", "The directory that is searched for the -assets files. Defaults to src.")
+	flag.BoolVar(&opts.noLock, "no-lock", false, "Do not lock the translations directory. By default a run waits until other runs that write to dst are done.")
+	flag.StringVar(&opts.failOn, "fail-on", "", "Comma separated conditions that fail the run after the translation files are updated: "+strings.Join(conditions, ",")+". A summary of all conditions is printed. lint requires -default-lang.")
+	flag.DurationVar(&opts.timeout, "timeout", 0, "Abort the extraction after the given duration, e.g. 5m. The translation files are not updated when the extraction is aborted.")
 	flag.Usage = func() {
 		fmt.Print(`Usage: msgextractor -src ./ -dst ./translations
 
@@ -30,6 +123,8 @@ Add an empty translation file to the translation directory to add new translatio
 
     $ touch ./translations/en.json
 
+Use "msgextractor preview -h" to preview a formatted message and "msgextractor edit -h" to translate the untranslated keys of a language.
+
 Flags:
 `)
 
@@ -38,42 +133,144 @@ Flags:
 
 	flag.Parse()
 
-	err := processTranslations(srcDir, translationDir, defaultLang, overwrite)
+	// Stop the extraction on interrupt.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	err := processTranslations(ctx, opts)
 	if err != nil {
 		log.Fatalf("error processing translations: %v", err)
 	}
 }
 
-func processTranslations(srcDir, translationsDir, defaultLang string, overwrite bool) error {
-	translationKeysFromSrcDir, err := messages.TranslationKeysFromSourceCode(srcDir)
+func processTranslations(ctx context.Context, opts options) error {
+	// Informational output is written to the logger, which is discarded in quiet mode.
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	if opts.quiet {
+		logger.SetOutput(io.Discard)
+	}
+
+	summary, err := newSummary(opts.failOn)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(summary.failOn, conditionLint) && opts.defaultLang == "" {
+		return fmt.Errorf("-fail-on %s requires -default-lang", conditionLint)
+	}
+
+	var extractOpts []messages.ExtractOpt
+	if opts.progress && !opts.quiet {
+		extractOpts = append(extractOpts, messages.WithProgress(func(p messages.Progress) {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s: %d packages loaded, %d keys found\n", p.DirsDone, p.DirsTotal, p.Dir, p.Packages, p.Keys)
+		}))
+	}
+
+	if opts.includeHidden {
+		extractOpts = append(extractOpts, messages.WithHiddenDirs())
+	}
+	if opts.noGitignore {
+		extractOpts = append(extractOpts, messages.WithoutGitignore())
+	}
+	if opts.followSymlinks {
+		extractOpts = append(extractOpts, messages.WithFollowSymlinks())
+	}
+	if opts.keyTypes != "" {
+		extractOpts = append(extractOpts, messages.WithKeyTypes(strings.Split(opts.keyTypes, ",")...))
+	}
+	if opts.excludes != "" {
+		extractOpts = append(extractOpts, messages.WithExcludes(strings.Split(opts.excludes, ",")...))
+	}
+	if opts.tags != "" {
+		extractOpts = append(extractOpts, messages.WithBuildTags(strings.Split(opts.tags, ",")...))
+	}
+	if opts.tests {
+		extractOpts = append(extractOpts, messages.WithTests())
+	}
+	if opts.followWrappers {
+		extractOpts = append(extractOpts, messages.WithFollowWrappers())
+	}
+	if opts.reportErrors {
+		extractOpts = append(extractOpts, messages.WithReportErrors(func(err error) {
+			logger.Printf("warning: %v", err)
+		}))
+	}
+
+	if opts.reservedKeys != "" {
+		extractOpts = append(extractOpts, messages.WithReservedKeys(strings.Split(opts.reservedKeys, ",")...))
+	} else {
+		extractOpts = append(extractOpts, messages.WithReservedKeys())
+	}
+
+	extraction, err := messages.ExtractFromSourceCodeCtx(ctx, opts.srcDir, extractOpts...)
 	if err != nil {
 		return fmt.Errorf("error reading translations from src: %w", err)
 	}
+	for _, invalid := range extraction.InvalidKeys {
+		logger.Printf("warning: %v", invalid)
+	}
+	summary.add(conditionInvalidKeys, len(extraction.InvalidKeys))
+	translationKeysFromSrcDir := extraction.Keys
+
+	// The keys are looked up for every translation of every language, a set keeps that linear on large catalogs.
+	usedKeys := make(map[string]struct{}, len(translationKeysFromSrcDir))
+	for _, key := range translationKeysFromSrcDir {
+		usedKeys[key] = struct{}{}
+	}
 
-	parser := messages.NewParser(afero.NewOsFs())
+	if !opts.noLock {
+		unlock, err := lockDir(opts.translationsDir, logger)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	osFs := afero.NewOsFs()
+	parser := messages.NewParser(osFs)
 
-	files, err := parser.TranslationFilesFromDir(translationsDir)
+	files, err := parser.TranslationFilesFromDir(opts.translationsDir)
 	if err != nil {
 		return err
 	}
 
 	if len(files) == 0 {
-		return fmt.Errorf("there are no translation files in dir %s, create an empty file to write translations", translationsDir)
+		return fmt.Errorf("there are no translation files in dir %s, create an empty file to write translations", opts.translationsDir)
+	}
+
+	c, err := catalog.Load(osFs, opts.translationsDir)
+	if err != nil {
+		return err
+	}
+
+	// The files by language, for the log messages.
+	filesByLanguage := make(map[messages.LanguageID]string, len(files))
+	for languageID, file := range files {
+		lang, err := messages.ParseLanguage(languageID)
+		if err != nil {
+			return err
+		}
+		filesByLanguage[lang] = file
 	}
 
 	defaultTranslations := &messages.RawMessages{
 		Messages:   make(map[string]string),
 		Attributes: make(map[string]string),
 	}
-	if defaultLang != "" {
-		defaultLanguageID, err := messages.ParseLanguage(defaultLang)
+	if opts.defaultLang != "" {
+		defaultLanguageID, err := messages.ParseLanguage(opts.defaultLang)
 		if err != nil {
 			log.Fatalf("error parsing default language: %v", err)
 		}
 
 		// Check if the default language is a translation file.
 		if _, ok := files[defaultLanguageID.String()]; !ok {
-			return fmt.Errorf("default language %s not found in translation files %q", defaultLanguageID.String(), maps.Keys(files))
+			return fmt.Errorf("default language %s not found in translation files %q", defaultLanguageID.String(), sorted.Keys(files))
 		}
 
 		defaultTranslations, err = parser.MessagesFromFile(files[defaultLanguageID.String()])
@@ -82,45 +279,112 @@ func processTranslations(srcDir, translationsDir, defaultLang string, overwrite
 		}
 	}
 
-	// Loop over all translation files and update them.
-	for _, file := range files {
-		existingTranslations, err := parser.MessagesFromFile(file)
+	// Keys that are referenced from non-Go files are never removed.
+	var assetReferences map[string][]messages.AssetReference
+	if opts.overwrite && opts.assetPatterns != "" {
+		assetReferences, err = unusedKeysInAssets(c, usedKeys, opts)
 		if err != nil {
-			return fmt.Errorf("reading language file %s: %w", file, err)
+			return err
 		}
+	}
+
+	store := messages.NewFileStore(osFs, opts.translationsDir)
+
+	// Loop over all languages and update their translations, they are written at the end.
+	// Languages and keys are processed in sorted order, so repeated runs log the same output.
+	for _, lang := range c.LanguageIDs() {
+		file := filesByLanguage[lang]
+		existingTranslations := c.Languages[lang]
 
 		// Remove existing translations that are not present in the src translations.
-		if overwrite {
-			for key := range existingTranslations.Messages {
-				if slices.Contains(translationKeysFromSrcDir, key) {
+		if opts.overwrite {
+			var removed []string
+			for _, key := range catalog.SortedKeys(existingTranslations) {
+				// Region overrides like "color@GB" and variants like "color#exp42" are kept as long as the key itself is used.
+				if _, ok := usedKeys[catalog.BaseKey(key)]; ok {
 					continue
 				}
 
+				if references, ok := assetReferences[key]; ok {
+					logger.Printf("translation %q in file %s is not found in source code but is referenced in %s, it is not removed", key, file, references[0])
+					continue
+				}
+
+				removed = append(removed, key)
+			}
+			summary.add(conditionUnused, len(removed))
+
+			if opts.archive {
+				if err := store.Archive(lang, existingTranslations, removed...); err != nil {
+					return fmt.Errorf("archiving %s: %w", file, err)
+				}
+			}
+
+			for _, key := range removed {
 				delete(existingTranslations.Messages, key)
 			}
 		} else {
 			// Output all translations that are in the translation file but not in the source code.
-			for key := range existingTranslations.Messages {
-				if slices.Contains(translationKeysFromSrcDir, key) {
+			for _, key := range catalog.SortedKeys(existingTranslations) {
+				if _, ok := usedKeys[catalog.BaseKey(key)]; ok {
 					continue
 				}
 
-				log.Printf("translation %q is present in file %s but not found in source code, use -remove to remove this translation", key, file)
+				logger.Printf("translation %q is present in file %s but not found in source code, use -remove to remove this translation", key, file)
+				summary.add(conditionUnused, 1)
 			}
 		}
 
 		for _, key := range translationKeysFromSrcDir {
-			// If the key already exists we do nothing.
+			// If the key already exists, as a message or as a plural message, we do nothing.
 			if _, ok := existingTranslations.Messages[key]; ok {
 				continue
 			}
+			if _, ok := existingTranslations.Messages[messages.PluralKey(key, messages.PluralOther)]; ok {
+				continue
+			}
+
+			summary.add(conditionMissingKeys, 1)
+
+			// The forms of a plural message of the default language are added as a plural message.
+			if _, ok := defaultTranslations.Messages[messages.PluralKey(key, messages.PluralOther)]; ok {
+				for _, form := range messages.PluralForms {
+					if value, ok := defaultTranslations.Messages[messages.PluralKey(key, form)]; ok {
+						existingTranslations.Messages[messages.PluralKey(key, form)] = value
+					}
+				}
+
+				continue
+			}
 
 			// Add the key to the existing translations and use the value from the default translation if present.
 			existingTranslations.Messages[key] = defaultTranslations.Messages[key]
 		}
 
+		for _, value := range existingTranslations.Messages {
+			if value == "" {
+				summary.add(conditionUntranslated, 1)
+			}
+		}
+
+		// Add the attributes that are used in the source code but missing in the file.
+		// The value of the default language is used if present, otherwise the attribute name without underscores.
+		// An empty value would replace the attribute with an empty string in the translated message.
+		for _, attribute := range extraction.Attributes {
+			if _, ok := existingTranslations.Attributes[attribute]; ok {
+				continue
+			}
+
+			value, ok := defaultTranslations.Attributes[attribute]
+			if !ok || value == "" {
+				value = strings.ReplaceAll(attribute, "_", " ")
+			}
+
+			existingTranslations.Attributes[attribute] = value
+		}
+
 		// If there is a default language we add the missing transformers.
-		if defaultLang != "" {
+		if opts.defaultLang != "" {
 			for key, transformer := range defaultTranslations.Attributes {
 				if _, ok := existingTranslations.Attributes[key]; !ok {
 					// If the transformer is missing completely we add it.
@@ -129,17 +393,66 @@ func processTranslations(srcDir, translationsDir, defaultLang string, overwrite
 			}
 		}
 
-		// Write the translations back to the file.
-		content, err := json.MarshalIndent(existingTranslations, "", "  ")
+	}
+
+	// Write the translations back to the files.
+	if err := c.Write(osFs, opts.translationsDir); err != nil {
+		return err
+	}
+
+	if len(summary.failOn) == 0 {
+		return nil
+	}
+
+	if opts.defaultLang != "" {
+		defaultLanguageID, err := messages.ParseLanguage(opts.defaultLang)
 		if err != nil {
-			return fmt.Errorf("marshalling translations: %w", err)
+			return err
 		}
 
-		err = os.WriteFile(file, content, os.ModePerm)
+		issues, err := messages.Lint(afero.NewOsFs(), opts.translationsDir, defaultLanguageID, messages.WithLintAttributes(extraction.Attributes...))
 		if err != nil {
-			return fmt.Errorf("writing translations: %w", err)
+			return err
+		}
+
+		issues, warnings := splitLintWarnings(issues)
+		for _, warning := range warnings {
+			logger.Printf("lint warning: %s", warning)
+		}
+		for _, issue := range issues {
+			logger.Printf("lint: %s", issue)
 		}
+		summary.add(conditionLint, len(issues))
+	}
+
+	// The summary is part of the result of the run, it is printed in quiet mode as well.
+	summary.print(os.Stderr)
+
+	return summary.err()
+}
+
+// unusedKeysInAssets searches the asset files for the keys in the catalog that are not used in the source code.
+func unusedKeysInAssets(c *catalog.Catalog, usedKeys map[string]struct{}, opts options) (map[string][]messages.AssetReference, error) {
+	var unused []string
+	seen := make(map[string]struct{})
+	for _, lang := range c.LanguageIDs() {
+		for _, key := range catalog.SortedKeys(c.Languages[lang]) {
+			if _, ok := usedKeys[catalog.BaseKey(key)]; ok {
+				continue
+			}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+
+			seen[key] = struct{}{}
+			unused = append(unused, key)
+		}
+	}
+
+	dir := opts.assetsDir
+	if dir == "" {
+		dir = opts.srcDir
 	}
 
-	return nil
+	return messages.KeysInAssets(dir, strings.Split(opts.assetPatterns, ","), unused)
 }
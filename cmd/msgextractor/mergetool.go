@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+)
+
+// mergetool merges a translation file like a git merge driver: the changes of the current and the other branch since their
+// common ancestor are merged per key and written to the file of the current branch.
+func mergetool(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("mergetool", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor mergetool %O %A %B
+
+Mergetool is a git merge driver for translation files. It merges the keys of the current branch (%A) and the other branch (%B)
+since their common ancestor (%O) and writes the result to %A. A key that is changed differently in both branches is a conflict,
+the conflicts are printed and the value of the current branch is kept. Configure it with:
+
+    git config merge.messages.driver "msgextractor mergetool %O %A %B"
+    echo "translations/*.json merge=messages" >> .gitattributes
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 3 {
+		flags.Usage()
+		return fmt.Errorf("the ancestor, current and other file are required")
+	}
+
+	fs := afero.NewOsFs()
+	parser := messages.NewParser(fs)
+
+	var catalogs [3]*messages.RawMessages
+	for i, file := range flags.Args() {
+		var err error
+		catalogs[i], err = parser.MessagesFromFile(file)
+		if err != nil {
+			return fmt.Errorf("reading file %s: %w", file, err)
+		}
+	}
+
+	merged, conflicts := messages.Merge3(catalogs[0], catalogs[1], catalogs[2])
+
+	content, err := merged.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	current := flags.Arg(1)
+	mode := os.FileMode(0o644)
+	if stat, err := fs.Stat(current); err == nil {
+		mode = stat.Mode().Perm()
+	}
+
+	if err := afero.WriteFile(fs, current, content, mode); err != nil {
+		return fmt.Errorf("writing merged file: %w", err)
+	}
+
+	for _, conflict := range conflicts {
+		key := conflict.Key
+		if conflict.Attribute {
+			key = "attributes." + key
+		}
+
+		switch {
+		case conflict.OursDeleted:
+			fmt.Fprintf(out, "conflict %s: deleted in current branch, changed to %q in other branch\n", key, conflict.Theirs)
+		case conflict.TheirsDeleted:
+			fmt.Fprintf(out, "conflict %s: changed to %q in current branch, deleted in other branch\n", key, conflict.Ours)
+		default:
+			fmt.Fprintf(out, "conflict %s: %q in current branch, %q in other branch\n", key, conflict.Ours, conflict.Theirs)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%d conflicts", len(conflicts))
+	}
+
+	return nil
+}
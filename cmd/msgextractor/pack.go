@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+)
+
+// pack writes the translations of a language as a compressed language pack.
+func pack(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("pack", flag.ExitOnError)
+	dir := flags.String("dst", "", "The directory that contains the translation files.")
+	lang := flags.String("lang", "", "The language of the pack, e.g. nl or en-GB.")
+	file := flags.String("out", "", "The file of the pack, <lang>"+messages.PackSuffix+" when empty.")
+	version := flags.String("version", "", "The version of the pack, e.g. the release. The version of the translation files when empty.")
+	base := flags.String("base", "", "A previous pack of the language, the delta since that pack is also written.")
+	deltaFile := flags.String("delta", "", "The file of the delta, <lang>.<base version>"+messages.DeltaSuffix+" next to the pack when empty.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor pack -dst ./translations -lang nl [-out nl.msgpack.gz] [-version v1.2.3] [-base old/nl.msgpack.gz [-delta nl.v1.2.2.msgdelta.gz]]
+
+Pack writes the translations of a language as a single compressed artifact with a checksum and a version,
+e.g. to publish on a CDN. Load the packs with messages.NewHTTPLoader or messages.NewPackLoader.
+With -base the delta since the base pack is also written, messages.NewHTTPLoader applies deltas to the pack it loaded before.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *lang == "" {
+		flags.Usage()
+		return fmt.Errorf("-lang is required")
+	}
+
+	languageID, err := messages.ParseLanguage(*lang)
+	if err != nil {
+		return err
+	}
+
+	fs := afero.NewOsFs()
+	languages, storeVersion, err := messages.NewFileStore(fs, *dir).Load(context.Background(), messages.Version{})
+	if err != nil {
+		return err
+	}
+
+	raw, ok := languages[languageID]
+	if !ok {
+		return fmt.Errorf("no translations for language %s", languageID)
+	}
+
+	if *version != "" {
+		storeVersion.ETag = *version
+	}
+
+	p := &messages.Pack{Language: languageID, Messages: raw, Version: storeVersion}
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if *file == "" {
+		*file = languageID.String() + messages.PackSuffix
+	}
+
+	if err := afero.WriteFile(fs, *file, data, 0o644); err != nil {
+		return fmt.Errorf("writing pack: %w", err)
+	}
+
+	fmt.Fprintf(out, "%s: %d messages, version %s, sha256 %s\n", *file, len(raw.Messages), storeVersion.ETag, p.Checksum)
+
+	if *base == "" {
+		return nil
+	}
+
+	return writeDelta(fs, *base, *deltaFile, *file, p, out)
+}
+
+// writeDelta writes the delta from the pack in the base file to the pack. The delta is written next to the pack file when file is empty.
+func writeDelta(fs afero.Fs, baseFile, file, packFile string, p *messages.Pack, out io.Writer) error {
+	data, err := afero.ReadFile(fs, baseFile)
+	if err != nil {
+		return fmt.Errorf("reading base pack: %w", err)
+	}
+
+	base, err := messages.UnmarshalPack(data)
+	if err != nil {
+		return fmt.Errorf("base pack %s: %w", baseFile, err)
+	}
+
+	delta, err := messages.NewDelta(base, p)
+	if err != nil {
+		return err
+	}
+
+	data, err = delta.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if file == "" {
+		file = filepath.Join(filepath.Dir(packFile), p.Language.String()+"."+base.Version.ETag+messages.DeltaSuffix)
+	}
+
+	if err := afero.WriteFile(fs, file, data, 0o644); err != nil {
+		return fmt.Errorf("writing delta: %w", err)
+	}
+
+	fmt.Fprintf(out, "%s: %d changed and %d removed messages since version %s, sha256 %s\n", file, len(delta.Set.Messages), len(delta.DeletedMessages), base.Version.ETag, delta.Checksum)
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+)
+
+// preview prints the message of a key, formatted with the sample replacements from the metadata file.
+func preview(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("preview", flag.ExitOnError)
+	dir := flags.String("dst", "", "The directory that contains the translation files and the metadata file.")
+	key := flags.String("key", "", "The key of the message to preview, e.g. welcome.login.")
+	lang := flags.String("lang", "", "The language of the message, e.g. nl or en-GB.")
+	defaultLang := flags.String("default-lang", "", "The language that is used when the message is not translated in -lang.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor preview -dst ./translations -key welcome.login -lang nl
+
+Preview prints the message of the key in the language, formatted with the sample replacements that are declared in the metadata file.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *key == "" || *lang == "" {
+		flags.Usage()
+		return fmt.Errorf("-key and -lang are required")
+	}
+
+	var opts []messages.Opt
+	if *defaultLang != "" {
+		defaultLanguage, err := messages.ParseLanguage(*defaultLang)
+		if err != nil {
+			return fmt.Errorf("parsing default language: %w", err)
+		}
+
+		opts = append(opts, messages.WithDefaultLanguage(defaultLanguage))
+	}
+
+	tr, err := messages.NewTranslator(afero.NewOsFs(), *dir, opts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := messages.WithLanguage(context.Background(), *lang)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, tr.Preview(ctx, messages.Key(*key)))
+	return err
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+// purge deletes the archived translations for good.
+func purge(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("purge", flag.ExitOnError)
+	dir := flags.String("dst", "", "The directory that contains the translation files.")
+	noLock := flags.Bool("no-lock", false, "Do not lock the translations directory.")
+	keys := flags.String("keys", "", "Comma separated keys to purge. All archived translations are purged when empty.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor purge -dst ./translations [-keys welcome.login,bye]
+
+Purge deletes the translations that are archived by "msgextractor -remove -archive" from the archive of every language.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var purgeKeys []string
+	if *keys != "" {
+		purgeKeys = strings.Split(*keys, ",")
+	}
+
+	if !*noLock {
+		unlock, err := lockDir(*dir, log.Default())
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	fs := afero.NewOsFs()
+	store := messages.NewFileStore(fs, *dir)
+
+	files, err := messages.NewParser(fs).TranslationFilesFromDir(*dir)
+	if err != nil {
+		return err
+	}
+
+	for _, languageID := range sorted.Keys(files) {
+		lang, err := messages.ParseLanguage(languageID)
+		if err != nil {
+			return err
+		}
+
+		purged, err := store.Purge(lang, purgeKeys...)
+		if err != nil {
+			return err
+		}
+
+		if purged > 0 {
+			fmt.Fprintf(out, "%s: purged %d archived translations\n", languageID, purged)
+		}
+	}
+
+	return nil
+}
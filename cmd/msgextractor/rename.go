@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+	"github.com/wvell/messages/catalog"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+// renamePlaceholder renames a placeholder of a key in every language and reports the calls that pass the old replacement.
+func renamePlaceholder(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("rename-placeholder", flag.ExitOnError)
+	dir := flags.String("dst", "", "The directory that contains the translation files.")
+	noLock := flags.Bool("no-lock", false, "Do not lock the translations directory.")
+	src := flags.String("src", ".", "The directory that contains the go source files that are searched for calls that pass the old replacement.")
+	key := flags.String("key", "", "The key of the message, e.g. welcome.login.")
+	from := flags.String("from", "", "The name of the placeholder, e.g. user.")
+	to := flags.String("to", "", "The new name of the placeholder, e.g. username.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor rename-placeholder -dst ./translations -key welcome.login -from user -to username
+
+Rename-placeholder renames the placeholder in the message of the key in every language, region overrides and variants included,
+and in the samples and placeholder types of the key in the metadata. Nothing is renamed when a message already has the new placeholder.
+The case of the first letter is kept, so :User becomes :Username. The calls in -src that still pass the old replacement are reported,
+because a replacement that is not passed is formatted as an empty string.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *key == "" || *from == "" || *to == "" {
+		flags.Usage()
+		return fmt.Errorf("-key, -from and -to are required")
+	}
+
+	if !*noLock {
+		unlock, err := lockDir(*dir, log.Default())
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	fs := afero.NewOsFs()
+	parser := messages.NewParser(fs)
+	store := messages.NewFileStore(fs, *dir)
+
+	files, err := parser.TranslationFilesFromDir(*dir)
+	if err != nil {
+		return err
+	}
+
+	// All messages are renamed before a file is written, so a message that already has the new placeholder changes no file.
+	renamed := make(map[string]*messages.RawMessages)
+	totals := make(map[string]int)
+	for _, languageID := range sorted.Keys(files) {
+		translations, err := parser.MessagesFromFile(files[languageID])
+		if err != nil {
+			return fmt.Errorf("reading language file %s: %w", files[languageID], err)
+		}
+
+		for _, k := range sorted.Keys(translations.Messages) {
+			if catalog.BaseKey(k) != *key {
+				continue
+			}
+
+			value, count, err := messages.RenamePlaceholder(translations.Messages[k], *from, *to)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", files[languageID], k, err)
+			}
+
+			translations.Messages[k] = value
+			totals[languageID] += count
+		}
+
+		if totals[languageID] > 0 {
+			renamed[languageID] = translations
+		}
+	}
+
+	if err := catalog.RenamePlaceholderMetadata(fs, *dir, *key, *from, *to); err != nil {
+		return err
+	}
+
+	for _, languageID := range sorted.Keys(renamed) {
+		lang, err := messages.ParseLanguage(languageID)
+		if err != nil {
+			return err
+		}
+
+		if err := store.Save(lang, renamed[languageID]); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "%s: renamed %d placeholders\n", files[languageID], totals[languageID])
+	}
+
+	usages, err := messages.FindReplacementUsages(context.Background(), *src, *key, *from)
+	if err != nil {
+		return err
+	}
+
+	for _, usage := range usages {
+		fmt.Fprintf(out, "%s: passes replacement %q, rename it to %q\n", usage.Pos, usage.Replacement, *to)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages"
+)
+
+// report prints the keys ranked by the runtime usage counts, with the popular locales they are missing in.
+func report(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	usageFile := flags.String("usage", "", "The JSON file with the usage counts by language and key, see messages.Usage.")
+	dir := flags.String("dst", "", "The directory that contains the translation files.")
+	src := flags.String("src", ".", "The directory with the go source files that are searched for translation keys.")
+	locales := flags.Int("locales", 3, "The number of languages with the most traffic that are checked for missing translations.")
+	top := flags.Int("top", 50, "The number of keys to print, zero prints all keys.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: msgextractor report -usage usage.json -dst ./translations -src .
+
+Report joins the runtime usage counts with the keys that are extracted from the source code. The keys are ranked by traffic,
+keys that are missing in the popular locales are flagged, so the translations with the most traffic are done first.
+Keys that are not found in the source code, e.g. dynamic keys, are marked with "(not in source)".
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *usageFile == "" {
+		flags.Usage()
+		return fmt.Errorf("-usage is required")
+	}
+
+	data, err := os.ReadFile(*usageFile)
+	if err != nil {
+		return err
+	}
+
+	var counts messages.UsageCounts
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return fmt.Errorf("decoding %s: %w", *usageFile, err)
+	}
+
+	extraction, err := messages.ExtractFromSourceCodeCtx(context.Background(), *src)
+	if err != nil {
+		return fmt.Errorf("error reading translations from src: %w", err)
+	}
+	for _, invalid := range extraction.InvalidKeys {
+		log.Printf("warning: %v", invalid)
+	}
+
+	usage, err := messages.UsageReportFromDir(afero.NewOsFs(), *dir, counts, extraction.Keys, *locales)
+	if err != nil {
+		return err
+	}
+
+	popular := make([]string, 0, len(usage.Locales))
+	for _, locale := range usage.Locales {
+		popular = append(popular, fmt.Sprintf("%s (%d)", locale.Language, locale.Count))
+	}
+	fmt.Fprintf(out, "popular locales: %s\n", strings.Join(popular, ", "))
+
+	for i, key := range usage.Keys {
+		if *top > 0 && i == *top {
+			break
+		}
+
+		line := fmt.Sprintf("%8d %s", key.Count, key.Key)
+		if len(key.Missing) > 0 {
+			line += " missing in " + strings.Join(key.Missing, ", ")
+		}
+		if !key.InSource {
+			line += " (not in source)"
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wvell/messages/internal/sorted"
+)
+
+var (
+	ErrDuplicateCode = fmt.Errorf("duplicate error code")
+)
+
+// codes returns the keys of the error codes in the metadata, see KeyMetadata.Code.
+func (m Metadata) codes() (map[string]Key, error) {
+	codes := make(map[string]Key)
+	for _, key := range sorted.Keys(m) {
+		code := m[key].Code
+		if code == "" {
+			continue
+		}
+
+		if other, ok := codes[code]; ok {
+			return nil, fmt.Errorf("%w: %s is used by %q and %q", ErrDuplicateCode, code, other, key)
+		}
+
+		codes[code] = Key(key)
+	}
+
+	return codes, nil
+}
+
+// ByCode translates the message with the error code in the language of the ctx, see KeyMetadata.Code.
+// Ok is false if no key has the code.
+func (t *Translator) ByCode(ctx context.Context, code string, replacements map[string]any) (string, bool) {
+	c := t.current.Load()
+	if c == nil {
+		return "", false
+	}
+
+	key, ok := c.codes[code]
+	if !ok {
+		return "", false
+	}
+
+	return t.Translate(ctx, key, replacements), true
+}
+
+// CodeTable returns the messages of all error codes in the language of the ctx, keyed by code, e.g. for support documentation
+// or client SDKs. The messages are rendered with the sample replacements from the metadata, see Preview.
+func (t *Translator) CodeTable(ctx context.Context) map[string]string {
+	c := t.current.Load()
+	if c == nil {
+		return map[string]string{}
+	}
+
+	table := make(map[string]string, len(c.codes))
+	for code, key := range c.codes {
+		table[code] = t.Preview(ctx, key)
+	}
+
+	return table
+}
@@ -0,0 +1,39 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByCode(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"errors.min": "Use at least :min characters", "errors.taken": "The name is taken"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"errors.min": "Gebruik minimaal :min tekens", "errors.taken": "De naam is bezet"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{
+		"errors.min": {"code": "E1001", "samples": {"min": 8}},
+		"errors.taken": {"code": "E1002"}
+	}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+
+	nl := ToCtx(context.Background(), "nl")
+
+	out, ok := tr.ByCode(nl, "E1001", map[string]any{"min": 10})
+	require.True(t, ok)
+	require.Equal(t, "Gebruik minimaal 10 tekens", out)
+
+	_, ok = tr.ByCode(nl, "E9999", nil)
+	require.False(t, ok)
+
+	require.Equal(t, map[string]string{"E1001": "Gebruik minimaal 8 tekens", "E1002": "De naam is bezet"}, tr.CodeTable(nl))
+	require.Equal(t, map[string]string{"E1001": "Use at least 8 characters", "E1002": "The name is taken"}, tr.CodeTable(ToCtx(context.Background(), "en")))
+
+	// A code can only be used by one key.
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{"errors.min": {"code": "E1001"}, "errors.taken": {"code": "E1001"}}`), 0o644))
+	_, err = NewTranslator(fs, "translations")
+	require.ErrorIs(t, err, ErrDuplicateCode)
+}
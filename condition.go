@@ -0,0 +1,130 @@
+package messages
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// conditionRe matches conditional messages like ":count == 0 ? No items | :count items". A message without the separator, like
+// ":count > 5? Wow", is not a conditional message.
+var conditionRe = regexp.MustCompile(`(?s)^\s*:([A-Za-z]+(?:\.[A-Za-z]+)*)\s*(==|!=|<=|>=|<|>)\s*([^?]*?)\s*\?\s*(.*? \| .*)$`)
+
+// conditionSeparator separates the message that is used when the condition matches from the message that is used otherwise.
+const conditionSeparator = " | "
+
+// condition selects one of two messages based on a replacement value.
+// The otherwise message can be a condition as well, so conditions can be chained:
+//
+//	":count == 0 ? No items | :count == 1 ? One item | :count items"
+type condition struct {
+	// The lowercase name of the replacement that is compared.
+	name string
+	// The comparison operator: ==, !=, <, <=, > or >=.
+	op string
+	// The value the replacement is compared to.
+	value string
+	// The message that is used when the condition matches.
+	then message
+	// The message that is used when the condition does not match.
+	otherwise message
+}
+
+// parseCondition parses value as a conditional message.
+// Ok is false if value is not a conditional message.
+func (p *Parser) parseCondition(key, value string) (*condition, bool, error) {
+	match := conditionRe.FindStringSubmatch(value)
+	if match == nil {
+		return nil, false, nil
+	}
+
+	then, otherwise, _ := strings.Cut(match[4], conditionSeparator)
+
+	cond := &condition{
+		name:  strings.ToLower(match[1]),
+		op:    match[2],
+		value: strings.Trim(match[3], `"'`),
+	}
+
+	var err error
+	cond.then, err = p.parseMessage(key, strings.TrimSpace(then))
+	if err != nil {
+		return nil, true, err
+	}
+
+	cond.otherwise, err = p.parseMessage(key, strings.TrimSpace(otherwise))
+	if err != nil {
+		return nil, true, err
+	}
+
+	return cond, true, nil
+}
+
+// choose returns the message that matches the replacements.
+func (c *condition) choose(replacements map[string]any) message {
+	if c.matches(replacements) {
+		return c.then
+	}
+
+	return c.otherwise
+}
+
+// matches compares the replacement value with the condition value.
+// Numbers are compared numerically, other values are compared as strings. A missing replacement is an empty string.
+func (c *condition) matches(replacements map[string]any) bool {
+	value := replacements[c.name]
+
+	var cmp int
+	actual, ok1 := numericValue(value)
+	expected, ok2 := numericValue(c.value)
+	if ok1 && ok2 {
+		switch {
+		case actual < expected:
+			cmp = -1
+		case actual > expected:
+			cmp = 1
+		}
+	} else {
+		var formatted string
+		if value != nil {
+			formatted = formatReplacement(value)
+		}
+		cmp = strings.Compare(formatted, c.value)
+	}
+
+	switch c.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+
+	return false
+}
+
+// numericValue returns the value as a float64 if it is a number or a string that contains a number.
+func numericValue(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
@@ -0,0 +1,48 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditions(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/conditions")
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		key          Key
+		replacements map[string]any
+		expected     string
+	}{
+		{name: "zero", key: "cart.items", replacements: map[string]any{"count": 0}, expected: "Your cart is empty"},
+		{name: "one", key: "cart.items", replacements: map[string]any{"count": uint8(1)}, expected: "One item in your cart"},
+		{name: "many", key: "cart.items", replacements: map[string]any{"count": 3}, expected: "3 items in your cart"},
+		{name: "missing replacement", key: "cart.items", replacements: nil, expected: " items in your cart"},
+		{name: "string equal", key: "status", replacements: map[string]any{"state": "active", "name": "john"}, expected: "John is active"},
+		{name: "string not equal", key: "status", replacements: map[string]any{"state": "blocked", "name": "john"}, expected: "John is blocked"},
+		{name: "greater or equal", key: "limit", replacements: map[string]any{"used": 10.5}, expected: "Limit reached"},
+		{name: "less", key: "limit", replacements: map[string]any{"used": "9"}, expected: "Below limit"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, tr.Translate(ctx, c.key, c.replacements))
+		})
+	}
+}
+
+func TestConditionWithoutSeparator(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/condition-without-separator")
+	require.NoError(t, err)
+
+	ctx := ToCtx(context.Background(), "en")
+	require.Equal(t, "3 == 0 ? Your cart is empty", tr.Translate(ctx, "cart.items", map[string]any{"count": 3}))
+	require.Equal(t, "7 > 5? Wow, that is a lot!", tr.Translate(ctx, "sale", map[string]any{"count": 7}))
+}
@@ -0,0 +1,119 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// configMapDataDir is the symlink to the current version of the files in a Kubernetes ConfigMap or Secret volume.
+// Kubernetes writes the new files to a new timestamped directory and swaps the symlink atomically,
+// the files in the volume are symlinks to "..data/<file>".
+const configMapDataDir = "..data"
+
+// ConfigMapLoader loads the translation files from a mounted Kubernetes ConfigMap or Secret volume.
+// The version is the directory the ..data symlink points to, so an update is detected when Kubernetes swaps the symlink
+// and all files are read from the same update. A directory without a ..data symlink is loaded as a plain directory.
+type ConfigMapLoader struct {
+	dir   string
+	opts  []ParserOpt
+	store *FileStore
+
+	// Mu protects loaded, the directory of the update of the last Load. The metadata is read from the same update.
+	mu     sync.Mutex
+	loaded string
+}
+
+var _ MetadataLoader = (*ConfigMapLoader)(nil)
+
+// NewConfigMapLoader returns a loader for the volume that is mounted at dir.
+func NewConfigMapLoader(dir string, opts ...ParserOpt) *ConfigMapLoader {
+	return &ConfigMapLoader{
+		dir:   dir,
+		opts:  opts,
+		store: NewFileStore(afero.NewOsFs(), dir, opts...),
+	}
+}
+
+// dataDir returns the directory the ..data symlink points to, ok is false if the volume has no ..data symlink.
+func (l *ConfigMapLoader) dataDir() (target string, ok bool, err error) {
+	target, err = os.Readlink(filepath.Join(l.dir, configMapDataDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("reading %s: %w", configMapDataDir, err)
+	}
+
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(l.dir, target)
+	}
+
+	return target, true, nil
+}
+
+func (l *ConfigMapLoader) Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error) {
+	target, ok, err := l.dataDir()
+	if err != nil {
+		return nil, Version{}, err
+	}
+	if !ok {
+		l.setLoaded("")
+		return l.store.Load(ctx, since)
+	}
+
+	stat, err := os.Stat(target)
+	if err != nil {
+		return nil, Version{}, fmt.Errorf("reading %s: %w", configMapDataDir, err)
+	}
+
+	version := Version{ETag: filepath.Base(target), LastModified: stat.ModTime()}
+	if version.ETag == since.ETag {
+		return nil, version, ErrNotModified
+	}
+
+	// The files are read from the directory of this update, not through the symlinks that Kubernetes can swap while loading.
+	languages, _, err := NewFileStore(afero.NewOsFs(), target, l.opts...).Load(ctx, Version{})
+	var errs LanguageErrors
+	if err != nil && !errors.As(err, &errs) {
+		return nil, Version{}, err
+	}
+
+	l.setLoaded(target)
+	return languages, version, err
+}
+
+// setLoaded records the directory of the update that Load read, empty for a plain directory.
+func (l *ConfigMapLoader) setLoaded(dir string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.loaded = dir
+}
+
+// Metadata reads the metadata file of the volume, see MetadataLoader.
+// The file is read from the update of the last Load, so the metadata belongs to the loaded messages.
+func (l *ConfigMapLoader) Metadata(ctx context.Context) (Metadata, error) {
+	l.mu.Lock()
+	dir := l.loaded
+	l.mu.Unlock()
+
+	if dir == "" {
+		target, ok, err := l.dataDir()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return l.store.Metadata(ctx)
+		}
+		dir = target
+	}
+
+	return NewFileStore(afero.NewOsFs(), dir, l.opts...).Metadata(ctx)
+}
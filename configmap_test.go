@@ -0,0 +1,127 @@
+package messages
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigMap writes the files like the kubelet does: to a new timestamped directory, the ..data symlink is swapped atomically.
+func writeConfigMap(t *testing.T, dir, version string, files map[string]string) {
+	data := filepath.Join(dir, version)
+	require.NoError(t, os.Mkdir(data, 0o755))
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(data, name), []byte(content), 0o644))
+
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			require.NoError(t, os.Symlink(filepath.Join(configMapDataDir, name), link))
+		}
+	}
+
+	tmp := filepath.Join(dir, "..data_tmp")
+	require.NoError(t, os.Symlink(version, tmp))
+	require.NoError(t, os.Rename(tmp, filepath.Join(dir, configMapDataDir)))
+}
+
+func TestConfigMapLoader(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigMap(t, dir, "..2024_01_01_00_00_00.1", map[string]string{
+		"en.json": `{"welcome": "Welcome"}`,
+		"nl.json": `{"welcome": "Welkom"}`,
+	})
+
+	tr, err := NewTranslatorFromLoader(context.Background(), NewConfigMapLoader(dir))
+	require.NoError(t, err)
+
+	en := ToCtx(context.Background(), "en")
+	nl := ToCtx(context.Background(), "nl")
+	require.Equal(t, "Welcome", tr.Translate(en, "welcome", nil))
+	require.Equal(t, "Welkom", tr.Translate(nl, "welcome", nil))
+	require.Len(t, tr.current.Load().languages, 2)
+
+	loader := NewConfigMapLoader(dir)
+	_, version, err := loader.Load(context.Background(), Version{})
+	require.NoError(t, err)
+	require.Equal(t, "..2024_01_01_00_00_00.1", version.ETag)
+
+	_, _, err = loader.Load(context.Background(), version)
+	require.ErrorIs(t, err, ErrNotModified)
+
+	// The update has the same file sizes and modification times, the swap of the symlink is detected.
+	modified := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "..2024_01_01_00_00_00.1", "en.json"), modified, modified))
+	writeConfigMap(t, dir, "..2024_01_01_00_01_00.2", map[string]string{
+		"en.json": `{"welcome": "Hello!!"}`,
+		"nl.json": `{"welcome": "Hallo!"}`,
+	})
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "..2024_01_01_00_01_00.2", "en.json"), modified, modified))
+
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, "Hello!!", tr.Translate(en, "welcome", nil))
+	require.Equal(t, "Hallo!", tr.Translate(nl, "welcome", nil))
+}
+
+func TestConfigMapLoaderPlainDir(t *testing.T) {
+	tr, err := NewTranslatorFromLoader(context.Background(), NewConfigMapLoader("./testdata/valid"))
+	require.NoError(t, err)
+	require.NotEmpty(t, tr.current.Load().languages)
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigMap(t, dir, "..1", map[string]string{"en.json": `{"welcome": "Welcome"}`})
+
+	tr, err := NewTranslatorFromLoader(context.Background(), NewConfigMapLoader(dir))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		tr.Watch(ctx, time.Millisecond, func(err error) { t.Error(err) })
+		close(done)
+	}()
+
+	writeConfigMap(t, dir, "..2", map[string]string{"en.json": `{"welcome": "Hello"}`})
+
+	en := ToCtx(context.Background(), "en")
+	require.Eventually(t, func() bool {
+		return tr.Translate(en, "welcome", nil) == "Hello"
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestConfigMapLoaderMetadata(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigMap(t, dir, "..2024_01_01_00_00_00.1", map[string]string{
+		"en.json":    `{"welcome": "Welcome"}`,
+		MetadataFile: `{"welcome": {"description": "first"}}`,
+	})
+
+	loader := NewConfigMapLoader(dir)
+	_, _, err := loader.Load(context.Background(), Version{})
+	require.NoError(t, err)
+
+	// The metadata is read from the update of the loaded messages, also when Kubernetes swapped the symlink in between.
+	writeConfigMap(t, dir, "..2024_01_01_00_01_00.2", map[string]string{
+		"en.json":    `{"welcome": "Hello"}`,
+		MetadataFile: `{"welcome": {"description": "second"}}`,
+	})
+
+	metadata, err := loader.Metadata(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "first", metadata["welcome"].Description)
+
+	_, _, err = loader.Load(context.Background(), Version{})
+	require.NoError(t, err)
+	metadata, err = loader.Metadata(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "second", metadata["welcome"].Description)
+}
@@ -37,16 +37,44 @@ func ToCtx(ctx context.Context, lang string) context.Context {
 	return _ctx
 }
 
+// WithLanguages sets the acceptable languages in the ctx in priority order, e.g. from a parsed Accept-Language header.
+// The Translator uses the first language it has messages for before it uses the default language.
+// The ctx is returned as is when there are no languages. An error is returned if one of the languages can not be parsed.
+func WithLanguages(ctx context.Context, langs ...string) (context.Context, error) {
+	if len(langs) == 0 {
+		return ctx, nil
+	}
+
+	ids := make([]LanguageID, 0, len(langs))
+	for _, lang := range langs {
+		id, err := ParseLanguage(lang)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return toCtx(ctx, ids...), nil
+}
+
 // LanguageFromCtx returns the language from the ctx.
+// When the ctx holds multiple languages the language with the highest priority is returned.
 func FromCtx(ctx context.Context) LanguageID {
-	l, ok := ctx.Value(languageKey).(LanguageID)
-	if ok {
-		return l
+	langs := LanguagesFromCtx(ctx)
+	if len(langs) > 0 {
+		return langs[0]
 	}
 
 	return LanguageID{}
 }
 
+// LanguagesFromCtx returns the acceptable languages from the ctx in priority order.
+func LanguagesFromCtx(ctx context.Context) []LanguageID {
+	langs, _ := ctx.Value(languageKey).([]LanguageID)
+	return langs
+}
+
 // ParseLanguage parses the language string into a LanguageID.
 func ParseLanguage(lang string) (LanguageID, error) {
 	match := langRe.FindString(lang)
@@ -77,8 +105,8 @@ func ParseLanguage(lang string) (LanguageID, error) {
 	return id, nil
 }
 
-func toCtx(ctx context.Context, id LanguageID) context.Context {
-	return context.WithValue(ctx, languageKey, id)
+func toCtx(ctx context.Context, ids ...LanguageID) context.Context {
+	return context.WithValue(ctx, languageKey, ids)
 }
 
 // LanguageID holds the language and an optional region.
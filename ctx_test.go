@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 )
 
@@ -100,3 +101,39 @@ func TestToCtx(t *testing.T) {
 		})
 	}
 }
+
+func TestWithLanguages(t *testing.T) {
+	ctx, err := WithLanguages(context.Background(), "fr-CH", "nl", "en")
+	require.NoError(t, err)
+	require.Equal(t, LanguageID{Language: "fr", Region: "CH"}, FromCtx(ctx))
+	require.Equal(t, []LanguageID{{Language: "fr", Region: "CH"}, {Language: "nl"}, {Language: "en"}}, LanguagesFromCtx(ctx))
+
+	_, err = WithLanguages(context.Background(), "nl", "invalid!")
+	require.Error(t, err)
+
+	// WithLanguage replaces the languages.
+	ctx, err = WithLanguage(ctx, "de")
+	require.NoError(t, err)
+	require.Equal(t, []LanguageID{{Language: "de"}}, LanguagesFromCtx(ctx))
+
+	// Without languages the language of the ctx is kept.
+	ctx, err = WithLanguages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []LanguageID{{Language: "de"}}, LanguagesFromCtx(ctx))
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom"}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "en"}))
+	require.NoError(t, err)
+
+	// The second choice is used before the default language.
+	ctx, err = WithLanguages(context.Background(), "fr-CH", "nl-BE", "en")
+	require.NoError(t, err)
+	require.Equal(t, "Welkom", tr.Translate(ctx, "welcome", nil))
+
+	ctx, err = WithLanguages(context.Background(), "fr", "de")
+	require.NoError(t, err)
+	require.Equal(t, "Welcome", tr.Translate(ctx, "welcome", nil))
+}
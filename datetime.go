@@ -0,0 +1,142 @@
+package messages
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+var timeZoneKey = ctxKey("timezone")
+
+// locations caches the locations that are loaded by name, time.LoadLocation reads the time zone database on every call.
+// Only valid names are cached, so the cache is limited to the locations of the time zone database.
+var locations sync.Map
+
+// TimeZoneKey is the replacement that sets the time zone of the time.Time replacements of a single call, the value is a *time.Location or
+// the name of a location like "Europe/Amsterdam". It takes precedence over the time zone of the context, see WithTimeZone.
+const TimeZoneKey = "timezone"
+
+// dateTimeFormat holds the layouts of the date and the time of a language.
+type dateTimeFormat struct {
+	date string
+	time string
+}
+
+// dateTimeFormats holds the formats by language, the format of the language with region takes precedence over the format of the language.
+var dateTimeFormats = map[string]dateTimeFormat{
+	"en":    {date: "1/2/2006", time: "3:04 PM"},
+	"en-GB": {date: "02/01/2006", time: "15:04"},
+	"nl":    {date: "2-1-2006", time: "15:04"},
+	"de":    {date: "2.1.2006", time: "15:04"},
+	"fr":    {date: "02/01/2006", time: "15:04"},
+	"es":    {date: "2/1/2006", time: "15:04"},
+	"it":    {date: "2/1/2006", time: "15:04"},
+	"pt":    {date: "02/01/2006", time: "15:04"},
+}
+
+// defaultDateTimeFormat is the ISO 8601 format for languages without a format.
+var defaultDateTimeFormat = dateTimeFormat{date: "2006-01-02", time: "15:04"}
+
+// WithTimeZone sets the time zone in the ctx. The time.Time replacements of Translate calls with the ctx are converted to the time zone
+// before they are formatted, so users see the time in their own time zone.
+func WithTimeZone(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, timeZoneKey, loc)
+}
+
+// TimeZoneFromCtx returns the time zone of the ctx, nil if the ctx has no time zone.
+func TimeZoneFromCtx(ctx context.Context) *time.Location {
+	loc, _ := ctx.Value(timeZoneKey).(*time.Location)
+	return loc
+}
+
+// convertTimes converts the time.Time replacements to the time zone of the call or the ctx.
+// The replacements of the caller are not modified, a copy is returned when a time is converted.
+func convertTimes(ctx context.Context, replacements map[string]any) map[string]any {
+	loc := TimeZoneFromCtx(ctx)
+	switch tz := replacements[TimeZoneKey].(type) {
+	case *time.Location:
+		loc = tz
+	case string:
+		if l, ok := loadLocation(tz); ok {
+			loc = l
+		}
+	}
+
+	if loc == nil {
+		return replacements
+	}
+
+	var converted map[string]any
+	for name, value := range replacements {
+		t, ok := value.(time.Time)
+		if !ok {
+			continue
+		}
+
+		if converted == nil {
+			converted = make(map[string]any, len(replacements))
+			for k, v := range replacements {
+				converted[k] = v
+			}
+		}
+
+		converted[name] = t.In(loc)
+	}
+
+	if converted == nil {
+		return replacements
+	}
+
+	return converted
+}
+
+// loadLocation returns the cached location of the name, ok is false if the name is not a location.
+func loadLocation(name string) (*time.Location, bool) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), true
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+
+	locations.Store(name, loc)
+	return loc, true
+}
+
+// formatFor returns the date and time format of the language.
+func formatFor(lang language.Tag) dateTimeFormat {
+	base, _ := lang.Base()
+	if region, confidence := lang.Region(); confidence == language.Exact {
+		if format, ok := dateTimeFormats[base.String()+"-"+region.String()]; ok {
+			return format
+		}
+	}
+
+	if format, ok := dateTimeFormats[base.String()]; ok {
+		return format
+	}
+
+	return defaultDateTimeFormat
+}
+
+// formatDateTime formats the date and time for the language.
+func formatDateTime(lang language.Tag, t time.Time) string {
+	format := formatFor(lang)
+	return t.Format(format.date + " " + format.time)
+}
+
+// dateTimeModifier returns a modifier that formats a time.Time with the layout of the language, other values are formatted as is.
+func dateTimeModifier(layout func(format dateTimeFormat) string) Modifier {
+	return func(lang language.Tag, value any, _ string) string {
+		t, ok := value.(time.Time)
+		if !ok {
+			return formatReplacement(value)
+		}
+
+		return t.Format(layout(formatFor(lang)))
+	}
+}
@@ -0,0 +1,54 @@
+package messages
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeZone(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"shipped": "Shipped at :at", "date": "On :at|date at :at|time"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"shipped": "Verzonden op :at"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/en_GB.json", []byte(`{"shipped": "Shipped at :at|datetime"}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+
+	at := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	replacements := map[string]any{"at": at}
+
+	en := ToCtx(context.Background(), "en")
+	require.Equal(t, "Shipped at 3/5/2024 2:30 PM", tr.Translate(en, "shipped", replacements))
+	require.Equal(t, "On 3/5/2024 at 2:30 PM", tr.Translate(en, "date", replacements))
+
+	// The time is converted to the time zone of the context.
+	require.Equal(t, "Shipped at 3/5/2024 3:30 PM", tr.Translate(WithTimeZone(en, amsterdam), "shipped", replacements))
+	require.Equal(t, "Verzonden op 5-3-2024 15:30", tr.Translate(WithTimeZone(ToCtx(context.Background(), "nl"), amsterdam), "shipped", replacements))
+	require.Equal(t, "Shipped at 05/03/2024 15:30", tr.Translate(WithTimeZone(ToCtx(context.Background(), "en-GB"), amsterdam), "shipped", replacements))
+	require.Equal(t, at, replacements["at"])
+
+	// The time zone of the call takes precedence.
+	require.Equal(t, "Shipped at 3/5/2024 9:30 AM", tr.Translate(WithTimeZone(en, amsterdam), "shipped", map[string]any{"at": at, TimeZoneKey: "America/New_York"}))
+	require.Equal(t, "Shipped at 3/5/2024 3:30 PM", tr.Translate(en, "shipped", map[string]any{"at": at, TimeZoneKey: amsterdam}))
+
+	require.Nil(t, TimeZoneFromCtx(en))
+
+	// The locations are cached, unknown locations are not.
+	loc, ok := loadLocation("America/New_York")
+	require.True(t, ok)
+	cached, ok := locations.Load("America/New_York")
+	require.True(t, ok)
+	require.Same(t, loc, cached)
+
+	_, ok = loadLocation("Mars/Olympus_Mons")
+	require.False(t, ok)
+	_, ok = locations.Load("Mars/Olympus_Mons")
+	require.False(t, ok)
+}
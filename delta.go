@@ -0,0 +1,205 @@
+package messages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/wvell/messages/internal/wire"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var (
+	ErrDeltaBase = fmt.Errorf("delta does not apply to the version of the pack")
+)
+
+// DeltaSuffix is the suffix of a delta file, e.g. "nl.v1.msgdelta.gz".
+const DeltaSuffix = ".msgdelta.gz"
+
+// deltaName is the name of the file in the gzip header of a delta, after the language.
+const deltaName = ".msgdelta"
+
+// The field numbers of the Delta message in proto/messages/v1/catalog.proto.
+const (
+	deltaBaseVersionField       protowire.Number = 1
+	deltaVersionField           protowire.Number = 2
+	deltaLastModifiedField      protowire.Number = 3
+	deltaLanguageField          protowire.Number = 4
+	deltaSetField               protowire.Number = 5
+	deltaDeletedMessagesField   protowire.Number = 6
+	deltaDeletedAttributesField protowire.Number = 7
+)
+
+// Delta holds the changes of the pack of a language since a version, so a client with the pack of that version only downloads
+// the changes. It is compressed and checksummed like a Pack. Create deltas with NewDelta or "msgextractor pack -base", apply them with Pack.Apply.
+type Delta struct {
+	Language LanguageID
+	// Base is the ETag of the version of the pack the delta applies to.
+	Base string
+	// Version is the version of the pack after the delta is applied.
+	Version Version
+	// Set holds the messages and attributes that are added or changed.
+	Set *RawMessages
+	// DeletedMessages and DeletedAttributes are the keys that are removed.
+	DeletedMessages   []string
+	DeletedAttributes []string
+	// Checksum is the sha256 of the encoded delta, it is set by MarshalBinary and UnmarshalDelta.
+	Checksum string
+}
+
+// NewDelta returns the changes from the base pack to the target pack.
+func NewDelta(base, target *Pack) (*Delta, error) {
+	if base.Language != target.Language {
+		return nil, fmt.Errorf("delta from language %s to %s", base.Language, target.Language)
+	}
+
+	d := &Delta{
+		Language: target.Language,
+		Base:     base.Version.ETag,
+		Version:  target.Version,
+		Set:      &RawMessages{Messages: make(map[string]string), Attributes: make(map[string]string)},
+	}
+	d.DeletedMessages = diffStringMap(base.Messages.Messages, target.Messages.Messages, d.Set.Messages)
+	d.DeletedAttributes = diffStringMap(base.Messages.Attributes, target.Messages.Attributes, d.Set.Attributes)
+
+	return d, nil
+}
+
+// diffStringMap adds the changed and added values of target to set and returns the sorted keys that are removed.
+func diffStringMap(base, target, set map[string]string) []string {
+	for key, value := range target {
+		if old, ok := base[key]; !ok || old != value {
+			set[key] = value
+		}
+	}
+
+	var deleted []string
+	for key := range base {
+		if _, ok := target[key]; !ok {
+			deleted = append(deleted, key)
+		}
+	}
+	slices.Sort(deleted)
+
+	return deleted
+}
+
+// Apply returns the pack with the changes of the delta, the pack itself is not changed.
+// It is an ErrDeltaBase when the delta is for another language or version.
+func (p *Pack) Apply(d *Delta) (*Pack, error) {
+	if p.Language != d.Language || p.Version.ETag != d.Base {
+		return nil, fmt.Errorf("%w: delta %s@%s, pack %s@%s", ErrDeltaBase, d.Language, d.Base, p.Language, p.Version.ETag)
+	}
+
+	raw := &RawMessages{Messages: maps.Clone(p.Messages.Messages), Attributes: maps.Clone(p.Messages.Attributes)}
+	if raw.Messages == nil {
+		raw.Messages = make(map[string]string)
+	}
+	if raw.Attributes == nil {
+		raw.Attributes = make(map[string]string)
+	}
+
+	for _, key := range d.DeletedMessages {
+		delete(raw.Messages, key)
+	}
+	for _, key := range d.DeletedAttributes {
+		delete(raw.Attributes, key)
+	}
+	maps.Copy(raw.Messages, d.Set.Messages)
+	maps.Copy(raw.Attributes, d.Set.Attributes)
+
+	return &Pack{Language: p.Language, Messages: raw, Version: d.Version}, nil
+}
+
+// MarshalBinary encodes the delta and sets the checksum.
+func (d *Delta) MarshalBinary() ([]byte, error) {
+	var b []byte
+	b = protowire.AppendTag(b, deltaBaseVersionField, protowire.BytesType)
+	b = protowire.AppendString(b, d.Base)
+
+	if d.Version.ETag != "" {
+		b = protowire.AppendTag(b, deltaVersionField, protowire.BytesType)
+		b = protowire.AppendString(b, d.Version.ETag)
+	}
+
+	if !d.Version.LastModified.IsZero() {
+		b = protowire.AppendTag(b, deltaLastModifiedField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(d.Version.LastModified.UnixMilli()))
+	}
+
+	b = protowire.AppendTag(b, deltaLanguageField, protowire.BytesType)
+	b = protowire.AppendString(b, d.Language.String())
+
+	var set []byte
+	set = appendStringMap(set, wire.MessagesMessages, d.Set.Messages)
+	set = appendStringMap(set, wire.MessagesAttributes, d.Set.Attributes)
+	b = protowire.AppendTag(b, deltaSetField, protowire.BytesType)
+	b = protowire.AppendBytes(b, set)
+
+	for _, key := range d.DeletedMessages {
+		b = protowire.AppendTag(b, deltaDeletedMessagesField, protowire.BytesType)
+		b = protowire.AppendString(b, key)
+	}
+	for _, key := range d.DeletedAttributes {
+		b = protowire.AppendTag(b, deltaDeletedAttributesField, protowire.BytesType)
+		b = protowire.AppendString(b, key)
+	}
+
+	data, checksum, err := compress(d.Language.String()+deltaName, b, d.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Checksum = checksum
+	return data, nil
+}
+
+// UnmarshalDelta decodes a delta and verifies its checksum.
+func UnmarshalDelta(data []byte) (*Delta, error) {
+	payload, name, checksum, err := decompress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(name, deltaName) {
+		return nil, fmt.Errorf("%w: %s is not a delta", ErrInvalidPack, name)
+	}
+
+	d := &Delta{
+		Set:      &RawMessages{Messages: make(map[string]string), Attributes: make(map[string]string)},
+		Checksum: checksum,
+	}
+	err = consumeFields(payload, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+		switch {
+		case num == deltaBaseVersionField && typ == protowire.BytesType:
+			d.Base = string(value)
+		case num == deltaVersionField && typ == protowire.BytesType:
+			d.Version.ETag = string(value)
+		case num == deltaLastModifiedField && typ == protowire.VarintType:
+			d.Version.LastModified = time.UnixMilli(int64(varint))
+		case num == deltaLanguageField && typ == protowire.BytesType:
+			lang, err := ParseLanguage(string(value))
+			if err != nil {
+				return err
+			}
+			d.Language = lang
+		case num == deltaSetField && typ == protowire.BytesType:
+			return consumeMessages(value, d.Set)
+		case num == deltaDeletedMessagesField && typ == protowire.BytesType:
+			d.DeletedMessages = append(d.DeletedMessages, norm.NFC.String(string(value)))
+		case num == deltaDeletedAttributesField && typ == protowire.BytesType:
+			d.DeletedAttributes = append(d.DeletedAttributes, norm.NFC.String(string(value)))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPack, err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,121 @@
+package messages
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelta(t *testing.T) {
+	nl, err := ParseLanguage("nl")
+	require.NoError(t, err)
+
+	base := &Pack{
+		Language: nl,
+		Messages: &RawMessages{Messages: map[string]string{"welcome": "Welkom", "bye": "Doei", "old": "Oud"}, Attributes: map[string]string{"email": "e-mail"}},
+		Version:  Version{ETag: "v1"},
+	}
+	target := &Pack{
+		Language: nl,
+		Messages: &RawMessages{Messages: map[string]string{"welcome": "Welkom :User", "bye": "Doei", "new": "Nieuw"}, Attributes: map[string]string{}},
+		Version:  Version{ETag: "v2", LastModified: time.UnixMilli(1700000000000)},
+	}
+
+	delta, err := NewDelta(base, target)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"welcome": "Welkom :User", "new": "Nieuw"}, delta.Set.Messages)
+	require.Equal(t, []string{"old"}, delta.DeletedMessages)
+	require.Equal(t, []string{"email"}, delta.DeletedAttributes)
+
+	data, err := delta.MarshalBinary()
+	require.NoError(t, err)
+
+	decoded, err := UnmarshalDelta(data)
+	require.NoError(t, err)
+	require.Equal(t, delta.Checksum, decoded.Checksum)
+
+	applied, err := base.Apply(decoded)
+	require.NoError(t, err)
+	require.Equal(t, target.Messages, applied.Messages)
+	require.Equal(t, "v2", applied.Version.ETag)
+	require.True(t, target.Version.LastModified.Equal(applied.Version.LastModified))
+
+	// The base pack is not changed.
+	require.Equal(t, "Oud", base.Messages.Messages["old"])
+
+	// A delta only applies to the pack of its base version.
+	_, err = applied.Apply(decoded)
+	require.ErrorIs(t, err, ErrDeltaBase)
+
+	// A pack is not a delta.
+	packData, err := base.MarshalBinary()
+	require.NoError(t, err)
+	_, err = UnmarshalDelta(packData)
+	require.ErrorIs(t, err, ErrInvalidPack)
+}
+
+func TestHTTPLoaderDelta(t *testing.T) {
+	nl, err := ParseLanguage("nl")
+	require.NoError(t, err)
+
+	packs := map[string]*Pack{
+		"v1": {Language: nl, Messages: &RawMessages{Messages: map[string]string{"welcome": "Welkom", "bye": "Doei"}}, Version: Version{ETag: "v1"}},
+		"v2": {Language: nl, Messages: &RawMessages{Messages: map[string]string{"welcome": "Welkom :User", "bye": "Doei"}}, Version: Version{ETag: "v2"}},
+	}
+	current := "v1"
+	var deltas, fulls int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since := strings.Trim(r.Header.Get("If-None-Match"), `"`)
+		if since == current {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"`+current+`"`)
+		if base, ok := packs[since]; ok && r.Header.Get("A-IM") == "msgdelta" {
+			delta, err := NewDelta(base, packs[current])
+			require.NoError(t, err)
+			data, err := delta.MarshalBinary()
+			require.NoError(t, err)
+
+			deltas++
+			w.WriteHeader(http.StatusIMUsed)
+			_, _ = w.Write(data)
+			return
+		}
+
+		data, err := packs[current].MarshalBinary()
+		require.NoError(t, err)
+
+		fulls++
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	ctx := ToCtx(context.Background(), "nl")
+	tr, err := NewTranslatorFromLoader(context.Background(), NewHTTPLoader(server.URL, server.Client()))
+	require.NoError(t, err)
+	require.Equal(t, "Welkom", tr.Translate(ctx, "welcome", map[string]any{"user": "jan"}))
+
+	current = "v2"
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, "Welkom Jan", tr.Translate(ctx, "welcome", map[string]any{"user": "jan"}))
+	require.Equal(t, "Doei", tr.Translate(ctx, "bye", nil))
+	require.Equal(t, 1, deltas)
+	require.Equal(t, 1, fulls)
+
+	// The full pack is downloaded when the delta does not apply.
+	packs["v3"] = &Pack{Language: nl, Messages: &RawMessages{Messages: map[string]string{"welcome": "Hoi"}}, Version: Version{ETag: "v3"}}
+	packs["v2"].Version.ETag = "other"
+	current = "v3"
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, "Hoi", tr.Translate(ctx, "welcome", nil))
+	require.Equal(t, 2, deltas)
+	require.Equal(t, 2, fulls)
+}
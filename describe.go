@@ -0,0 +1,91 @@
+package messages
+
+import (
+	"slices"
+)
+
+// KeyInfo describes a translation key for tooling like admin UIs and the web editor, see Translator.Describe.
+type KeyInfo struct {
+	Key Key `json:"key"`
+	// Description tells the translator where and how the message is used.
+	Description string `json:"description,omitempty"`
+	// Placeholders are the placeholders of the message in any language and the placeholders in the metadata, sorted by name.
+	Placeholders []PlaceholderInfo `json:"placeholders,omitempty"`
+	// MaxLength is the maximum number of characters of a translation, zero is no limit.
+	MaxLength int `json:"max_length,omitempty"`
+	// Samples are the example replacement values of the metadata.
+	Samples map[string]any `json:"samples,omitempty"`
+	// Code is the error code of the message, see Translator.ByCode.
+	Code string `json:"code,omitempty"`
+}
+
+// PlaceholderInfo describes a placeholder of a message.
+type PlaceholderInfo struct {
+	Name string `json:"name"`
+	// Type is the expected type of the replacement from the metadata, empty if the metadata has no type.
+	Type string `json:"type,omitempty"`
+}
+
+// Describe returns the metadata of the key together with the placeholders that the messages of the key use.
+// A key without metadata and messages only has the Key set.
+func (t *Translator) Describe(key Key) KeyInfo {
+	base, _, _ := splitCasingDirective(key)
+	info := KeyInfo{Key: base}
+
+	c := t.current.Load()
+	if c == nil {
+		return info
+	}
+
+	metadata := c.metadata[string(base)]
+	info.Description = metadata.Description
+	info.MaxLength = metadata.MaxLength
+	info.Samples = metadata.Samples
+	info.Code = metadata.Code
+
+	var names []string
+	for name := range metadata.Placeholders {
+		names = append(names, name)
+	}
+
+	for _, messages := range c.languages {
+		if i, ok := messages.index[base]; ok {
+			names = messages.messages[i].placeholders(names)
+		}
+		for _, regionMessages := range messages.regions {
+			if msg, ok := regionMessages[base]; ok {
+				names = msg.placeholders(names)
+			}
+		}
+	}
+
+	slices.Sort(names)
+	for _, name := range slices.Compact(names) {
+		info.Placeholders = append(info.Placeholders, PlaceholderInfo{Name: name, Type: metadata.Placeholders[name]})
+	}
+
+	return info
+}
+
+// placeholders appends the names of the replacements that the message uses to names.
+func (m message) placeholders(names []string) []string {
+	for _, r := range m.replacements {
+		names = append(names, r.name)
+	}
+
+	if m.template != nil {
+		names = append(names, m.template.fields...)
+	}
+
+	if m.icu != nil {
+		names = append(names, m.icu.args...)
+	}
+
+	if m.condition != nil {
+		names = append(names, m.condition.name)
+		names = m.condition.then.placeholders(names)
+		names = m.condition.otherwise.placeholders(names)
+	}
+
+	return names
+}
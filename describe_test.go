@@ -0,0 +1,37 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"cart.items": "You have :count items, :User", "save": "Save"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"cart.items": "Je hebt :count artikelen", "cart.items@BE": "Je hebt :count artikels in :shop"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{
+		"cart.items": {"description": "Number of items in the cart.", "placeholders": {"count": "int"}, "samples": {"count": 3}},
+		"save": {"description": "Label of the save button.", "max_length": 12, "code": "E1001"}
+	}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+
+	require.Equal(t, KeyInfo{
+		Key:         "cart.items",
+		Description: "Number of items in the cart.",
+		Placeholders: []PlaceholderInfo{
+			{Name: "count", Type: "int"},
+			{Name: "shop"},
+			{Name: "user"},
+		},
+		Samples: map[string]any{"count": int64(3)},
+	}, tr.Describe("cart.items"))
+
+	require.Equal(t, KeyInfo{Key: "save", Description: "Label of the save button.", MaxLength: 12, Code: "E1001"}, tr.Describe("save"))
+
+	// Keys without metadata and messages only have the key.
+	require.Equal(t, KeyInfo{Key: "unknown"}, tr.Describe("unknown"))
+}
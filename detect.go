@@ -0,0 +1,150 @@
+package messages
+
+import (
+	"embed"
+	"math"
+	"path"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/wvell/messages/internal/sorted"
+)
+
+//go:embed catalogs/detect/*.txt
+var detectFS embed.FS
+
+// LanguageDetector detects the language of a text, e.g. to choose the language of a reply to a message of a user.
+type LanguageDetector interface {
+	// Detect returns the most likely language of the text and a confidence between 0 and 1.
+	// An empty LanguageID is returned when the text has no letters.
+	Detect(text string) (LanguageID, float64)
+}
+
+var (
+	defaultDetector     *NgramDetector
+	defaultDetectorOnce sync.Once
+)
+
+// DetectLanguage returns the most likely language of the text and a confidence between 0 and 1.
+// It detects en, nl, de, fr, es, it and pt with the built-in NgramDetector. Short texts of a few words have a low confidence,
+// use the language of the user when it is known.
+func DetectLanguage(text string) (LanguageID, float64) {
+	defaultDetectorOnce.Do(func() {
+		samples := make(map[LanguageID]string)
+
+		entries, err := detectFS.ReadDir("catalogs/detect")
+		if err != nil {
+			panic(err)
+		}
+
+		for _, entry := range entries {
+			data, err := detectFS.ReadFile(path.Join("catalogs/detect", entry.Name()))
+			if err != nil {
+				panic(err)
+			}
+
+			samples[LanguageID{Language: strings.TrimSuffix(entry.Name(), ".txt")}] = string(data)
+		}
+
+		defaultDetector = NewNgramDetector(samples)
+	})
+
+	return defaultDetector.Detect(text)
+}
+
+// NgramDetector detects the language of a text by comparing the trigrams of the text with the trigrams of sample texts.
+type NgramDetector struct {
+	profiles map[LanguageID]ngramProfile
+}
+
+var _ LanguageDetector = (*NgramDetector)(nil)
+
+// ngramProfile holds the frequencies of the trigrams of a text and the length of the frequency vector.
+type ngramProfile struct {
+	counts map[string]float64
+	norm   float64
+}
+
+// NewNgramDetector returns a detector for the languages of the samples. A sample should be a few paragraphs of ordinary text.
+func NewNgramDetector(samples map[LanguageID]string) *NgramDetector {
+	d := &NgramDetector{profiles: make(map[LanguageID]ngramProfile, len(samples))}
+	for lang, sample := range samples {
+		d.profiles[lang] = newNgramProfile(sample)
+	}
+
+	return d
+}
+
+// Detect returns the language with the most similar trigrams. The confidence is how much better the text matches
+// the best language than the second best language.
+func (d *NgramDetector) Detect(text string) (LanguageID, float64) {
+	profile := newNgramProfile(text)
+	if profile.norm == 0 {
+		return LanguageID{}, 0
+	}
+
+	var best LanguageID
+	bestScore, secondScore := 0.0, 0.0
+
+	// The languages are compared in sorted order, so ties always return the same language.
+	byName := make(map[string]LanguageID, len(d.profiles))
+	for lang := range d.profiles {
+		byName[lang.String()] = lang
+	}
+
+	for _, name := range sorted.Keys(byName) {
+		lang := byName[name]
+
+		score := profile.similarity(d.profiles[lang])
+		switch {
+		case score > bestScore:
+			best, bestScore, secondScore = lang, score, bestScore
+		case score > secondScore:
+			secondScore = score
+		}
+	}
+
+	if bestScore == 0 {
+		return LanguageID{}, 0
+	}
+
+	return best, (bestScore - secondScore) / bestScore
+}
+
+// newNgramProfile returns the trigram frequencies of the lowercase words in text, the words are padded with spaces.
+func newNgramProfile(text string) ngramProfile {
+	profile := ngramProfile{counts: make(map[string]float64)}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			profile.counts[string(runes[i:i+3])]++
+		}
+	}
+
+	for _, count := range profile.counts {
+		profile.norm += count * count
+	}
+	profile.norm = math.Sqrt(profile.norm)
+
+	return profile
+}
+
+// similarity returns the cosine similarity of the trigram frequencies.
+func (p ngramProfile) similarity(other ngramProfile) float64 {
+	if p.norm == 0 || other.norm == 0 {
+		return 0
+	}
+
+	var dot float64
+	for trigram, count := range p.counts {
+		dot += count * other.counts[trigram]
+	}
+
+	return dot / (p.norm * other.norm)
+}
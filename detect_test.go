@@ -0,0 +1,47 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := map[string]string{
+		"en": "Hello, I ordered a pair of shoes last week but they are too small. How can I return them?",
+		"nl": "Hallo, ik heb vorige week een paar schoenen besteld maar ze zijn te klein. Hoe kan ik ze terugsturen?",
+		"de": "Hallo, ich habe letzte Woche ein Paar Schuhe bestellt, aber sie sind zu klein. Wie kann ich sie zurückschicken?",
+		"fr": "Bonjour, j'ai commandé une paire de chaussures la semaine dernière mais elles sont trop petites. Comment puis-je les retourner ?",
+		"es": "Hola, la semana pasada pedí un par de zapatos pero son demasiado pequeños. ¿Cómo puedo devolverlos?",
+		"it": "Ciao, la settimana scorsa ho ordinato un paio di scarpe ma sono troppo piccole. Come posso restituirle?",
+		"pt": "Olá, eu encomendei um par de sapatos na semana passada mas eles são muito pequenos. Como posso devolvê-los?",
+	}
+
+	for expected, text := range tests {
+		lang, confidence := DetectLanguage(text)
+		require.Equal(t, expected, lang.String(), text)
+		require.Greater(t, confidence, 0.0, text)
+		require.LessOrEqual(t, confidence, 1.0, text)
+	}
+
+	lang, confidence := DetectLanguage("1234 !!")
+	require.True(t, lang.Empty())
+	require.Equal(t, 0.0, confidence)
+}
+
+func TestNgramDetector(t *testing.T) {
+	detector := NewNgramDetector(map[LanguageID]string{
+		{Language: "en"}: "the cat sat on the mat with the hat",
+		{Language: "nl"}: "de kat zat op de mat met de hoed",
+	})
+
+	lang, _ := detector.Detect("the hat")
+	require.Equal(t, "en", lang.String())
+
+	lang, _ = detector.Detect("de kat")
+	require.Equal(t, "nl", lang.String())
+
+	lang, confidence := detector.Detect("xyz")
+	require.True(t, lang.Empty())
+	require.Equal(t, 0.0, confidence)
+}
@@ -0,0 +1,133 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+// Change is a message or attribute that differs between two catalogs, see Diff.
+type Change struct {
+	Key string
+	// Attribute is true if the key is an attribute instead of a message.
+	Attribute bool
+	// Old is the value in the first catalog, it is empty for an added key.
+	Old string
+	// New is the value in the second catalog, it is empty for a removed key.
+	New string
+}
+
+// Changes holds the differences between two catalogs, the changes are sorted by key with the messages before the attributes.
+type Changes struct {
+	Added    []Change
+	Removed  []Change
+	Modified []Change
+}
+
+// Empty returns true if the catalogs are the same.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0
+}
+
+// Diff returns the messages and attributes that are added, removed and modified in b compared to a.
+// A nil catalog has no messages, e.g. to diff a language that only exists in one directory.
+func Diff(a, b *RawMessages) Changes {
+	if a == nil {
+		a = &RawMessages{}
+	}
+	if b == nil {
+		b = &RawMessages{}
+	}
+
+	var changes Changes
+	changes.diff(a.Messages, b.Messages, false)
+	changes.diff(a.Attributes, b.Attributes, true)
+
+	return changes
+}
+
+// diff adds the differences between the values of a and b to the changes.
+func (c *Changes) diff(a, b map[string]string, attribute bool) {
+	for _, key := range sorted.Keys(a) {
+		newValue, ok := b[key]
+		if !ok {
+			c.Removed = append(c.Removed, Change{Key: key, Attribute: attribute, Old: a[key]})
+			continue
+		}
+
+		if newValue != a[key] {
+			c.Modified = append(c.Modified, Change{Key: key, Attribute: attribute, Old: a[key], New: newValue})
+		}
+	}
+
+	for _, key := range sorted.Keys(b) {
+		if _, ok := a[key]; !ok {
+			c.Added = append(c.Added, Change{Key: key, Attribute: attribute, New: b[key]})
+		}
+	}
+}
+
+// DiffDirs returns the changes of every language in dirB compared to dirA, languages without changes are omitted.
+// The messages of a language that only exists in one of the directories are all added or removed.
+func DiffDirs(fs afero.Fs, dirA, dirB string, opts ...ParserOpt) (map[LanguageID]Changes, error) {
+	parser := NewParser(fs, opts...)
+
+	a, err := rawMessagesFromDir(parser, dirA)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := rawMessagesFromDir(parser, dirB)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make(map[LanguageID]Changes)
+	for lang := range b {
+		if _, ok := a[lang]; !ok {
+			a[lang] = nil
+		}
+	}
+
+	for lang, messages := range a {
+		changes := Diff(messages, b[lang])
+		if !changes.Empty() {
+			diffs[lang] = changes
+		}
+	}
+
+	return diffs, nil
+}
+
+// rawMessagesFromDir reads the translation files in the directory by language.
+// The files that can not be read are returned as LanguageErrors with the languages that are read.
+func rawMessagesFromDir(parser *Parser, dir string) (map[LanguageID]*RawMessages, error) {
+	files, err := parser.TranslationFilesFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make(map[LanguageID]*RawMessages, len(files))
+	errs := LanguageErrors{}
+	for languageID, file := range files {
+		lang, err := ParseLanguage(languageID)
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err := parser.messagesWithOverlay(file)
+		if err != nil {
+			errs[lang] = fmt.Errorf("reading file %s: %w", file, err)
+			continue
+		}
+
+		languages[lang] = raw
+	}
+
+	if len(errs) > 0 {
+		return languages, errs
+	}
+
+	return languages, nil
+}
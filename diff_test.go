@@ -0,0 +1,52 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	a := &RawMessages{
+		Messages:   map[string]string{"welcome": "Welcome", "bye": "Bye", "color": "Color"},
+		Attributes: map[string]string{"email": "email"},
+	}
+	b := &RawMessages{
+		Messages:   map[string]string{"welcome": "Welcome!", "color": "Color", "cart": "Cart"},
+		Attributes: map[string]string{"email": "email address", "name": "name"},
+	}
+
+	changes := Diff(a, b)
+	require.Equal(t, []Change{{Key: "cart", New: "Cart"}, {Key: "name", Attribute: true, New: "name"}}, changes.Added)
+	require.Equal(t, []Change{{Key: "bye", Old: "Bye"}}, changes.Removed)
+	require.Equal(t, []Change{
+		{Key: "welcome", Old: "Welcome", New: "Welcome!"},
+		{Key: "email", Attribute: true, Old: "email", New: "email address"},
+	}, changes.Modified)
+	require.False(t, changes.Empty())
+
+	require.True(t, Diff(a, a).Empty())
+	require.Equal(t, []Change{{Key: "bye", Old: "Bye"}, {Key: "color", Old: "Color"}, {Key: "welcome", Old: "Welcome"}, {Key: "email", Attribute: true, Old: "email"}}, Diff(a, nil).Removed)
+}
+
+func TestDiffDirs(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "a/en.json", []byte(`{"welcome": "Welcome", "bye": "Bye"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "a/nl.json", []byte(`{"welcome": "Welkom"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "a/de.json", []byte(`{"welcome": "Willkommen"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "b/en.json", []byte(`{"welcome": "Welcome", "bye": "Goodbye"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "b/nl.json", []byte(`{"welcome": "Welkom"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "b/fr.json", []byte(`{"welcome": "Bienvenue"}`), 0o644))
+
+	diffs, err := DiffDirs(fs, "a", "b")
+	require.NoError(t, err)
+	require.Equal(t, map[LanguageID]Changes{
+		{Language: "en"}: {Modified: []Change{{Key: "bye", Old: "Bye", New: "Goodbye"}}},
+		{Language: "de"}: {Removed: []Change{{Key: "welcome", Old: "Willkommen"}}},
+		{Language: "fr"}: {Added: []Change{{Key: "welcome", New: "Bienvenue"}}},
+	}, diffs)
+
+	_, err = DiffDirs(fs, "a", "missing")
+	require.Error(t, err)
+}
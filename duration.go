@@ -0,0 +1,141 @@
+package messages
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// durationUnit is a unit of a humanized duration with the singular and plural name.
+type durationUnit struct {
+	size             time.Duration
+	singular, plural string
+}
+
+// durationUnits holds the units per language, from large to small.
+var durationUnits = map[string][]durationUnit{
+	"en": {
+		{24 * time.Hour, "day", "days"},
+		{time.Hour, "hour", "hours"},
+		{time.Minute, "minute", "minutes"},
+		{time.Second, "second", "seconds"},
+		{time.Millisecond, "millisecond", "milliseconds"},
+	},
+	"nl": {
+		{24 * time.Hour, "dag", "dagen"},
+		{time.Hour, "uur", "uur"},
+		{time.Minute, "minuut", "minuten"},
+		{time.Second, "seconde", "seconden"},
+		{time.Millisecond, "milliseconde", "milliseconden"},
+	},
+	"de": {
+		{24 * time.Hour, "Tag", "Tage"},
+		{time.Hour, "Stunde", "Stunden"},
+		{time.Minute, "Minute", "Minuten"},
+		{time.Second, "Sekunde", "Sekunden"},
+		{time.Millisecond, "Millisekunde", "Millisekunden"},
+	},
+	"fr": {
+		{24 * time.Hour, "jour", "jours"},
+		{time.Hour, "heure", "heures"},
+		{time.Minute, "minute", "minutes"},
+		{time.Second, "seconde", "secondes"},
+		{time.Millisecond, "milliseconde", "millisecondes"},
+	},
+	"es": {
+		{24 * time.Hour, "día", "días"},
+		{time.Hour, "hora", "horas"},
+		{time.Minute, "minuto", "minutos"},
+		{time.Second, "segundo", "segundos"},
+		{time.Millisecond, "milisegundo", "milisegundos"},
+	},
+}
+
+// defaultDurationPrecision is the number of units that is used when the duration modifier has no argument.
+const defaultDurationPrecision = 2
+
+// durationModifier humanizes a duration, 2h5m is formatted as "2 hours 5 minutes" in English.
+// The value can be a time.Duration, a number of seconds or a string that is parsed with time.ParseDuration.
+// The argument is the maximum number of units, which defaults to 2. Smaller units are truncated.
+// Languages without unit names use the English names.
+func durationModifier(lang language.Tag, value any, arg string) string {
+	d, ok := durationValue(value)
+	if !ok {
+		return formatReplacement(value)
+	}
+
+	base, _ := lang.Base()
+	units, ok := durationUnits[base.String()]
+	if !ok {
+		units = durationUnits["en"]
+	}
+
+	precision := intArg(arg, defaultDurationPrecision)
+	if precision == 0 {
+		precision = defaultDurationPrecision
+	}
+
+	negative := d < 0
+	if negative {
+		d = -d
+	}
+
+	var parts []string
+	for _, unit := range units {
+		if len(parts) == precision {
+			break
+		}
+
+		n := d / unit.size
+		d -= n * unit.size
+		if n == 0 {
+			// Skip leading zero units, but stop at a zero unit after the first unit.
+			// "2 hours 5 seconds" is not a useful approximation of 2h0m5s.
+			if len(parts) > 0 {
+				break
+			}
+			continue
+		}
+
+		name := unit.plural
+		if n == 1 {
+			name = unit.singular
+		}
+		parts = append(parts, strconv.FormatInt(int64(n), 10)+" "+name)
+	}
+
+	if len(parts) == 0 {
+		for _, unit := range units {
+			if unit.size == time.Second {
+				return "0 " + unit.plural
+			}
+		}
+	}
+
+	formatted := strings.Join(parts, " ")
+	if negative {
+		formatted = "-" + formatted
+	}
+
+	return formatted
+}
+
+// durationValue returns value as a time.Duration.
+func durationValue(value any) (time.Duration, bool) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, true
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+
+	seconds, ok := numericValue(value)
+	if !ok {
+		return 0, false
+	}
+
+	return time.Duration(seconds * float64(time.Second)), true
+}
@@ -0,0 +1,41 @@
+package messages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestDurationModifier(t *testing.T) {
+	cases := []struct {
+		lang     string
+		value    any
+		arg      string
+		expected string
+	}{
+		{lang: "en", value: 2*time.Hour + 5*time.Minute + 30*time.Second, expected: "2 hours 5 minutes"},
+		{lang: "en", value: 2*time.Hour + 5*time.Minute + 30*time.Second, arg: "3", expected: "2 hours 5 minutes 30 seconds"},
+		{lang: "en", value: 2*time.Hour + 5*time.Minute, arg: "1", expected: "2 hours"},
+		{lang: "en", value: 2*time.Hour + 5*time.Second, expected: "2 hours"},
+		{lang: "en", value: 26 * time.Hour, expected: "1 day 2 hours"},
+		{lang: "en", value: time.Minute, expected: "1 minute"},
+		{lang: "en", value: -90 * time.Second, expected: "-1 minute 30 seconds"},
+		{lang: "en", value: 1500 * time.Millisecond, expected: "1 second 500 milliseconds"},
+		{lang: "en", value: time.Duration(0), expected: "0 seconds"},
+		{lang: "en", value: 90, expected: "1 minute 30 seconds"},
+		{lang: "en", value: "1h30m", expected: "1 hour 30 minutes"},
+		{lang: "nl", value: 3 * time.Hour, expected: "3 uur"},
+		{lang: "de", value: 48 * time.Hour, expected: "2 Tage"},
+		// Languages without unit names use English.
+		{lang: "ja", value: time.Hour, expected: "1 hour"},
+		{lang: "en", value: "not a duration", expected: "not a duration"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.expected, func(t *testing.T) {
+			require.Equal(t, c.expected, durationModifier(language.Make(c.lang), c.value, c.arg))
+		})
+	}
+}
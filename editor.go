@@ -0,0 +1,252 @@
+package messages
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+//go:embed editor.html
+var editorFS embed.FS
+
+var editorTemplate = template.Must(template.ParseFS(editorFS, "editor.html"))
+
+// Editor is an http.Handler that serves a minimal web UI to browse, search and edit the translation files in a directory.
+// The editor has no authentication, wrap it in the authentication and CSRF protection of your application:
+//
+//	mux.Handle("/translations/", requireAdmin(http.StripPrefix("/translations", messages.NewEditor(fs, "translations"))))
+//
+// Edits are validated by loading the translation files with the edit like NewTranslator does, and written to the translation file.
+type Editor struct {
+	dir string
+	fs  afero.Fs
+	// Options of the Translator that loads the translation files, see validate.
+	opts       []Opt
+	parserOpts []ParserOpt
+	saver      Saver
+	// Audit receives the edits, see SetAuditHook.
+	audit AuditHook
+	// Mu serializes the edits, so two edits of the same language don't overwrite each other. It also protects the audit hook.
+	mu sync.Mutex
+}
+
+// NewEditor returns an editor for the translation files in dir.
+// The options should be the options of the Translator that loads the translations, e.g. WithModifier and WithParserOpts,
+// so an edit that the Translator can not load is not saved.
+func NewEditor(fs afero.Fs, dir string, opts ...Opt) *Editor {
+	parserOpts := newTranslator(opts...).parserOpts
+
+	return &Editor{
+		dir:        dir,
+		fs:         fs,
+		opts:       opts,
+		parserOpts: parserOpts,
+		saver:      NewFileStore(fs, dir, parserOpts...),
+	}
+}
+
+// newParser returns a parser for one request.
+// A Parser is not safe for concurrent use and interns every value it parses, so it is not shared between requests.
+func (e *Editor) newParser() *Parser {
+	return NewParser(e.fs, e.parserOpts...)
+}
+
+// validate loads the translation files with the translations of the language like NewTranslator, so the modifiers,
+// templates and ICU messages are checked. The translations are written to a copy on write layer over the files.
+func (e *Editor) validate(lang LanguageID, translations *RawMessages) error {
+	overlay := afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(e.fs), afero.NewMemMapFs())
+	if err := NewFileStore(overlay, e.dir, e.parserOpts...).Save(lang, translations); err != nil {
+		return err
+	}
+
+	_, err := NewTranslator(overlay, e.dir, e.opts...)
+	return err
+}
+
+// SetAuditHook calls the hook after every edit, the actor of the event is the actor of the request context, see WithActor.
+func (e *Editor) SetAuditHook(hook AuditHook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.audit = hook
+}
+
+// editorPage is the data of the editor template.
+type editorPage struct {
+	Languages []string
+	Lang      string
+	Reference string
+	Query     string
+	Rows      []editorRow
+	Saved     string
+	Error     string
+}
+
+type editorRow struct {
+	Key       string
+	Reference string
+	Value     string
+}
+
+func (e *Editor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		e.render(w, r, http.StatusOK, editorPage{Saved: r.URL.Query().Get("saved")})
+	case http.MethodPost:
+		e.save(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// render renders the messages of the language that match the query with the status.
+func (e *Editor) render(w http.ResponseWriter, r *http.Request, status int, page editorPage) {
+	parser := e.newParser()
+	files, err := parser.TranslationFilesFromDir(e.dir)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	page.Languages = sorted.Keys(files)
+	if len(page.Languages) == 0 {
+		http.Error(w, fmt.Sprintf("there are no translation files in dir %s", e.dir), http.StatusNotFound)
+		return
+	}
+
+	page.Lang = editorLanguage(r.FormValue("lang"), files, page.Languages[0])
+	page.Reference = editorLanguage(r.FormValue("ref"), files, page.Languages[0])
+	page.Query = r.FormValue("q")
+
+	translations, err := parser.MessagesFromFile(files[page.Lang])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	reference, err := parser.MessagesFromFile(files[page.Reference])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Keys of the reference language that are missing in the language are shown so they can be translated.
+	keys := make(map[string]bool)
+	for key := range translations.Messages {
+		keys[key] = true
+	}
+	for key := range reference.Messages {
+		keys[key] = true
+	}
+
+	query := strings.ToLower(page.Query)
+	for _, key := range sorted.Keys(keys) {
+		row := editorRow{Key: key, Reference: reference.Messages[key], Value: translations.Messages[key]}
+		if query != "" && !strings.Contains(strings.ToLower(row.Key+"\n"+row.Reference+"\n"+row.Value), query) {
+			continue
+		}
+
+		page.Rows = append(page.Rows, row)
+	}
+
+	var buf bytes.Buffer
+	if err := editorTemplate.Execute(&buf, page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = buf.WriteTo(w)
+}
+
+// save validates the message in the form and writes it to the translation file.
+func (e *Editor) save(w http.ResponseWriter, r *http.Request) {
+	lang, key, value := r.FormValue("lang"), r.FormValue("key"), r.FormValue("value")
+
+	err := e.saveMessage(r.Context(), lang, key, value)
+	if err != nil {
+		e.render(w, r, http.StatusUnprocessableEntity, editorPage{Error: err.Error()})
+		return
+	}
+
+	query := url.Values{"lang": {lang}, "ref": {r.FormValue("ref")}, "q": {r.FormValue("q")}, "saved": {key}}
+	http.Redirect(w, r, "?"+query.Encode(), http.StatusSeeOther)
+}
+
+func (e *Editor) saveMessage(ctx context.Context, lang, key, value string) error {
+	if key == "" || key == attributesKey {
+		return fmt.Errorf("invalid key %q", key)
+	}
+
+	parser := e.newParser()
+	files, err := parser.TranslationFilesFromDir(e.dir)
+	if err != nil {
+		return err
+	}
+
+	file, ok := files[lang]
+	if !ok {
+		return fmt.Errorf("language %s not found", lang)
+	}
+
+	languageID, err := ParseLanguage(lang)
+	if err != nil {
+		return err
+	}
+
+	value = parser.normalizeSpace(value)
+	msg, err := parser.parseMessage(key, value)
+	if err != nil {
+		return err
+	}
+
+	if !parser.allowMixedCase(lang) {
+		if err := checkReplacementCase(filepath.Base(file), key, msg); err != nil {
+			return err
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	translations, err := parser.MessagesFromFile(file)
+	if err != nil {
+		return err
+	}
+
+	old := translations.Messages[key]
+	translations.Messages[key] = value
+
+	if err := e.validate(languageID, translations); err != nil {
+		return err
+	}
+
+	if err := e.saver.Save(languageID, translations); err != nil {
+		return err
+	}
+
+	audit(ctx, e.audit, "editor", languageID, Key(key), old, value)
+
+	return nil
+}
+
+// editorLanguage returns the language if it has a translation file, otherwise the fallback.
+func editorLanguage(lang string, files map[string]string, fallback string) string {
+	if _, ok := files[lang]; ok {
+		return lang
+	}
+
+	return fallback
+}
@@ -0,0 +1,132 @@
+package messages
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditor(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome :User", "color": "Pick a color"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom :User"}`), 0o644))
+
+	editor := NewEditor(fs, "translations")
+
+	// The keys of the reference language that are missing are listed.
+	rec := httptest.NewRecorder()
+	editor.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?lang=nl&ref=en", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "Welkom :User")
+	require.Contains(t, rec.Body.String(), "Pick a color")
+
+	// Search.
+	rec = httptest.NewRecorder()
+	editor.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?lang=nl&ref=en&q=COLOR", nil))
+	require.Contains(t, rec.Body.String(), "Pick a color")
+	require.NotContains(t, rec.Body.String(), "Welkom :User")
+
+	// Save a message.
+	rec = httptest.NewRecorder()
+	editor.ServeHTTP(rec, postForm(url.Values{"lang": {"nl"}, "key": {"color"}, "value": {"Kies een kleur"}}))
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+
+	raw, err := NewParser(fs).MessagesFromFile("translations/nl.json")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"welcome": "Welkom :User", "color": "Kies een kleur"}, raw.Messages)
+
+	// Invalid messages are not saved.
+	rec = httptest.NewRecorder()
+	editor.ServeHTTP(rec, postForm(url.Values{"lang": {"nl"}, "key": {"welcome"}, "value": {"Welkom :user en :User"}}))
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	require.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), "duplicate replacement with different case")
+
+	rec = httptest.NewRecorder()
+	editor.ServeHTTP(rec, postForm(url.Values{"lang": {"de"}, "key": {"welcome"}, "value": {"Willkommen"}}))
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// Messages that the Translator can not load are not saved.
+	rec = httptest.NewRecorder()
+	editor.ServeHTTP(rec, postForm(url.Values{"lang": {"nl"}, "key": {"welcome"}, "value": {"Welkom :User|shout(1)"}}))
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	require.Contains(t, rec.Body.String(), ErrUnknownModifier.Error())
+
+	// The modifiers of the Translator options can be used.
+	rec = httptest.NewRecorder()
+	editor = NewEditor(fs, "translations", WithModifier("shout", shout))
+	editor.ServeHTTP(rec, postForm(url.Values{"lang": {"nl"}, "key": {"color"}, "value": {"Kies een :Kleur|shout"}}))
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+
+	raw, err = NewParser(fs).MessagesFromFile("translations/nl.json")
+	require.NoError(t, err)
+	require.Equal(t, "Welkom :User", raw.Messages["welcome"])
+	require.Equal(t, "Kies een :Kleur|shout", raw.Messages["color"])
+}
+
+func TestEditorAudit(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom"}`), 0o644))
+
+	var events []AuditEvent
+	editor := NewEditor(fs, "translations")
+	editor.SetAuditHook(func(ctx context.Context, event AuditEvent) {
+		events = append(events, event)
+	})
+
+	r := postForm(url.Values{"lang": {"nl"}, "key": {"welcome"}, "value": {"Hallo"}})
+	r = r.WithContext(WithActor(r.Context(), "jan@example.com"))
+	editor.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Saving the same message again is not audited.
+	editor.ServeHTTP(httptest.NewRecorder(), postForm(url.Values{"lang": {"nl"}, "key": {"welcome"}, "value": {"Hallo"}}))
+
+	require.Len(t, events, 1)
+	require.Equal(t, "jan@example.com", events[0].Actor)
+	require.Equal(t, "editor", events[0].Source)
+	require.Equal(t, LanguageID{Language: "nl"}, events[0].Language)
+	require.Equal(t, Key("welcome"), events[0].Key)
+	require.Equal(t, "Welkom", events[0].Old)
+	require.Equal(t, "Hallo", events[0].New)
+	require.False(t, events[0].Time.IsZero())
+}
+
+func TestEditorConcurrentSaves(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"a": "A", "b": "B"}`), 0o644))
+
+	editor := NewEditor(fs, "translations")
+
+	// The requests don't share a parser, run with -race.
+	var wg sync.WaitGroup
+	for i := range 20 {
+		key := []string{"a", "b"}[i%2]
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			editor.ServeHTTP(httptest.NewRecorder(), postForm(url.Values{"lang": {"nl"}, "key": {key}, "value": {"Hallo :name"}}))
+		}()
+		go func() {
+			defer wg.Done()
+			editor.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?lang=nl", nil))
+		}()
+	}
+	wg.Wait()
+
+	raw, err := NewParser(fs).MessagesFromFile("translations/nl.json")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "Hallo :name", "b": "Hallo :name"}, raw.Messages)
+}
+
+func postForm(form url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
@@ -0,0 +1,48 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+)
+
+// Overlay files like en.production.json or en_US.staging.json, see WithEnvironment.
+var isOverlayFile = regexp.MustCompile(`^[a-zA-Z]{2}(?:[-_][a-zA-Z]{2})?\.[a-zA-Z0-9_-]+\.json$`)
+
+// WithEnvironment merges the overlay file of the environment over the translation file of a language when the translations are loaded,
+// e.g. en.production.json over en.json for the environment "production". The overlay only has the messages that differ in the environment.
+// Overlay files of other environments are ignored, and the translation files are saved without the overlay.
+func WithEnvironment(name string) ParserOpt {
+	return func(p *Parser) {
+		p.environment = name
+	}
+}
+
+// overlayFile returns the overlay file of the environment for the translation file, e.g. "en.production.json" for "en.json".
+func overlayFile(file, environment string) string {
+	return strings.TrimSuffix(file, ".json") + "." + environment + ".json"
+}
+
+// messagesWithOverlay reads the translation file and merges the overlay of the environment over it.
+func (p *Parser) messagesWithOverlay(file string) (*RawMessages, error) {
+	raw, err := p.MessagesFromFile(file)
+	if err != nil || p.environment == "" {
+		return raw, err
+	}
+
+	overlay := overlayFile(file, p.environment)
+	if _, err := p.fs.Stat(overlay); errors.Is(err, fs.ErrNotExist) {
+		return raw, nil
+	}
+
+	overlayMessages, err := p.MessagesFromFile(overlay)
+	if err != nil {
+		return nil, fmt.Errorf("reading overlay %s: %w", overlay, err)
+	}
+
+	mergeMessages(raw, overlayMessages)
+
+	return raw, nil
+}
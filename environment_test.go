@@ -0,0 +1,39 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentOverlays(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome", "terms": "Read the terms"}`), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "translations/en.production.json", []byte(`{"terms": "Read the terms and conditions"}`), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "translations/en.staging.json", []byte(`{"terms": "Staging terms"}`), 0o600))
+
+	ctx, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	// Without an environment the overlays are ignored.
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+	require.Equal(t, "Read the terms", tr.Translate(ctx, "terms", nil))
+
+	tr, err = NewTranslator(fs, "translations", WithParserOpts(WithEnvironment("production")))
+	require.NoError(t, err)
+	require.Equal(t, "Read the terms and conditions", tr.Translate(ctx, "terms", nil))
+	require.Equal(t, "Welcome", tr.Translate(ctx, "welcome", nil))
+
+	// A changed overlay is reloaded.
+	require.NoError(t, afero.WriteFile(fs, "translations/en.production.json", []byte(`{"terms": "Accept the terms and conditions"}`), 0o600))
+	require.NoError(t, tr.Reload(ctx))
+	require.Equal(t, "Accept the terms and conditions", tr.Translate(ctx, "terms", nil))
+
+	// An environment without overlays uses the translation files.
+	tr, err = NewTranslator(fs, "translations", WithParserOpts(WithEnvironment("test")))
+	require.NoError(t, err)
+	require.Equal(t, "Read the terms", tr.Translate(ctx, "terms", nil))
+}
@@ -1,15 +1,21 @@
 package messages
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 
+	"golang.org/x/text/unicode/norm"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -18,94 +24,489 @@ const (
 )
 
 var (
-	ErrInvalidTranslationKey = fmt.Errorf("restricted translation key: attributes")
+	ErrInvalidTranslationKey = fmt.Errorf("restricted translation key")
 )
 
+// DefaultReservedKeys are the keys that can not be used as a translation key because they are a section of the translation files.
+// The "attributes" key is always reserved, see WithReservedKeys.
+var DefaultReservedKeys = []string{attributesKey}
+
+// ExtractOpt is a functional option for TranslationKeysFromSourceCode.
+type ExtractOpt func(*extractConfig)
+
+type extractConfig struct {
+	// The fully qualified type names of translation keys.
+	keyTypes []string
+	// Glob patterns of directories that are not searched for go files.
+	excludes []string
+	// Build tags that are used when loading packages.
+	buildTags []string
+	// Include test files.
+	tests bool
+	// Extract keys passed to functions that forward a string parameter as a translation key.
+	followWrappers bool
+	// Optional callback for package errors. When set, package errors do not stop the extraction.
+	reportErrors func(error)
+	// Optional callback that is called after every processed directory.
+	progress func(Progress)
+	// Directory names that are skipped while searching for go files.
+	skipDirs []string
+	// Search hidden directories (directories that start with a dot).
+	hiddenDirs bool
+	// Skip directories that are ignored by .gitignore files.
+	gitignore bool
+	// Follow symlinked directories.
+	followSymlinks bool
+	// Keys that can not be used as a translation key.
+	reservedKeys []string
+}
+
+func newExtractConfig(opts ...ExtractOpt) *extractConfig {
+	cfg := &extractConfig{
+		keyTypes:     []string{keyType},
+		skipDirs:     defaultSkipDirs,
+		gitignore:    true,
+		reservedKeys: DefaultReservedKeys,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// Progress describes the state of a running extraction.
+type Progress struct {
+	// Dir is the directory that has just been processed.
+	Dir string
+	// DirsDone is the number of directories processed so far, DirsTotal the number of directories that will be processed.
+	DirsDone  int
+	DirsTotal int
+	// Packages is the number of packages loaded so far.
+	Packages int
+	// Keys is the number of unique translation keys found so far.
+	Keys int
+}
+
+// WithProgress calls fn after every processed directory.
+// This can be used to report progress for large source trees.
+func WithProgress(fn func(Progress)) ExtractOpt {
+	return func(c *extractConfig) {
+		c.progress = fn
+	}
+}
+
+// WithSkipDirs replaces the directory names that are skipped while searching for go files.
+// By default .git, node_modules and vendor are skipped.
+func WithSkipDirs(names ...string) ExtractOpt {
+	return func(c *extractConfig) {
+		c.skipDirs = names
+	}
+}
+
+// WithHiddenDirs also searches hidden directories (directories that start with a dot), which are skipped by default.
+func WithHiddenDirs() ExtractOpt {
+	return func(c *extractConfig) {
+		c.hiddenDirs = true
+	}
+}
+
+// WithoutGitignore searches directories that are ignored by .gitignore files, which are skipped by default.
+func WithoutGitignore() ExtractOpt {
+	return func(c *extractConfig) {
+		c.gitignore = false
+	}
+}
+
+// WithFollowSymlinks follows symlinked directories, which are skipped by default.
+// Every directory is searched at most once, so symlink cycles are safe.
+func WithFollowSymlinks() ExtractOpt {
+	return func(c *extractConfig) {
+		c.followSymlinks = true
+	}
+}
+
+// WithKeyType replaces the fully qualified type name (github.com/wvell/messages.Key) that is searched for translation keys.
+func WithKeyType(typ string) ExtractOpt {
+	return func(c *extractConfig) {
+		c.keyTypes = []string{typ}
+	}
+}
+
+// WithKeyTypes adds fully qualified type names that are treated as translation keys next to github.com/wvell/messages.Key.
+// This is useful when the package is wrapped in a facade with its own key type, e.g. "example.com/i18n.MsgID".
+func WithKeyTypes(types ...string) ExtractOpt {
+	return func(c *extractConfig) {
+		c.keyTypes = append(slices.Clone(c.keyTypes), types...)
+	}
+}
+
+// isKeyType reports if typ is one of the configured key types.
+// Aliases match both on the alias name and on the aliased type.
+func (c *extractConfig) isKeyType(typ types.Type) bool {
+	return slices.Contains(c.keyTypes, typ.String()) || slices.Contains(c.keyTypes, types.Unalias(typ).String())
+}
+
+// WithReservedKeys replaces the keys that can not be used as a translation key, DefaultReservedKeys by default.
+// The "attributes" key is always reserved, because it is the section of the attributes in the translation files.
+func WithReservedKeys(keys ...string) ExtractOpt {
+	return func(c *extractConfig) {
+		c.reservedKeys = keys
+	}
+}
+
+// WithExcludes skips directories that match one of the glob patterns.
+// A pattern is matched against the directory name and the slash separated path relative to the searched directory,
+// e.g. "mocks" or "internal/generated/*".
+func WithExcludes(patterns ...string) ExtractOpt {
+	return func(c *extractConfig) {
+		c.excludes = append(c.excludes, patterns...)
+	}
+}
+
+// WithBuildTags loads packages with the given build tags.
+func WithBuildTags(tags ...string) ExtractOpt {
+	return func(c *extractConfig) {
+		c.buildTags = append(c.buildTags, tags...)
+	}
+}
+
+// WithTests also extracts translation keys from _test.go files.
+func WithTests() ExtractOpt {
+	return func(c *extractConfig) {
+		c.tests = true
+	}
+}
+
+// WithFollowWrappers also extracts keys that are passed as a string to a function that uses the string as a translation key.
+// For example the key "welcome" is extracted from T(ctx, "welcome") with:
+//
+//	func T(ctx context.Context, key string) string {
+//		return tr.Translate(ctx, messages.Key(key), nil)
+//	}
+func WithFollowWrappers() ExtractOpt {
+	return func(c *extractConfig) {
+		c.followWrappers = true
+	}
+}
+
+// WithReportErrors passes package errors, like compile errors, to fn instead of failing the extraction.
+// Translation keys are still extracted from packages with errors as far as possible.
+func WithReportErrors(fn func(error)) ExtractOpt {
+	return func(c *extractConfig) {
+		c.reportErrors = fn
+	}
+}
+
 // TranslationKeysFromSourceCode finds all translation key's used in go source files.
 // It will parse dir and every subdirectory recursively for go files and search for instances of messages.Key.
-func TranslationKeysFromSourceCode(dir string) ([]string, error) {
-	dirs, err := findDirsRecursively(dir)
+func TranslationKeysFromSourceCode(dir string, opts ...ExtractOpt) ([]string, error) {
+	return TranslationKeysFromSourceCodeCtx(context.Background(), dir, opts...)
+}
+
+// TranslationKeysFromSourceCodeCtx is like TranslationKeysFromSourceCode but stops when ctx is done.
+// On cancellation the keys found so far are returned together with an error that wraps ctx.Err().
+// Reserved keys are left out, the other keys are returned together with an error that wraps ErrInvalidTranslationKey.
+func TranslationKeysFromSourceCodeCtx(ctx context.Context, dir string, opts ...ExtractOpt) ([]string, error) {
+	extraction, err := ExtractFromSourceCodeCtx(ctx, dir, opts...)
+	if extraction == nil {
+		return nil, err
+	}
+
+	if err == nil && len(extraction.InvalidKeys) > 0 {
+		errs := make([]error, 0, len(extraction.InvalidKeys))
+		for _, invalid := range extraction.InvalidKeys {
+			errs = append(errs, invalid)
+		}
+		err = errors.Join(errs...)
+	}
+
+	return extraction.Keys, err
+}
+
+// Extraction holds everything that is extracted from the go source files.
+type Extraction struct {
+	// Keys holds the translation keys.
+	Keys []string
+	// Attributes holds the constant values that are passed as the :attribute replacement, for example "first_name" in:
+	//
+	//	tr.Translate(ctx, "required", map[string]any{messages.AttributeKey: "first_name"})
+	Attributes []string
+	// InvalidKeys holds the reserved keys that are found, they are not part of Keys.
+	InvalidKeys []InvalidKey
+}
+
+// InvalidKey is a reserved key that is used as a translation key in the source code.
+type InvalidKey struct {
+	Key      string
+	Position token.Position
+}
+
+func (e InvalidKey) Error() string {
+	return fmt.Sprintf("%s: %s %q", e.Position, ErrInvalidTranslationKey, e.Key)
+}
+
+func (e InvalidKey) Unwrap() error {
+	return ErrInvalidTranslationKey
+}
+
+// foundKey is a translation key and the position where it is found.
+type foundKey struct {
+	key string
+	pos token.Position
+}
+
+// ExtractFromSourceCodeCtx is like TranslationKeysFromSourceCodeCtx but also extracts the attributes.
+func ExtractFromSourceCodeCtx(ctx context.Context, dir string, opts ...ExtractOpt) (*Extraction, error) {
+	cfg := newExtractConfig(opts...)
+
+	dirs, err := findDirsRecursively(dir, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	var translations []string
-	for _, dir := range dirs {
-		fset := token.NewFileSet()
+	var wrappers *wrapperFinder
+	if cfg.followWrappers {
+		wrappers = newWrapperFinder(cfg)
+	}
+
+	var translations, attributes []string
+	var invalid []InvalidKey
+	// found adds a translation key, reserved keys are added to the invalid keys instead.
+	found := func(translation string, pos token.Position) {
+		if key := catalogKey(translation); key == attributesKey || slices.Contains(cfg.reservedKeys, key) {
+			invalid = append(invalid, InvalidKey{Key: key, Position: pos})
+			return
+		}
 
-		mode := packages.NeedName | packages.NeedSyntax |
-			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedCompiledGoFiles
+		translations = append(translations, translation)
+	}
+	// partial returns the extraction so far, it is returned when the extraction is cancelled.
+	partial := func() *Extraction {
+		keys := make([]string, 0, len(translations))
+		for _, translation := range translations {
+			keys = append(keys, catalogKey(translation))
+		}
+
+		// The keys are sorted, the order in which they are found depends on map iteration.
+		extraction := &Extraction{Keys: removeDuplicates(keys), Attributes: removeDuplicates(attributes), InvalidKeys: slices.Clone(invalid)}
+		slices.Sort(extraction.Keys)
+		slices.Sort(extraction.Attributes)
+		slices.SortFunc(extraction.InvalidKeys, func(a, b InvalidKey) int {
+			return cmp.Or(cmp.Compare(a.Position.Filename, b.Position.Filename), cmp.Compare(a.Position.Offset, b.Position.Offset))
+		})
+		// A call and its argument are both found, they are reported once per line.
+		extraction.InvalidKeys = slices.CompactFunc(extraction.InvalidKeys, func(a, b InvalidKey) bool {
+			return a.Key == b.Key && a.Position.Filename == b.Position.Filename && a.Position.Line == b.Position.Line
+		})
+
+		return extraction
+	}
 
-		cfg := &packages.Config{
-			Mode:  mode,
-			Dir:   dir,
-			Fset:  fset,
-			Tests: false,
+	progress := Progress{DirsTotal: len(dirs)}
+	seen := make(map[string]bool)
+	counted := 0
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return partial(), fmt.Errorf("extraction stopped at %s: %w", dir, err)
 		}
 
-		pkgs, err := packages.Load(cfg)
+		pkgs, err := loadPackages(ctx, cfg, dir)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return partial(), fmt.Errorf("extraction stopped at %s: %w", dir, ctxErr)
+		}
 		if err != nil {
-			return nil, fmt.Errorf("loading package: %w", err)
+			return nil, err
 		}
 
-		pkgsErrs := ""
-		packages.Visit(pkgs, nil, func(pkg *packages.Package) {
-			for _, err := range pkg.Errors {
-				if strings.HasPrefix(err.Msg, "build constraints exclude all Go files") {
-					continue
-				}
+		for _, pkg := range pkgs {
+			if pkg.TypesInfo == nil {
+				continue
+			}
 
-				pkgsErrs += err.Error() + "\n"
+			ignored := ignoredInPackage(pkg)
+			if wrappers != nil {
+				wrappers.collect(pkg, ignored)
 			}
-		})
-		if pkgsErrs != "" {
-			return nil, fmt.Errorf("package load error: %s", pkgsErrs)
-		}
 
-		for _, pkg := range pkgs {
+			attributes = append(attributes, attributesFromPackage(pkg, ignored)...)
+
 			for ident, def := range pkg.TypesInfo.Types {
-				if def.Type.String() == "github.com/wvell/messages.Key" && def.Value != nil {
-					translations = append(translations, strings.Trim(def.Value.ExactString(), "\""))
+				if ignored.contains(ident.Pos()) {
+					continue
+				}
+
+				if cfg.isKeyType(def.Type) && def.Value != nil {
+					found(strings.Trim(def.Value.ExactString(), "\""), pkg.Fset.Position(ident.Pos()))
 				} else if callExpr, ok := ident.(*ast.CallExpr); ok {
-					translation := processCallExpr(pkg.TypesInfo, callExpr)
+					translation := processCallExpr(cfg, pkg.TypesInfo, callExpr)
+					if translation == "" && cfg.isKeyType(def.Type) && isConversion(pkg.TypesInfo, callExpr) {
+						// A conversion of a variable to the key type, e.g. a field of a route table like Title: messages.Key(title).
+						translation = getValueFromExpr(callExpr.Args[0], pkg.TypesInfo)
+					}
 					if translation != "" {
-						translations = append(translations, translation)
+						found(translation, pkg.Fset.Position(ident.Pos()))
 					}
 				}
 			}
 		}
+
+		if cfg.progress != nil {
+			for _, translation := range translations[counted:] {
+				seen[catalogKey(translation)] = true
+			}
+			counted = len(translations)
+
+			progress.Dir = dir
+			progress.DirsDone++
+			progress.Packages += len(pkgs)
+			progress.Keys = len(seen)
+			cfg.progress(progress)
+		}
+	}
+
+	if wrappers != nil {
+		for _, key := range wrappers.keys() {
+			found(key.key, key.pos)
+		}
 	}
 
-	deduplicated := removeDuplicates(translations)
+	return partial(), nil
+}
+
+// loadPackages loads the packages in dir with their syntax and type information.
+// Package errors are passed to cfg.reportErrors when it is set, otherwise they are returned.
+func loadPackages(ctx context.Context, cfg *extractConfig, dir string) ([]*packages.Package, error) {
+	var buildFlags []string
+	if len(cfg.buildTags) > 0 {
+		buildFlags = append(buildFlags, "-tags="+strings.Join(cfg.buildTags, ","))
+	}
+
+	mode := packages.NeedName | packages.NeedSyntax |
+		packages.NeedTypes | packages.NeedTypesInfo | packages.NeedCompiledGoFiles
+
+	pkgCfg := &packages.Config{
+		Context:    ctx,
+		Mode:       mode,
+		Dir:        dir,
+		Fset:       token.NewFileSet(),
+		Tests:      cfg.tests,
+		BuildFlags: buildFlags,
+	}
+
+	pkgs, err := packages.Load(pkgCfg)
+	if err != nil {
+		return nil, fmt.Errorf("loading package: %w", err)
+	}
+
+	pkgsErrs := ""
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			if strings.HasPrefix(err.Msg, "build constraints exclude all Go files") {
+				continue
+			}
+
+			if cfg.reportErrors != nil {
+				cfg.reportErrors(err)
+				continue
+			}
+
+			pkgsErrs += err.Error() + "\n"
+		}
+	})
+	if pkgsErrs != "" {
+		return nil, fmt.Errorf("package load error: %s", pkgsErrs)
+	}
+
+	return pkgs, nil
+}
+
+// catalogKey returns the key as it is used in the translation files.
+// Casing directives like "button.save!upper" are not part of the key in the translation files,
+// and the keys in the translation files are NFC normalized.
+func catalogKey(translation string) string {
+	key, _, _ := splitCasingDirective(Key(translation))
+	return norm.NFC.String(string(key))
+}
+
+// attributesFromPackage finds the constant :attribute replacement values in the package.
+// Both map literals with the key "attribute" and struct literals with the field Attribute are searched:
+//
+//	map[string]any{"attribute": "first_name"}
+//	Replacements{Attribute: "first_name"}
+func attributesFromPackage(pkg *packages.Package, ignored ignoredRanges) []string {
+	var attributes []string
+
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(node ast.Node) bool {
+			lit, ok := node.(*ast.CompositeLit)
+			if !ok || ignored.contains(lit.Pos()) {
+				return true
+			}
 
-	if slices.Contains(deduplicated, attributesKey) {
-		return nil, ErrInvalidTranslationKey
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+
+				if !isAttributeKey(pkg.TypesInfo, kv.Key) {
+					continue
+				}
+
+				value := pkg.TypesInfo.Types[kv.Value].Value
+				if value != nil && value.Kind() == constant.String {
+					attributes = append(attributes, constant.StringVal(value))
+				}
+			}
+
+			return true
+		})
 	}
 
-	return deduplicated, nil
+	return attributes
 }
 
-func processCallExpr(info *types.Info, v *ast.CallExpr) string {
-	// It is a direct call to a function.
-	ident, ok := v.Fun.(*ast.Ident)
-	if ok {
-		return translationKeysFromCallExpr(info, ident, v.Args)
+// isAttributeKey reports if the key of a composite literal element is the :attribute replacement.
+func isAttributeKey(info *types.Info, key ast.Expr) bool {
+	// A struct field named Attribute.
+	if ident, ok := key.(*ast.Ident); ok {
+		if field, ok := info.Uses[ident].(*types.Var); ok && field.IsField() {
+			return strings.EqualFold(field.Name(), AttributeKey)
+		}
 	}
 
-	// It is a call to a method.
-	tr, ok := v.Fun.(*ast.SelectorExpr)
-	if !ok {
-		return ""
+	value := info.Types[key].Value
+	return value != nil && value.Kind() == constant.String && constant.StringVal(value) == AttributeKey
+}
+
+// isConversion reports if the call is a type conversion, like messages.Key(title).
+func isConversion(info *types.Info, call *ast.CallExpr) bool {
+	return len(call.Args) == 1 && info.Types[call.Fun].IsType()
+}
+
+func processCallExpr(cfg *extractConfig, info *types.Info, v *ast.CallExpr) string {
+	switch fun := v.Fun.(type) {
+	case *ast.Ident:
+		// It is a direct call to a function.
+		return translationKeysFromCallExpr(cfg, info, info.TypeOf(fun), v.Args)
+	case *ast.SelectorExpr:
+		// It is a call to a method.
+		return translationKeysFromCallExpr(cfg, info, info.TypeOf(fun.Sel), v.Args)
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		// It is a call to an explicit instantiation of a generic function, like T[messages.Key].
+		return translationKeysFromCallExpr(cfg, info, info.TypeOf(fun), v.Args)
 	}
 
-	return translationKeysFromCallExpr(info, tr.Sel, v.Args)
+	return ""
 }
 
-// translationKeyFromCall returns the translation key from the given ast.Ident.
+// translationKeyFromCall returns the translation key from a call to a function of the given type.
 // If no translation can be found it will return an empty string.
 // It will only resolve translation keys from consts or simple assignments.
-func translationKeysFromCallExpr(info *types.Info, ident *ast.Ident, args []ast.Expr) string {
-	typ := info.TypeOf(ident)
+// The type of a generic function is its instantiated signature, so keys passed to helpers like func T[K ~string](key K) are found.
+func translationKeysFromCallExpr(cfg *extractConfig, info *types.Info, typ types.Type, args []ast.Expr) string {
 	if typ == nil {
 		return ""
 	}
@@ -120,7 +521,7 @@ func translationKeysFromCallExpr(info *types.Info, ident *ast.Ident, args []ast.
 	}
 
 	for i := range sig.Params().Len() {
-		if sig.Params().At(i).Type().String() == keyType {
+		if cfg.isKeyType(sig.Params().At(i).Type()) {
 			translation := getValueFromExpr(args[i], info)
 			if translation != "" {
 				return translation
@@ -181,40 +582,141 @@ func getValueFromExpr(expr ast.Expr, info *types.Info) string {
 	return ""
 }
 
+// defaultSkipDirs are directory names that are never searched for go files unless overridden with WithSkipDirs.
+var defaultSkipDirs = []string{".git", "node_modules", "vendor"}
+
 // findDirsRecursively finds all directories that contain go files in the given root directory.
-func findDirsRecursively(rootDir string) ([]string, error) {
-	subdirs := []string{rootDir}
+// Directories in cfg.skipDirs, hidden directories and directories ignored by a .gitignore file are skipped
+// unless the config says otherwise. The root directory itself is never skipped.
+// Symlinked directories are only followed when cfg.followSymlinks is set, every directory is visited at most once
+// so symlink cycles can not cause an infinite walk.
+func findDirsRecursively(rootDir string, cfg *extractConfig) ([]string, error) {
+	w := &dirWalker{
+		root:    rootDir,
+		cfg:     cfg,
+		visited: make(map[string]bool),
+		dirs:    []string{rootDir},
+	}
 
-	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	err := w.walk(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.dirs, nil
+}
+
+// dirWalker holds the state of findDirsRecursively.
+type dirWalker struct {
+	root    string
+	cfg     *extractConfig
+	ignores []gitignore
+	// Visited holds the real path (symlinks resolved) of every directory that has been walked.
+	visited map[string]bool
+	dirs    []string
+}
+
+func (w *dirWalker) walk(path string) error {
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+
+	if w.visited[realPath] {
+		return nil
+	}
+	w.visited[realPath] = true
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	hasGoFiles := false
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
 		}
-		if info.IsDir() && path != rootDir {
-			hasGoFiles := false
 
-			entries, err := os.ReadDir(path)
+		if entry.Name() == ".gitignore" && w.cfg.gitignore {
+			ignore, err := readGitignore(path)
 			if err != nil {
 				return err
 			}
+			w.ignores = append(w.ignores, ignore)
+		}
 
-			for _, entry := range entries {
-				if !entry.IsDir() && filepath.Ext(entry.Name()) == ".go" {
-					hasGoFiles = true
-					break
-				}
+		if filepath.Ext(entry.Name()) == ".go" {
+			hasGoFiles = true
+		}
+	}
+
+	if hasGoFiles && !slices.Contains(w.dirs, path) {
+		w.dirs = append(w.dirs, path)
+	}
+
+	for _, entry := range entries {
+		subPath := filepath.Join(path, entry.Name())
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if !w.cfg.followSymlinks {
+				continue
 			}
 
-			if hasGoFiles {
-				subdirs = append(subdirs, path)
+			// Ignore dangling symlinks and symlinks to files.
+			info, err := os.Stat(subPath)
+			if err != nil || !info.IsDir() {
+				continue
 			}
+		} else if !entry.IsDir() {
+			continue
+		}
+
+		if w.skipDir(subPath, entry.Name()) {
+			continue
+		}
+
+		err := w.walk(subPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// skipDir reports if the directory at path should not be searched for go files.
+func (w *dirWalker) skipDir(path, name string) bool {
+	if slices.Contains(w.cfg.skipDirs, name) {
+		return true
+	}
+
+	if !w.cfg.hiddenDirs && strings.HasPrefix(name, ".") {
+		return true
+	}
+
+	if len(w.cfg.excludes) > 0 {
+		rel, err := filepath.Rel(w.root, path)
+		if err == nil {
+			rel = filepath.ToSlash(rel)
+			for _, pattern := range w.cfg.excludes {
+				if ok, _ := filepath.Match(pattern, name); ok {
+					return true
+				}
+				if ok, _ := filepath.Match(pattern, rel); ok {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, ignore := range w.ignores {
+		if ignore.matchDir(path) {
+			return true
 		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
 	}
 
-	return subdirs, nil
+	return false
 }
 
 func removeDuplicates(input []string) []string {
@@ -1,6 +1,8 @@
 package messages
 
 import (
+	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -18,6 +20,175 @@ func TestTranslationKeysFromSourceCode(t *testing.T) {
 }
 
 func TestTranslationKeysFromSourceCodeInvalid(t *testing.T) {
-	_, err := TranslationKeysFromSourceCode("./testdata/extractor-invalid")
+	translations, err := TranslationKeysFromSourceCode("./testdata/extractor-invalid")
 	require.ErrorIs(t, err, ErrInvalidTranslationKey)
+	require.Equal(t, []string{"meta", "welcome"}, translations)
+}
+
+func TestExtractFromSourceCodeInvalidKeys(t *testing.T) {
+	extraction, err := ExtractFromSourceCodeCtx(context.Background(), "./testdata/extractor-invalid")
+	require.NoError(t, err)
+	require.Equal(t, []string{"meta", "welcome"}, extraction.Keys)
+
+	require.Len(t, extraction.InvalidKeys, 1)
+	invalid := extraction.InvalidKeys[0]
+	require.Equal(t, "attributes", invalid.Key)
+	require.Equal(t, "invalid.go", filepath.Base(invalid.Position.Filename))
+	require.Equal(t, 10, invalid.Position.Line)
+	require.ErrorIs(t, invalid, ErrInvalidTranslationKey)
+	require.Contains(t, invalid.Error(), `invalid.go:10:2: restricted translation key "attributes"`)
+
+	// The attributes key is always reserved.
+	extraction, err = ExtractFromSourceCodeCtx(context.Background(), "./testdata/extractor-invalid", WithReservedKeys("meta"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"welcome"}, extraction.Keys)
+	require.Len(t, extraction.InvalidKeys, 2)
+	require.Equal(t, "attributes", extraction.InvalidKeys[0].Key)
+	require.Equal(t, "meta", extraction.InvalidKeys[1].Key)
+
+	extraction, err = ExtractFromSourceCodeCtx(context.Background(), "./testdata/extractor-invalid", WithReservedKeys())
+	require.NoError(t, err)
+	require.Equal(t, []string{"meta", "welcome"}, extraction.Keys)
+	require.Len(t, extraction.InvalidKeys, 1)
+}
+
+func TestTranslationKeysFromSourceCodeProgress(t *testing.T) {
+	var reports []Progress
+	translations, err := TranslationKeysFromSourceCode("./testdata/extractor", WithProgress(func(p Progress) {
+		reports = append(reports, p)
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, reports, 2)
+	last := reports[len(reports)-1]
+	require.Equal(t, 2, last.DirsDone)
+	require.Equal(t, 2, last.DirsTotal)
+	require.Equal(t, len(translations), last.Keys)
+}
+
+func TestFindDirsRecursivelySkipsDirs(t *testing.T) {
+	root := "./testdata/extractor-walk"
+
+	dirs, err := findDirsRecursively(root, newExtractConfig())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{root, filepath.Join(root, "visible")}, dirs)
+
+	dirs, err = findDirsRecursively(root, newExtractConfig(WithHiddenDirs(), WithoutGitignore(), WithSkipDirs()))
+	require.NoError(t, err)
+	require.Len(t, dirs, 6)
+}
+
+func TestFindDirsRecursivelySymlinks(t *testing.T) {
+	root := "./testdata/extractor-symlink"
+	target := filepath.Join(root, "pkg", "target")
+
+	dirs, err := findDirsRecursively(root, newExtractConfig())
+	require.NoError(t, err)
+	require.Equal(t, []string{root, target}, dirs)
+
+	// The cycle pkg/target/cycle -> pkg and the duplicate link to pkg/target are only visited once.
+	dirs, err = findDirsRecursively(root, newExtractConfig(WithFollowSymlinks()))
+	require.NoError(t, err)
+	require.Len(t, dirs, 2)
+}
+
+func TestTranslationKeysFromSourceCodeCtxCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Cancel after the first directory has been processed.
+	translations, err := TranslationKeysFromSourceCodeCtx(ctx, "./testdata/extractor", WithProgress(func(Progress) {
+		cancel()
+	}))
+	require.ErrorIs(t, err, context.Canceled)
+	require.Contains(t, translations, "login.welcome")
+	require.NotContains(t, translations, "sub.translation")
+}
+
+func TestTranslationKeysFromSourceCodeOptions(t *testing.T) {
+	dir := "./testdata/extractor-options"
+
+	translations, err := TranslationKeysFromSourceCode(dir)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"excluded.key"}, translations)
+
+	translations, err = TranslationKeysFromSourceCode(dir,
+		WithExcludes("excluded"),
+		WithBuildTags("extra"),
+		WithTests(),
+		WithFollowWrappers(),
+	)
+	require.NoError(t, err)
+	// The keys are sorted.
+	require.Equal(t, []string{"tagged.key", "test.key", "wrapper.chained", "wrapper.direct"}, translations)
+}
+
+func TestTranslationKeysFromSourceCodeKeyType(t *testing.T) {
+	translations, err := TranslationKeysFromSourceCode("./testdata/extractor", WithKeyType("example.com/other.Key"))
+	require.NoError(t, err)
+	require.Empty(t, translations)
+}
+
+func TestTranslationKeysFromSourceCodeReportErrors(t *testing.T) {
+	dir := "./testdata/extractor-broken"
+
+	_, err := TranslationKeysFromSourceCode(dir)
+	require.Error(t, err)
+
+	var errs []error
+	translations, err := TranslationKeysFromSourceCode(dir, WithReportErrors(func(err error) {
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+	require.NotEmpty(t, errs)
+	require.Equal(t, []string{"broken.key"}, translations)
+}
+
+func TestTranslationKeysFromSourceCodeKeyTypes(t *testing.T) {
+	dir := "./testdata/extractor-keytypes"
+
+	translations, err := TranslationKeysFromSourceCode(dir)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"facade.alias"}, translations)
+
+	translations, err = TranslationKeysFromSourceCode(dir, WithKeyTypes("github.com/wvell/messages/testdata/extractor-keytypes/i18n.MsgID"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"facade.alias", "facade.call", "facade.const"}, translations)
+}
+
+func TestTranslationKeysFromSourceCodeGenerics(t *testing.T) {
+	dir := "./testdata/extractor-generics"
+
+	translations, err := TranslationKeysFromSourceCode(dir)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"generic.conversion", "generic.explicit", "generic.inferred", "generic.method", "generic.pair", "generic.var"}, translations)
+
+	// Helpers that are instantiated with a string are wrappers.
+	translations, err = TranslationKeysFromSourceCode(dir, WithFollowWrappers())
+	require.NoError(t, err)
+	require.Subset(t, translations, []string{"generic.wrapper", "generic.wrapper.explicit"})
+}
+
+func TestTranslationKeysFromSourceCodeComposite(t *testing.T) {
+	translations, err := TranslationKeysFromSourceCode("./testdata/extractor-composite")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"nav.about", "nav.about.short", "nav.contact", "nav.copyright", "nav.home", "nav.nested", "nav.subtitle",
+		"nav.tab.overview", "nav.tab.settings", "nav.var",
+	}, translations)
+}
+
+func TestExtractFromSourceCodeIgnore(t *testing.T) {
+	extraction, err := ExtractFromSourceCodeCtx(context.Background(), "./testdata/extractor-ignore", WithFollowWrappers())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"greet.bye", "greet.hello", "greet.not_ignored", "title.home"}, extraction.Keys)
+	require.Equal(t, []string{"first_name"}, extraction.Attributes)
+}
+
+func TestExtractFromSourceCodeAttributes(t *testing.T) {
+	extraction, err := ExtractFromSourceCodeCtx(context.Background(), "./testdata/extractor-attributes")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"required"}, extraction.Keys)
+	require.Equal(t, []string{"first_name", "street", "zipcode"}, extraction.Attributes)
 }
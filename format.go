@@ -0,0 +1,81 @@
+package messages
+
+import (
+	"reflect"
+	"sync"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/text/language"
+)
+
+// FormatOpt configures Format.
+type FormatOpt func(*formatOptions)
+
+type formatOptions struct {
+	lang       language.Tag
+	attributes map[string]string
+	modifiers  map[string]Modifier
+}
+
+// formatDefaults are the modifiers and formatters of Format, they are created once.
+var formatDefaults = sync.OnceValues(func() (map[string]Modifier, map[reflect.Type]formatter) {
+	return defaultModifiers(), defaultFormatters()
+})
+
+// WithFormatLanguage formats the message in the language, e.g. for the modifiers and the number formats. The default is English.
+func WithFormatLanguage(lang LanguageID) FormatOpt {
+	return func(o *formatOptions) {
+		o.lang = language.Make(lang.String())
+	}
+}
+
+// WithFormatAttributes sets the attributes that replace the value of the :attribute replacement, see Attributes.
+func WithFormatAttributes(attributes map[string]string) FormatOpt {
+	return func(o *formatOptions) {
+		o.attributes = attributes
+	}
+}
+
+// WithFormatModifier adds a custom modifier, like WithModifier for a Translator.
+func WithFormatModifier(name string, modifier Modifier) FormatOpt {
+	return func(o *formatOptions) {
+		o.modifiers[name] = modifier
+	}
+}
+
+// Format formats a message that is not in a translation file, e.g. a notification template that users write, with the same
+// placeholders, modifiers, conditions and attributes as the translated messages:
+//
+//	messages.Format("Hello :User, you have :count|compact messages", map[string]any{"user": "jan", "count": 1200})
+//
+// The message is returned unchanged when it is invalid, e.g. when it uses an unknown modifier with an argument.
+func Format(msg string, replacements map[string]any, opts ...FormatOpt) string {
+	modifiers, formatters := formatDefaults()
+	o := &formatOptions{lang: language.English, modifiers: modifiers}
+	if len(opts) > 0 {
+		o.modifiers = maps.Clone(modifiers)
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	parsed, err := NewParser(nil).parseMessage("", msg)
+	if err != nil {
+		return msg
+	}
+
+	m := &messages{
+		messages:   []message{parsed},
+		index:      map[Key]int32{"": 0},
+		attributes: o.attributes,
+		lang:       o.lang,
+		modifiers:  o.modifiers,
+		formatters: formatters,
+	}
+
+	if err := m.validateModifiers(); err != nil {
+		return msg
+	}
+
+	return m.format("", "", replacements)
+}
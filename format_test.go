@@ -0,0 +1,29 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestFormat(t *testing.T) {
+	require.Equal(t, "Hello Jan, you have 1.2K messages", Format("Hello :User, you have :count|compact messages", map[string]any{"user": "jan", "count": 1200}))
+	require.Equal(t, "Hello , :user", Format(`Hello :user, \:user`, nil))
+	require.Equal(t, "No messages", Format(":count == 0 ? No messages | :count messages", map[string]any{"count": 0}))
+	require.Equal(t, "2 messages", Format(":count == 0 ? No messages | :count messages", map[string]any{"count": 2}))
+
+	nl, err := ParseLanguage("nl")
+	require.NoError(t, err)
+	require.Equal(t, "Het e-mailadres is verplicht, 1,2K", Format("Het :attribute is verplicht, :count|compact", map[string]any{"attribute": "email", "count": 1200},
+		WithFormatLanguage(nl), WithFormatAttributes(map[string]string{"email": "e-mailadres"})))
+
+	shout := WithFormatModifier("shout", func(_ language.Tag, value any, _ string) string {
+		return formatReplacement(value) + "!"
+	})
+	require.Equal(t, "Hi jan!", Format("Hi :user|shout", map[string]any{"user": "jan"}, shout))
+
+	// A pipe without a modifier is text, invalid messages are returned unchanged.
+	require.Equal(t, "Hi jan|shout", Format("Hi :user|shout", map[string]any{"user": "jan"}))
+	require.Equal(t, "Hi :user|shout(1)", Format("Hi :user|shout(1)", map[string]any{"user": "jan"}))
+}
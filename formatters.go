@@ -0,0 +1,122 @@
+package messages
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+)
+
+// PhoneNumber is a phone number in the international E.164 format, e.g. +31612345678.
+// Phone numbers are formatted for the region of the language when used as a replacement.
+type PhoneNumber string
+
+// PostalCode is a postal code that is formatted for the region of the language when used as a replacement.
+type PostalCode string
+
+// phoneFormat holds the country calling code and the digit grouping of the national number of a country.
+type phoneFormat struct {
+	callingCode string
+	// Region is the region that uses the national format.
+	region string
+	// Prefix is the national trunk prefix that is added in the national format.
+	prefix string
+	// Groups holds the sizes of the digit groups of the national number, the last group takes the remaining digits.
+	groups []int
+	// National formats the grouped number in the national format, when nil the groups are joined with a space.
+	national func(groups []string) string
+}
+
+var phoneFormats = []phoneFormat{
+	{callingCode: "1", region: "US", groups: []int{3, 3, 4}, national: func(g []string) string {
+		return "(" + g[0] + ") " + g[1] + "-" + strings.Join(g[2:], "")
+	}},
+	{callingCode: "31", region: "NL", prefix: "0", groups: []int{1, 8}},
+	{callingCode: "32", region: "BE", prefix: "0", groups: []int{3, 2, 2, 2}},
+	{callingCode: "33", region: "FR", prefix: "0", groups: []int{1, 2, 2, 2, 2}},
+	{callingCode: "44", region: "GB", prefix: "0", groups: []int{2, 4, 4}},
+	{callingCode: "49", region: "DE", prefix: "0", groups: []int{3, 8}},
+}
+
+// formatPhoneNumber formats an E.164 phone number. The national format is used when the region of lang
+// matches the country of the number, the international format otherwise:
+//
+//	+31612345678 is formatted as "06 12345678" for nl-NL and "+31 6 12345678" for other regions.
+//
+// Numbers of unknown countries and numbers that are not in the E.164 format are returned as is.
+func formatPhoneNumber(lang language.Tag, number PhoneNumber) string {
+	digits := strings.TrimPrefix(string(number), "+")
+	if len(digits) == len(number) || strings.IndexFunc(digits, func(r rune) bool { return !unicode.IsDigit(r) }) != -1 {
+		return string(number)
+	}
+
+	region, confidence := lang.Region()
+	for _, format := range phoneFormats {
+		national, ok := strings.CutPrefix(digits, format.callingCode)
+		if !ok {
+			continue
+		}
+
+		groups := groupDigits(national, format.groups)
+		if confidence == language.Exact && region.String() == format.region {
+			if format.national != nil && len(groups) >= len(format.groups) {
+				return format.national(groups)
+			}
+
+			return format.prefix + strings.Join(groups, " ")
+		}
+
+		return "+" + format.callingCode + " " + strings.Join(groups, " ")
+	}
+
+	return string(number)
+}
+
+// groupDigits splits digits in groups of the given sizes, the last group takes the remaining digits.
+func groupDigits(digits string, sizes []int) []string {
+	var groups []string
+	for i, size := range sizes {
+		if len(digits) <= size || i == len(sizes)-1 {
+			break
+		}
+
+		groups = append(groups, digits[:size])
+		digits = digits[size:]
+	}
+
+	return append(groups, digits)
+}
+
+// formatPostalCode formats a postal code for the region of lang.
+// The codes are normalized to upper case without surrounding whitespace, and for some regions the separator is added:
+//
+//	NL: 1234AB    -> 1234 AB
+//	GB: SW1A1AA   -> SW1A 1AA
+//	CA: K1A0B1    -> K1A 0B1
+//	US: 123456789 -> 12345-6789
+func formatPostalCode(lang language.Tag, code PostalCode) string {
+	normalized := strings.ToUpper(strings.TrimSpace(string(code)))
+	compact := strings.ReplaceAll(strings.ReplaceAll(normalized, " ", ""), "-", "")
+
+	region, confidence := lang.Region()
+	if confidence != language.Exact {
+		return normalized
+	}
+
+	switch region.String() {
+	case "NL":
+		if len(compact) == 6 {
+			return compact[:4] + " " + compact[4:]
+		}
+	case "GB", "CA":
+		if len(compact) > 3 {
+			return compact[:len(compact)-3] + " " + compact[len(compact)-3:]
+		}
+	case "US":
+		if len(compact) == 9 {
+			return compact[:5] + "-" + compact[5:]
+		}
+	}
+
+	return normalized
+}
@@ -0,0 +1,79 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestFormatPhoneNumber(t *testing.T) {
+	cases := []struct {
+		lang     string
+		number   PhoneNumber
+		expected string
+	}{
+		{lang: "nl-NL", number: "+31612345678", expected: "06 12345678"},
+		{lang: "en-GB", number: "+31612345678", expected: "+31 6 12345678"},
+		{lang: "nl", number: "+31612345678", expected: "+31 6 12345678"},
+		{lang: "en-US", number: "+15551234567", expected: "(555) 123-4567"},
+		{lang: "nl-NL", number: "+15551234567", expected: "+1 555 123 4567"},
+		{lang: "en-GB", number: "+442079460958", expected: "020 7946 0958"},
+		{lang: "fr-FR", number: "+33612345678", expected: "06 12 34 56 78"},
+		// Unknown countries and numbers that are not E.164 are returned as is.
+		{lang: "nl-NL", number: "+81312345678", expected: "+81312345678"},
+		{lang: "nl-NL", number: "0612345678", expected: "0612345678"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.lang+"_"+string(c.number), func(t *testing.T) {
+			require.Equal(t, c.expected, formatPhoneNumber(language.MustParse(c.lang), c.number))
+		})
+	}
+}
+
+func TestFormatPostalCode(t *testing.T) {
+	cases := []struct {
+		lang     string
+		code     PostalCode
+		expected string
+	}{
+		{lang: "nl-NL", code: "1234ab", expected: "1234 AB"},
+		{lang: "en-GB", code: "sw1a1aa", expected: "SW1A 1AA"},
+		{lang: "en-CA", code: "K1A0B1", expected: "K1A 0B1"},
+		{lang: "en-US", code: "123456789", expected: "12345-6789"},
+		{lang: "en", code: " 1234ab ", expected: "1234AB"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.lang+"_"+string(c.code), func(t *testing.T) {
+			require.Equal(t, c.expected, formatPostalCode(language.MustParse(c.lang), c.code))
+		})
+	}
+}
+
+type money struct {
+	cents int
+}
+
+func TestFormatters(t *testing.T) {
+	euros := func(lang language.Tag, m money) string {
+		return formatReplacement(float64(m.cents)/100) + " EUR"
+	}
+
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/formatters", WithFormatter(euros))
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "nl-NL")
+	require.NoError(t, err)
+
+	message := tr.Translate(ctx, "confirm", map[string]any{
+		"phone":  PhoneNumber("+31612345678"),
+		"postal": PostalCode("1234ab"),
+		"plain":  "1234ab",
+		"price":  money{cents: 1250},
+	})
+	require.Equal(t, "We bellen 06 12345678 over 1234 AB (1234 AB) voor 12.50 EUR", message)
+}
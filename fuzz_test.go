@@ -0,0 +1,85 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func FuzzRawMessagesUnmarshalJSON(f *testing.F) {
+	f.Add([]byte(`{"welcome": "Welcome :User", "attributes": {"first_name": "first name"}}`))
+	f.Add([]byte(`{"color": "color", "color@GB": "colour"}`))
+	f.Add([]byte(`{"café": "café"}`))
+	f.Add([]byte(`{"attributes": null}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var raw RawMessages
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return
+		}
+
+		// A successfully decoded file can be written back.
+		if _, err := json.Marshal(&raw); err != nil {
+			t.Fatalf("marshaling decoded messages: %v", err)
+		}
+	})
+}
+
+func FuzzParseMessage(f *testing.F) {
+	f.Add("Welcome :User")
+	f.Add(`Costs \:price`)
+	f.Add(":total|percent(1) of :user.name|title")
+	f.Add(":count == 0 ? No items | :count == 1 ? One item | :count items")
+	f.Add(":count >= ? | ")
+
+	parser := NewParser(afero.NewMemMapFs())
+
+	f.Fuzz(func(t *testing.T, value string) {
+		_, _ = parser.parseMessage("key", value)
+	})
+}
+
+func FuzzFormat(f *testing.F) {
+	f.Add("Welcome :User", "jan", int64(3), 0.25)
+	f.Add(":count == 0 ? No items | :count items", "", int64(0), 1.5)
+	f.Add("Used :ratio|percent(1), :count|compact, :count|bytes(iec), :user|title", "new york", int64(-1<<62), -0.0)
+	f.Add(":count|duration(9) :user|phone :user|postal", "é́", int64(1e15), 1e300)
+
+	f.Fuzz(func(t *testing.T, value, user string, count int64, ratio float64) {
+		content, err := json.Marshal(map[string]string{"key": value, "key@GB": value})
+		if err != nil {
+			return
+		}
+
+		fs := afero.NewMemMapFs()
+		if err := afero.WriteFile(fs, "translations/en.json", content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		tr, err := NewTranslator(fs, "translations")
+		if err != nil {
+			// Invalid messages are rejected when they are loaded.
+			return
+		}
+
+		replacements := map[string]any{
+			"user":     user,
+			"count":    count,
+			"ratio":    ratio,
+			"duration": time.Duration(count),
+			"phone":    PhoneNumber(user),
+			"list":     []any{user, count, ratio},
+		}
+
+		for _, lang := range []string{"en", "en-GB", "en-US"} {
+			ctx := ToCtx(context.Background(), lang)
+			tr.Translate(ctx, "key", replacements)
+			tr.Translate(ctx, "key!title", replacements)
+			tr.Translate(ctx, "key", nil)
+		}
+	})
+}
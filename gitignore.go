@@ -0,0 +1,81 @@
+package messages
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitignore holds the directory patterns of a single .gitignore file.
+// Only the subset of the gitignore syntax that is relevant for directories is supported, negations are ignored.
+type gitignore struct {
+	// The directory that contains the .gitignore file, patterns are relative to this directory.
+	dir      string
+	patterns []gitignorePattern
+}
+
+type gitignorePattern struct {
+	pattern string
+	// Anchored patterns are matched against the path relative to the .gitignore directory, others against the name only.
+	anchored bool
+}
+
+// readGitignore reads the .gitignore file in dir.
+func readGitignore(dir string) (gitignore, error) {
+	ignore := gitignore{dir: dir}
+
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return ignore, fmt.Errorf("opening .gitignore: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "**/")
+		line = strings.TrimSuffix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		ignore.patterns = append(ignore.patterns, gitignorePattern{
+			pattern:  strings.TrimPrefix(line, "/"),
+			anchored: strings.Contains(line, "/"),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ignore, fmt.Errorf("reading .gitignore: %w", err)
+	}
+
+	return ignore, nil
+}
+
+// matchDir reports if the directory at path is ignored.
+func (g gitignore) matchDir(path string) bool {
+	rel, err := filepath.Rel(g.dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, p := range g.patterns {
+		name := filepath.Base(path)
+		if p.anchored {
+			name = rel
+		}
+
+		if ok, _ := filepath.Match(p.pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
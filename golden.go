@@ -0,0 +1,159 @@
+package messages
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+var (
+	ErrGoldenMismatch = fmt.Errorf("translations differ from the golden files")
+)
+
+// Preview translates the key with the sample replacements from the metadata, see KeyMetadata. A preview is not counted in the usage.
+func (t *Translator) Preview(ctx context.Context, key Key) string {
+	base, _, _ := splitCasingDirective(key)
+	c := t.current.Load()
+	if c == nil {
+		return string(key)
+	}
+
+	return t.translateMemo(ctx, key, c.metadata[string(base)].Samples)
+}
+
+// Render renders every key of every language with the sample replacements from the metadata.
+// The result maps the language, e.g. "en-US", to the rendered messages by key.
+// Region overrides are rendered for their region and keep their key, e.g. "color@GB".
+func (t *Translator) Render() map[string]map[string]string {
+	c := t.current.Load()
+	if c == nil {
+		return map[string]map[string]string{}
+	}
+
+	rendered := make(map[string]map[string]string, len(c.languages))
+	for languageID, messages := range c.languages {
+		out := make(map[string]string, len(messages.index))
+
+		ctx := ToCtx(context.Background(), languageID)
+		for key := range messages.index {
+			out[string(key)] = t.Preview(ctx, key)
+		}
+
+		for region, regionMessages := range messages.regions {
+			regionCtx := ToCtx(context.Background(), messages.id(region).String())
+			for key := range regionMessages {
+				out[string(key)+regionSeparator+region] = t.Preview(regionCtx, key)
+			}
+		}
+
+		rendered[languageID] = out
+	}
+
+	return rendered
+}
+
+// CheckGoldenFiles renders the translations in dir with the sample replacements from the metadata file (see Translator.Render)
+// and compares them with the golden files in goldenDir, one JSON file per language.
+// Use it in a test so translation changes show up as reviewable diffs:
+//
+//	var update = flag.Bool("update", false, "update the golden files")
+//
+//	func TestTranslations(t *testing.T) {
+//		err := messages.CheckGoldenFiles(afero.NewOsFs(), "translations", "testdata/golden", *update)
+//		require.NoError(t, err)
+//	}
+//
+// The golden files are written instead of compared when update is true.
+// An ErrGoldenMismatch error with all differences is returned when the rendered messages differ from the golden files.
+func CheckGoldenFiles(fsys afero.Fs, dir, goldenDir string, update bool, opts ...Opt) error {
+	tr, err := NewTranslator(fsys, dir, opts...)
+	if err != nil {
+		return err
+	}
+
+	rendered := tr.Render()
+
+	if update {
+		return writeGoldenFiles(fsys, goldenDir, rendered)
+	}
+
+	var diffs []string
+	for _, languageID := range sorted.Keys(rendered) {
+		file := filepath.Join(goldenDir, languageID+".json")
+
+		data, err := afero.ReadFile(fsys, file)
+		if errors.Is(err, fs.ErrNotExist) {
+			diffs = append(diffs, fmt.Sprintf("%s: golden file is missing", file))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading golden file: %w", err)
+		}
+
+		var golden map[string]string
+		if err := json.Unmarshal(data, &golden); err != nil {
+			return fmt.Errorf("decoding golden file %s: %w", file, err)
+		}
+
+		diffs = append(diffs, diffGolden(file, golden, rendered[languageID])...)
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("%w, update them if the changes are intended:\n%s", ErrGoldenMismatch, strings.Join(diffs, "\n"))
+	}
+
+	return nil
+}
+
+// diffGolden returns the differences between the golden and the rendered messages of one file.
+func diffGolden(file string, golden, rendered map[string]string) []string {
+	var diffs []string
+	for _, key := range sorted.Keys(rendered) {
+		want, ok := golden[key]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: key %q is new: %q", file, key, rendered[key]))
+		} else if want != rendered[key] {
+			diffs = append(diffs, fmt.Sprintf("%s: key %q changed from %q to %q", file, key, want, rendered[key]))
+		}
+	}
+
+	for _, key := range sorted.Keys(golden) {
+		if _, ok := rendered[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: key %q is removed", file, key))
+		}
+	}
+
+	return diffs
+}
+
+func writeGoldenFiles(fsys afero.Fs, goldenDir string, rendered map[string]map[string]string) error {
+	if err := fsys.MkdirAll(goldenDir, 0o755); err != nil {
+		return fmt.Errorf("creating golden dir: %w", err)
+	}
+
+	for languageID, messages := range rendered {
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+
+		// The encoder sorts the keys, so the golden files are stable.
+		if err := encoder.Encode(messages); err != nil {
+			return fmt.Errorf("encoding golden file: %w", err)
+		}
+
+		if err := afero.WriteFile(fsys, filepath.Join(goldenDir, languageID+".json"), buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("writing golden file: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package messages
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/golden/translations")
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]map[string]string{
+		"en": {
+			"cart.items":    "3 items in your cart",
+			"color":         "Pick a color",
+			"color@GB":      "Pick a colour",
+			"welcome.login": "Welcome Jan",
+		},
+		"nl": {
+			"cart.items":    "3 artikelen in je winkelwagen",
+			"color":         "Kies een kleur",
+			"welcome.login": "Welkom Jan",
+		},
+	}, tr.Render())
+}
+
+func TestCheckGoldenFiles(t *testing.T) {
+	dir := filepath.Join("testdata", "golden", "translations")
+	goldenDir := filepath.Join("testdata", "golden", "expected")
+
+	require.NoError(t, CheckGoldenFiles(afero.NewOsFs(), dir, goldenDir, false))
+
+	// Changes are written to memory, so the testdata is not changed.
+	fs := afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(afero.NewOsFs()), afero.NewMemMapFs())
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "nl.json"), []byte(`{"color": "Kies een kleurtje", "welcome.login": "Welkom :User"}`), 0o644))
+
+	err := CheckGoldenFiles(fs, dir, goldenDir, false)
+	require.ErrorIs(t, err, ErrGoldenMismatch)
+	require.ErrorContains(t, err, `key "color" changed from "Kies een kleur" to "Kies een kleurtje"`)
+	require.ErrorContains(t, err, `key "cart.items" is removed`)
+
+	require.NoError(t, CheckGoldenFiles(fs, dir, goldenDir, true))
+	require.NoError(t, CheckGoldenFiles(fs, dir, goldenDir, false))
+
+	// The golden files of a new language are missing.
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "de.json"), []byte(`{"color": "Wähle eine Farbe"}`), 0o644))
+	require.ErrorContains(t, CheckGoldenFiles(fs, dir, goldenDir, false), "golden file is missing")
+}
+
+func TestPreview(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/golden/translations")
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "nl")
+	require.NoError(t, err)
+
+	require.Equal(t, "Welkom Jan", tr.Preview(ctx, "welcome.login"))
+	require.Equal(t, "WELKOM JAN", tr.Preview(ctx, "welcome.login!upper"))
+	require.Equal(t, "Kies een kleur", tr.Preview(ctx, "color"))
+}
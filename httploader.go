@@ -0,0 +1,156 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HTTPLoader loads the translations of all languages from a JSON bundle on a remote server.
+// The bundle holds the translation files by language:
+//
+//	{
+//		"en": {"welcome": "Welcome"},
+//		"nl": {"welcome": "Welkom"}
+//	}
+//
+// The url can also be a language pack of a single language, see Pack. Combine the loaders of the packs with Layer.
+//
+// Reloads are conditional requests with the ETag and Last-Modified headers of the last response,
+// the bundle is only downloaded and parsed again when the server returns a new version.
+//
+// When the last response was a pack, reloads also send the "A-IM: msgdelta" header of RFC 3229. The server can answer with
+// the status 226 IM Used and the Delta since the version of the If-None-Match header, the delta is applied to the pack of the last load.
+// The full pack is downloaded again when the delta does not apply to that pack.
+type HTTPLoader struct {
+	url    string
+	client *http.Client
+
+	// Mu protects the pack of the last load, deltas are applied to it.
+	mu   sync.Mutex
+	pack *Pack
+}
+
+// deltaIM is the instance manipulation of the A-IM header for language pack deltas, see RFC 3229.
+const deltaIM = "msgdelta"
+
+var _ Loader = (*HTTPLoader)(nil)
+
+// NewHTTPLoader returns a loader for the bundle at url. The http.DefaultClient is used when client is nil.
+func NewHTTPLoader(url string, client *http.Client) *HTTPLoader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPLoader{url: url, client: client}
+}
+
+func (l *HTTPLoader) Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	languages, version, err := l.load(ctx, since, l.pack != nil && since.ETag != "")
+	if errors.Is(err, ErrDeltaBase) {
+		return l.load(ctx, Version{}, false)
+	}
+
+	return languages, version, err
+}
+
+// load downloads the translations, a delta is requested when delta is set.
+func (l *HTTPLoader) load(ctx context.Context, since Version, delta bool) (map[LanguageID]*RawMessages, Version, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url, nil)
+	if err != nil {
+		return nil, Version{}, fmt.Errorf("creating request: %w", err)
+	}
+
+	if since.ETag != "" {
+		req.Header.Set("If-None-Match", since.ETag)
+	} else if !since.LastModified.IsZero() {
+		req.Header.Set("If-Modified-Since", since.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if delta {
+		req.Header.Set("A-IM", deltaIM)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, Version{}, fmt.Errorf("loading translations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, since, ErrNotModified
+	}
+
+	if resp.StatusCode != http.StatusOK && (resp.StatusCode != http.StatusIMUsed || !delta) {
+		return nil, Version{}, fmt.Errorf("loading translations: unexpected status %s", resp.Status)
+	}
+
+	version := Version{ETag: resp.Header.Get("ETag")}
+	if lastModified, err := time.Parse(http.TimeFormat, resp.Header.Get("Last-Modified")); err == nil {
+		version.LastModified = lastModified
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, Version{}, fmt.Errorf("loading translations: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusIMUsed {
+		d, err := UnmarshalDelta(data)
+		if err != nil {
+			return nil, Version{}, err
+		}
+
+		pack, err := l.pack.Apply(d)
+		if err != nil {
+			return nil, Version{}, err
+		}
+
+		if version.ETag == "" {
+			version.ETag = d.Checksum
+		}
+
+		l.pack = pack
+		return map[LanguageID]*RawMessages{pack.Language: pack.Messages}, version, nil
+	}
+
+	if isPack(data) {
+		pack, err := UnmarshalPack(data)
+		if err != nil {
+			return nil, Version{}, err
+		}
+
+		if version.ETag == "" {
+			version.ETag = pack.Checksum
+		}
+
+		l.pack = pack
+		return map[LanguageID]*RawMessages{pack.Language: pack.Messages}, version, nil
+	}
+
+	var bundle map[string]*RawMessages
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, Version{}, fmt.Errorf("decoding translations: %w", err)
+	}
+
+	languages := make(map[LanguageID]*RawMessages, len(bundle))
+	for languageID, raw := range bundle {
+		lang, err := ParseLanguage(languageID)
+		if err != nil {
+			return nil, Version{}, err
+		}
+
+		languages[lang] = raw
+	}
+
+	l.pack = nil
+	return languages, version, nil
+}
@@ -0,0 +1,491 @@
+package messages
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	textmessage "golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+var (
+	// ErrInvalidICUMessage is returned when a message can not be parsed as an ICU message, see WithMessageFormat.
+	ErrInvalidICUMessage = fmt.Errorf("invalid ICU message")
+)
+
+// MessageFormat is the syntax of the messages in the translation files, see WithMessageFormat.
+type MessageFormat int
+
+const (
+	// PlaceholderFormat is the default syntax with :placeholder replacements and conditions.
+	PlaceholderFormat MessageFormat = iota
+	// ICU is the ICU MessageFormat syntax with {placeholder} replacements and plural and select arguments.
+	ICU
+)
+
+// WithMessageFormat sets the syntax of the messages. With ICU the messages are ICU MessageFormat messages, so existing ICU bundles of
+// other platforms can be used as they are:
+//
+//	"welcome": "Welcome {name}",
+//	"cart": "{count, plural, =0 {Your cart is empty} one {One item} other {# items}}",
+//	"invite": "{gender, select, female {She invited you} male {He invited you} other {They invited you}}"
+//
+// The argument types are number (with the styles integer and percent), plural, selectordinal and select, and the modifiers, like
+// {start, date} and {size, bytes}. The style of a modifier is its argument, e.g. {total, percent, 1}. Plural and selectordinal
+// select the form with the CLDR plural rules of the language, # is the number minus the offset. Argument names are lowercased
+// like placeholders, so {userName} is replaced with the "username" replacement. Messages are parsed when the translations are loaded,
+// a message that can not be parsed is an ErrInvalidICUMessage. Template messages, see WithTemplates, are not parsed as ICU messages.
+// The :placeholders, modifiers and conditions of the default syntax are plain text in ICU messages.
+func WithMessageFormat(format MessageFormat) Opt {
+	return func(t *Translator) {
+		t.messageFormat = format
+		// ICU messages are only parsed as ICU, the placeholders, modifiers and conditions of this package are text.
+		t.parserOpts = append(t.parserOpts, func(p *Parser) {
+			p.rawValues = format == ICU
+		})
+	}
+}
+
+// icuMessage is the parsed message of an ICU message.
+type icuMessage struct {
+	parts []icuPart
+	// Args are the names of the arguments that the message uses, including the arguments of the nested messages.
+	args []string
+}
+
+// icuPart is literal text, the # of a plural message or an argument of an ICU message.
+type icuPart struct {
+	text  string
+	pound bool
+	// Arg is the lowercase name of the argument, empty for text and #.
+	arg string
+	// ArgType is the type of the argument, e.g. "number", "plural" or the name of a modifier. Empty for a simple argument.
+	argType string
+	style   string
+	// Offset is subtracted from the value of a plural argument before the form is selected.
+	offset float64
+	// Options are the messages of a plural, selectordinal or select argument.
+	options []icuOption
+}
+
+// icuOption is a message of a plural, selectordinal or select argument.
+type icuOption struct {
+	// Selector is the keyword of the option, e.g. "one" or "female". Exact is set for =N selectors of plural arguments.
+	selector string
+	exact    *float64
+	message  []icuPart
+}
+
+// compileICUMessages parses the messages of the language as ICU messages, when the message format is ICU.
+func (t *Translator) compileICUMessages(m *messages) error {
+	if t.messageFormat != ICU {
+		return nil
+	}
+
+	for key, i := range m.index {
+		msg, err := t.compileICU(key, m.messages[i])
+		if err != nil {
+			return err
+		}
+		m.messages[i] = msg
+	}
+
+	for _, regionMessages := range m.regions {
+		for key, msg := range regionMessages {
+			msg, err := t.compileICU(key, msg)
+			if err != nil {
+				return err
+			}
+			regionMessages[key] = msg
+		}
+	}
+
+	return nil
+}
+
+// compileICU parses the message of key as an ICU message, when the message format is ICU.
+func (t *Translator) compileICU(key Key, msg message) (message, error) {
+	if t.messageFormat != ICU || msg.condition != nil || msg.template != nil {
+		return msg, nil
+	}
+
+	p := &icuParser{src: []rune(msg.message), modifiers: t.modifiers}
+	parts, err := p.message(false)
+	if err == nil && p.pos < len(p.src) {
+		err = p.errorf("unmatched }")
+	}
+	if err != nil {
+		return msg, fmt.Errorf("%w: message %q: %w", ErrInvalidICUMessage, key, err)
+	}
+
+	msg.icu = &icuMessage{parts: parts, args: p.args}
+	msg.replacements = nil
+
+	return msg, nil
+}
+
+// icuParser parses an ICU message.
+type icuParser struct {
+	src []rune
+	pos int
+	// Modifiers are the modifiers that can be used as argument type.
+	modifiers map[string]Modifier
+	args      []string
+}
+
+func (p *icuParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// message parses text and arguments up to the } that closes the message, or the end of the message.
+// In a plural message # is the number.
+func (p *icuParser) message(inPlural bool) ([]icuPart, error) {
+	var parts []icuPart
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, icuPart{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '}':
+			flush()
+			return parts, nil
+		case c == '{':
+			flush()
+			part, err := p.argument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
+		case c == '#' && inPlural:
+			flush()
+			parts = append(parts, icuPart{pound: true})
+			p.pos++
+		case c == '\'':
+			p.quoted(&text, inPlural)
+		default:
+			text.WriteRune(c)
+			p.pos++
+		}
+	}
+
+	flush()
+	return parts, nil
+}
+
+// quoted reads an apostrophe. Two apostrophes are an apostrophe, an apostrophe before a special character starts quoted text that
+// ends with the next apostrophe. Other apostrophes are literal, so "it's" needs no quoting.
+func (p *icuParser) quoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+		text.WriteRune('\'')
+		p.pos++
+		return
+	}
+
+	if p.pos >= len(p.src) || !(p.src[p.pos] == '{' || p.src[p.pos] == '}' || p.src[p.pos] == '|' || (p.src[p.pos] == '#' && inPlural)) {
+		text.WriteRune('\'')
+		return
+	}
+
+	for p.pos < len(p.src) {
+		if p.src[p.pos] == '\'' {
+			if p.pos+1 < len(p.src) && p.src[p.pos+1] == '\'' {
+				text.WriteRune('\'')
+				p.pos += 2
+				continue
+			}
+
+			p.pos++
+			return
+		}
+
+		text.WriteRune(p.src[p.pos])
+		p.pos++
+	}
+}
+
+func (p *icuParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// identifier reads a name, a type or a selector.
+func (p *icuParser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if unicode.IsSpace(c) || strings.ContainsRune("{},'#", c) {
+			break
+		}
+		p.pos++
+	}
+
+	return string(p.src[start:p.pos])
+}
+
+// expect skips spaces and reads c.
+func (p *icuParser) expect(c rune) error {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return p.errorf("expected %q, got the end of the message", c)
+	}
+	if p.src[p.pos] != c {
+		return p.errorf("expected %q, got %q", c, p.src[p.pos])
+	}
+
+	p.pos++
+	return nil
+}
+
+// argument parses an argument like {name}, {name, type} or {name, type, style}.
+func (p *icuParser) argument(inPlural bool) (icuPart, error) {
+	p.pos++
+	p.skipSpace()
+
+	name := strings.ToLower(p.identifier())
+	if name == "" {
+		return icuPart{}, p.errorf("argument without a name")
+	}
+	if !slices.Contains(p.args, name) {
+		p.args = append(p.args, name)
+	}
+
+	part := icuPart{arg: name}
+
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == '}' {
+		p.pos++
+		return part, nil
+	}
+
+	if err := p.expect(','); err != nil {
+		return icuPart{}, err
+	}
+
+	p.skipSpace()
+	part.argType = p.identifier()
+
+	switch part.argType {
+	case "plural", "selectordinal", "select":
+		if err := p.expect(','); err != nil {
+			return icuPart{}, err
+		}
+
+		if err := p.options(&part, inPlural); err != nil {
+			return icuPart{}, err
+		}
+	case "number":
+		style, err := p.style()
+		if err != nil {
+			return icuPart{}, err
+		}
+
+		if style != "" && style != "integer" && style != "percent" {
+			return icuPart{}, p.errorf("argument %q has unsupported number style %q, use integer or percent", name, style)
+		}
+		part.style = style
+	case "":
+		return icuPart{}, p.errorf("argument %q without a type", name)
+	default:
+		if _, ok := p.modifiers[part.argType]; !ok {
+			return icuPart{}, p.errorf("argument %q has unknown type %q", name, part.argType)
+		}
+
+		style, err := p.style()
+		if err != nil {
+			return icuPart{}, err
+		}
+		part.style = style
+	}
+
+	return part, nil
+}
+
+// style reads the optional style of an argument and the closing }.
+func (p *icuParser) style() (string, error) {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == ',' {
+		p.pos++
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != '}' && p.src[p.pos] != '{' {
+			p.pos++
+		}
+
+		style := strings.TrimSpace(string(p.src[start:p.pos]))
+		return style, p.expect('}')
+	}
+
+	return "", p.expect('}')
+}
+
+// options parses the offset and the options of a plural, selectordinal or select argument and the closing }.
+func (p *icuParser) options(part *icuPart, inPlural bool) error {
+	isPlural := part.argType != "select"
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return p.errorf("argument %q is not closed", part.arg)
+		}
+
+		if p.src[p.pos] == '}' {
+			p.pos++
+			break
+		}
+
+		selector := p.identifier()
+		if selector == "" {
+			return p.errorf("argument %q has an option without a selector", part.arg)
+		}
+
+		if offset, ok := strings.CutPrefix(selector, "offset:"); ok && isPlural && len(part.options) == 0 {
+			value, err := strconv.ParseFloat(offset, 64)
+			if err != nil {
+				return p.errorf("argument %q has invalid offset %q", part.arg, offset)
+			}
+			part.offset = value
+			continue
+		}
+
+		option := icuOption{selector: selector}
+		if exact, ok := strings.CutPrefix(selector, "="); ok && isPlural {
+			value, err := strconv.ParseFloat(exact, 64)
+			if err != nil {
+				return p.errorf("argument %q has invalid selector %q", part.arg, selector)
+			}
+			option.exact = &value
+		} else if isPlural && !slices.Contains(PluralForms, PluralForm(selector)) {
+			return p.errorf("argument %q has selector %q, use =N or one of %q", part.arg, selector, PluralForms)
+		}
+
+		if err := p.expect('{'); err != nil {
+			return err
+		}
+
+		// In the messages of a select argument # is the number of the plural argument around it.
+		message, err := p.message(isPlural || inPlural)
+		if err != nil {
+			return err
+		}
+
+		if err := p.expect('}'); err != nil {
+			return err
+		}
+
+		option.message = message
+		part.options = append(part.options, option)
+	}
+
+	if !slices.ContainsFunc(part.options, func(o icuOption) bool { return o.selector == string(PluralOther) }) {
+		return p.errorf("argument %q has no %q option", part.arg, PluralOther)
+	}
+
+	return nil
+}
+
+// formatICU formats the parts of an ICU message with the replacements, pound is the value of # in a plural message.
+func (m *messages) formatICU(b *strings.Builder, lang language.Tag, parts []icuPart, replacements map[string]any, pound string) {
+	for _, part := range parts {
+		switch {
+		case part.pound:
+			b.WriteString(pound)
+		case part.arg == "":
+			b.WriteString(part.text)
+		case part.argType == "plural" || part.argType == "selectordinal":
+			value, _ := numericValue(replacements[part.arg])
+			option := part.choosePlural(lang, value)
+			m.formatICU(b, lang, option.message, replacements, formatICUNumber(lang, value-part.offset, ""))
+		case part.argType == "select":
+			value := formatReplacement(replacements[part.arg])
+			option := part.option(value)
+			m.formatICU(b, lang, option.message, replacements, pound)
+		default:
+			value, ok := replacements[part.arg]
+			if !ok {
+				continue
+			}
+
+			var formatted string
+			switch part.argType {
+			case "":
+				formatted = m.formatValue(lang, value, "", "")
+			case "number":
+				if f, ok := numericValue(value); ok {
+					formatted = formatICUNumber(lang, f, part.style)
+				} else {
+					formatted = formatReplacement(value)
+				}
+			default:
+				formatted = m.formatValue(lang, value, part.argType, part.style)
+			}
+
+			if part.arg == AttributeKey {
+				if attribute, ok := m.attribute(formatted); ok {
+					formatted = attribute
+				}
+			}
+
+			if formatted != "" && m.bidiIsolate {
+				formatted = bidiIsolate(formatted)
+			}
+
+			b.WriteString(formatted)
+		}
+	}
+}
+
+// choosePlural returns the option of a plural or selectordinal argument for the value. An exact selector like =0 matches the value,
+// a plural form matches the value minus the offset.
+func (part icuPart) choosePlural(lang language.Tag, value float64) icuOption {
+	for _, option := range part.options {
+		if option.exact != nil && *option.exact == value {
+			return option
+		}
+	}
+
+	rules := plural.Cardinal
+	if part.argType == "selectordinal" {
+		rules = plural.Ordinal
+	}
+
+	return part.option(string(pluralFormOf(rules, lang, value-part.offset)))
+}
+
+// option returns the option with the selector, or the other option.
+func (part icuPart) option(selector string) icuOption {
+	other := icuOption{}
+	for _, option := range part.options {
+		if option.selector == selector {
+			return option
+		}
+		if option.selector == string(PluralOther) {
+			other = option
+		}
+	}
+
+	return other
+}
+
+// formatICUNumber formats the number for the language with the style of a number argument.
+func formatICUNumber(lang language.Tag, f float64, style string) string {
+	switch style {
+	case "integer":
+		return textmessage.NewPrinter(lang).Sprint(number.Decimal(math.Round(f), number.MaxFractionDigits(0)))
+	case "percent":
+		return percentModifier(lang, f, "")
+	}
+
+	return textmessage.NewPrinter(lang).Sprint(number.Decimal(f))
+}
@@ -0,0 +1,121 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestICUMessages(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{
+		"welcome": "Welcome {userName}",
+		"cart": "{count, plural, =0 {Your cart is empty} one {One item} other {# items}}",
+		"guests": "{count, plural, offset:1 =0 {Nobody came} =1 {{host} came} one {{host} and one other came} other {{host} and # others came}}",
+		"invite": "{gender, select, female {She invited you} male {He invited you} other {They invited you}}",
+		"likes": "{gender, select, female {{count, plural, one {She has one like} other {She has # likes}}} other {{count, plural, one {One like} other {# likes}}}}",
+		"place": "You finished {place, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+		"total": "Total {total, number} or {total, number, integer}, {share, number, percent} off",
+		"start": "Starts {start, date, short} at {start, time}",
+		"size": "{size, bytes}",
+		"quotes": "It's '{literal}' and '{'{name}'}' with '' and #",
+		"rank": "{count, plural, other {'#'#}}",
+		"required": "{attribute} is required",
+		"command": "Run cmd:do_it for {name}",
+		"modifier": "Use x:upper|y {name}",
+		"condition": ":n == 1 ? {name} one | {name} other",
+		"attributes": {"email": "e-mail address"}
+	}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/pl.json", []byte(`{
+		"cart": "{count, plural, one {# produkt} few {# produkty} many {# produktów} other {# produktu}}"
+	}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "en"}), WithMessageFormat(ICU))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.Equal(t, "Welcome jan", tr.Translate(ctx, "welcome", map[string]any{"username": "jan"}))
+	require.Equal(t, "Welcome ", tr.Translate(ctx, "welcome", nil))
+
+	require.Equal(t, "Your cart is empty", tr.Translate(ctx, "cart", map[string]any{"count": 0}))
+	require.Equal(t, "One item", tr.Translate(ctx, "cart", map[string]any{"count": 1}))
+	require.Equal(t, "1,500 items", tr.Translate(ctx, "cart", map[string]any{"count": 1500}))
+	require.Equal(t, "1.5 items", tr.Translate(ctx, "cart", map[string]any{"count": 1.5}))
+
+	require.Equal(t, "Nobody came", tr.Translate(ctx, "guests", map[string]any{"count": 0, "host": "Jan"}))
+	require.Equal(t, "Jan came", tr.Translate(ctx, "guests", map[string]any{"count": 1, "host": "Jan"}))
+	require.Equal(t, "Jan and one other came", tr.Translate(ctx, "guests", map[string]any{"count": 2, "host": "Jan"}))
+	require.Equal(t, "Jan and 4 others came", tr.Translate(ctx, "guests", map[string]any{"count": 5, "host": "Jan"}))
+
+	require.Equal(t, "She invited you", tr.Translate(ctx, "invite", map[string]any{"gender": "female"}))
+	require.Equal(t, "They invited you", tr.Translate(ctx, "invite", map[string]any{"gender": "unknown"}))
+	require.Equal(t, "They invited you", tr.Translate(ctx, "invite", nil))
+	require.Equal(t, "She has 3 likes", tr.Translate(ctx, "likes", map[string]any{"gender": "female", "count": 3}))
+	require.Equal(t, "One like", tr.Translate(ctx, "likes", map[string]any{"count": 1}))
+
+	require.Equal(t, "You finished 1st", tr.Translate(ctx, "place", map[string]any{"place": 1}))
+	require.Equal(t, "You finished 22nd", tr.Translate(ctx, "place", map[string]any{"place": 22}))
+	require.Equal(t, "You finished 13th", tr.Translate(ctx, "place", map[string]any{"place": 13}))
+
+	require.Equal(t, "Total 1,234.5 or 1,235, 15% off", tr.Translate(ctx, "total", map[string]any{"total": 1234.5, "share": 0.15}))
+	require.Equal(t, "Starts 3/1/2024 at 2:30 PM", tr.Translate(ctx, "start", map[string]any{"start": time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)}))
+	require.Equal(t, "1.5 kB", tr.Translate(ctx, "size", map[string]any{"size": 1536}))
+	require.Equal(t, "It's {literal} and {jan} with ' and #", tr.Translate(ctx, "quotes", map[string]any{"name": "jan"}))
+	require.Equal(t, "#3", tr.Translate(ctx, "rank", map[string]any{"count": 3}))
+	require.Equal(t, "e-mail address is required", tr.Translate(ctx, "required", map[string]any{"attribute": "email"}))
+
+	// The syntax of the default format is text.
+	require.Equal(t, "Run cmd:do_it for jan", tr.Translate(ctx, "command", map[string]any{"name": "jan"}))
+	require.Equal(t, "Use x:upper|y jan", tr.Translate(ctx, "modifier", map[string]any{"name": "jan"}))
+	require.Equal(t, ":n == 1 ? jan one | jan other", tr.Translate(ctx, "condition", map[string]any{"name": "jan", "n": 1}))
+
+	pl := ToCtx(ctx, "pl")
+	require.Equal(t, "1 produkt", tr.Translate(pl, "cart", map[string]any{"count": 1}))
+	require.Equal(t, "3 produkty", tr.Translate(pl, "cart", map[string]any{"count": 3}))
+	require.Equal(t, "5 produktów", tr.Translate(pl, "cart", map[string]any{"count": 5}))
+	require.Equal(t, "1,5 produktu", tr.Translate(pl, "cart", map[string]any{"count": 1.5}))
+
+	// Plural messages select the form, the form is an ICU message.
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"cats": {"one": "{name} has one cat", "other": "{name} has {count} cats"}}`), 0o644))
+	tr, err = NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "en"}), WithMessageFormat(ICU))
+	require.NoError(t, err)
+	require.Equal(t, "Jan has 2 cats", tr.TranslatePlural(ctx, "cats", 2, map[string]any{"name": "Jan"}))
+}
+
+func TestICUMessagesMissing(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"cart": "{name}: {count, plural, one {# item} other {# items}}"}`), 0o644))
+
+	var missing []Missing
+	tr, err := NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "en"}), WithMessageFormat(ICU),
+		WithMissingHook(func(ctx context.Context, m Missing) { missing = append(missing, m) }), WithPlaceholderAudit(1))
+	require.NoError(t, err)
+
+	require.Equal(t, ": 2 items", tr.Translate(context.Background(), "cart", map[string]any{"count": 2}))
+	require.Equal(t, []Missing{{Language: LanguageID{Language: "en"}, Key: "cart", Placeholder: "{name}"}}, missing)
+}
+
+func TestICUMessagesInvalid(t *testing.T) {
+	for _, value := range []string{
+		"Welcome {name",
+		"Welcome name}",
+		"{}",
+		"{count, plural, one {One}}",
+		"{count, plural, single {One} other {Many}}",
+		"{count, plural, one {One} other {Many}",
+		"{gender, select, male {He}}",
+		"{total, number, currency}",
+		"{total, unknown}",
+		"{total,}",
+	} {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(fmt.Sprintf(`{"key": %q}`, value)), 0o644))
+
+		_, err := NewTranslator(fs, "translations", WithMessageFormat(ICU))
+		require.ErrorIs(t, err, ErrInvalidICUMessage, value)
+	}
+}
@@ -0,0 +1,128 @@
+package messages
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// IgnoreDirective skips the keys of the source code after it during extraction, e.g. intentionally dynamic keys or test fixtures:
+//
+//	//msgextractor:ignore
+//	func TestAttributes(t *testing.T) { ... }
+//
+//	tr.Translate(ctx, "attributes", nil) //msgextractor:ignore
+//
+// At the end of a line it skips the line, on its own line it skips the declaration or statement that starts on the next line,
+// including its block. Before the package clause it skips the whole file. Text after the directive, like a reason, is allowed.
+const IgnoreDirective = "//msgextractor:ignore"
+
+// ignoredRange is a range of the source code that is skipped.
+type ignoredRange struct {
+	pos, end token.Pos
+}
+
+// ignoredRanges are the ranges of the source code that the ignore directives skip.
+type ignoredRanges []ignoredRange
+
+// contains reports if pos is in one of the ranges.
+func (r ignoredRanges) contains(pos token.Pos) bool {
+	for _, ignored := range r {
+		if pos >= ignored.pos && pos < ignored.end {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ignoredInPackage returns the ranges that the ignore directives in the files of the package skip.
+func ignoredInPackage(pkg *packages.Package) ignoredRanges {
+	var ranges ignoredRanges
+	for _, file := range pkg.Syntax {
+		ranges = append(ranges, ignoredInFile(pkg.Fset, file)...)
+	}
+
+	return ranges
+}
+
+// ignoredInFile returns the ranges that the ignore directives in the file skip.
+func ignoredInFile(fset *token.FileSet, file *ast.File) ignoredRanges {
+	var ranges ignoredRanges
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if !isIgnoreDirective(comment.Text) {
+				continue
+			}
+
+			if comment.Pos() < file.Package {
+				return ignoredRanges{{pos: file.FileStart, end: file.FileEnd}}
+			}
+
+			tokenFile := fset.File(comment.Pos())
+			line := tokenFile.Line(comment.Pos())
+			if !isTrailingComment(file, tokenFile, comment) {
+				line = tokenFile.Line(group.End()) + 1
+			}
+
+			ranges = append(ranges, lineRange(file, tokenFile, line))
+		}
+	}
+
+	return ranges
+}
+
+// isIgnoreDirective reports if the comment is the ignore directive, optionally followed by a reason.
+func isIgnoreDirective(text string) bool {
+	rest, ok := strings.CutPrefix(text, IgnoreDirective)
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// isTrailingComment reports if the comment is at the end of a line with code.
+func isTrailingComment(file *ast.File, tokenFile *token.File, comment *ast.Comment) bool {
+	line := tokenFile.Line(comment.Pos())
+
+	trailing := false
+	ast.Inspect(file, func(node ast.Node) bool {
+		if trailing || node == nil || node.Pos() >= comment.Pos() || node.End() <= tokenFile.LineStart(line) {
+			return !trailing
+		}
+
+		if _, isComment := node.(*ast.CommentGroup); !isComment && tokenFile.Line(node.Pos()) == line {
+			trailing = true
+		}
+
+		return true
+	})
+
+	return trailing
+}
+
+// lineRange returns the range of the line and of the outermost node that starts on the line, e.g. a function with its body.
+func lineRange(file *ast.File, tokenFile *token.File, line int) ignoredRange {
+	if line > tokenFile.LineCount() {
+		return ignoredRange{}
+	}
+
+	r := ignoredRange{pos: tokenFile.LineStart(line), end: file.FileEnd}
+	if line < tokenFile.LineCount() {
+		r.end = tokenFile.LineStart(line + 1)
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil || node.End() < r.pos || node.Pos() >= r.end {
+			return false
+		}
+
+		if _, isComment := node.(*ast.CommentGroup); !isComment && tokenFile.Line(node.Pos()) == line && node.End() > r.end {
+			r.end = node.End()
+			return false
+		}
+
+		return true
+	})
+
+	return r
+}
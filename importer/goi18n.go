@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/wvell/messages"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+var (
+	ErrInvalidGoI18n = fmt.Errorf("invalid go-i18n message file")
+)
+
+// goI18nFields are the fields of a message in a go-i18n file, a table with one of these fields is a message instead of a group.
+var goI18nFields = []string{"id", "description", "hash", "leftdelim", "rightdelim", "zero", "one", "two", "few", "many", "other"}
+
+// goI18nFieldRe matches the name of a template field like .Name or .User.Name.
+var goI18nFieldRe = regexp.MustCompile(`^\s*\.([A-Za-z]+(?:\.[A-Za-z]+)*)\s*$`)
+
+// GoI18n converts a go-i18n v2 message file in the TOML or JSON format, by the extension of filename, to messages.
+// The Language is the language of the file name, e.g. "nl" for active.nl.toml:
+//
+//   - Template fields like {{.Name}} become placeholders like :name, {{.PluralCount}} becomes :count.
+//   - Messages with plural forms become plural messages, translate them with messages.Translator.TranslatePlural.
+//   - Descriptions are added to the Metadata, nested groups of messages become keys with dots, e.g. "errors.notFound".
+//   - Messages with other template actions, like {{if}}, are kept as is with a warning, load them with messages.WithTemplates.
+func GoI18n(filename string, data []byte) (*Catalog, error) {
+	var root any
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".json":
+		if err := json.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidGoI18n, filename, err)
+		}
+	case ".toml":
+		table, err := parseTOML(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidGoI18n, filename, err)
+		}
+		root = table
+	default:
+		return nil, fmt.Errorf("%w: %s: unsupported format %q, use .toml or .json", ErrInvalidGoI18n, filename, ext)
+	}
+
+	imported := newCatalog()
+	imported.Language, _ = goI18nLanguage(filename)
+
+	if err := imported.addGoI18n("", root); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidGoI18n, filename, err)
+	}
+
+	imported.sortWarnings()
+
+	return imported, nil
+}
+
+// goI18nLanguage returns the language of a go-i18n file name, the last part of the name before the extension, e.g. "nl" for active.nl.toml.
+func goI18nLanguage(filename string) (messages.LanguageID, error) {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return messages.ParseLanguage(name)
+}
+
+// add adds the message or group of messages in value with the key prefix.
+func (imp *Catalog) addGoI18n(prefix string, value any) error {
+	switch v := value.(type) {
+	case string:
+		return imp.addGoI18nMessage(prefix, map[string]any{"other": v})
+	case []any:
+		// The array format of go-i18n: [{"id": "welcome", "other": "Welcome"}].
+		for _, item := range v {
+			fields, ok := item.(map[string]any)
+			id, _ := fields["id"].(string)
+			if !ok || id == "" {
+				return fmt.Errorf("a message in an array needs an id")
+			}
+
+			if err := imp.addGoI18nMessage(joinKey(prefix, id), fields); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		if isGoI18nMessage(v) {
+			return imp.addGoI18nMessage(prefix, v)
+		}
+
+		for _, key := range sorted.Keys(v) {
+			if err := imp.addGoI18n(joinKey(prefix, key), v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("message %q: unsupported value %v", prefix, value)
+	}
+
+	return nil
+}
+
+// isGoI18nMessage reports if the table is a message, a table with a message field, instead of a group of messages.
+func isGoI18nMessage(table map[string]any) bool {
+	for key, value := range table {
+		if _, isString := value.(string); isString && slices.Contains(goI18nFields, strings.ToLower(key)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addMessage converts the fields of a message.
+func (imp *Catalog) addGoI18nMessage(key string, fields map[string]any) error {
+	field := func(name string) string {
+		for k, v := range fields {
+			if s, ok := v.(string); ok && strings.EqualFold(k, name) {
+				return s
+			}
+		}
+
+		return ""
+	}
+
+	if key == "" {
+		return fmt.Errorf("a message needs an id")
+	}
+
+	if description := field("description"); description != "" {
+		imp.Metadata[key] = messages.KeyMetadata{Description: description}
+	}
+
+	leftDelim, rightDelim := field("leftDelim"), field("rightDelim")
+	if leftDelim == "" {
+		leftDelim = "{{"
+	}
+	if rightDelim == "" {
+		rightDelim = "}}"
+	}
+
+	warn := func(format string, args ...any) {
+		imp.warn(key, format, args...)
+	}
+
+	forms := make(map[messages.PluralForm]string)
+	var last messages.PluralForm
+	for _, form := range messages.PluralForms {
+		if text := field(string(form)); text != "" {
+			forms[form] = text
+			last = form
+		}
+	}
+
+	if len(forms) == 0 {
+		return fmt.Errorf("message %q has no translation", key)
+	}
+
+	// A plural message needs the other form, the last form that the message has is used for it.
+	if _, ok := forms[messages.PluralOther]; !ok {
+		forms[messages.PluralOther] = forms[last]
+		warn("the message has no other form, the %s form is used for every other count", last)
+	}
+
+	for _, form := range messages.PluralForms {
+		text, ok := forms[form]
+		if !ok {
+			continue
+		}
+
+		converted, ok := convertGoI18nTemplate(text, leftDelim, rightDelim)
+		if !ok {
+			warn("the %s form is kept as a template, load it with WithTemplates", form)
+			converted = text
+		}
+
+		// A message with only the other form is a normal message.
+		if len(forms) == 1 {
+			imp.Messages.Messages[key] = converted
+			continue
+		}
+
+		imp.Messages.Messages[messages.PluralKey(key, form)] = converted
+	}
+
+	return nil
+}
+
+// convertGoI18nTemplate converts the template fields to placeholders, ok is false if the template has other actions.
+// Colons that would start a placeholder are escaped.
+func convertGoI18nTemplate(text, leftDelim, rightDelim string) (string, bool) {
+	var out strings.Builder
+	for {
+		start := strings.Index(text, leftDelim)
+		if start < 0 {
+			out.WriteString(escapeColons(text))
+			return out.String(), true
+		}
+
+		end := strings.Index(text[start+len(leftDelim):], rightDelim)
+		if end < 0 {
+			return "", false
+		}
+		end += start + len(leftDelim)
+
+		match := goI18nFieldRe.FindStringSubmatch(text[start+len(leftDelim) : end])
+		if match == nil {
+			return "", false
+		}
+
+		// A placeholder that is directly followed by a letter, digit or underscore would include it in its name.
+		rest := text[end+len(rightDelim):]
+		if next := []rune(rest + " ")[0]; unicode.IsLetter(next) || unicode.IsDigit(next) || next == '_' {
+			return "", false
+		}
+
+		name := strings.ToLower(match[1])
+		if name == "pluralcount" {
+			name = messages.PluralCountKey
+		}
+
+		out.WriteString(escapeColons(text[:start]))
+		out.WriteString(":" + name)
+		text = rest
+	}
+}
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wvell/messages"
+)
+
+func TestImportGoI18nTOML(t *testing.T) {
+	imported, err := GoI18n("translations/active.nl.toml", []byte(`
+# Generated by goi18n.
+Hello = "Hallo {{.Name}}"
+Time = 'Tijd:nu {{ .Time }}'
+
+[Cats]
+description = "The number of cats"
+one = "{{.PluralCount}} kat"
+other = "{{.PluralCount}} katten"
+
+[Unread]
+leftDelim = "<<"
+rightDelim = ">>"
+zero = "Geen berichten"
+one = "Eén bericht"
+few = "Enkele berichten"
+other = """
+<<.Count>> berichten"""
+
+[Greeting]
+other = "{{if .Name}}Hallo {{.Name}}{{else}}Hallo{{end}}"
+
+[errors.notFound]
+other = "Niet gevonden: {{.ID}}"
+`))
+	require.NoError(t, err)
+	require.Equal(t, messages.LanguageID{Language: "nl"}, imported.Language)
+	require.Equal(t, map[string]string{
+		"Hello":           "Hallo :name",
+		"Time":            `Tijd\:nu :time`,
+		"Cats[one]":       ":count kat",
+		"Cats[other]":     ":count katten",
+		"Unread[zero]":    "Geen berichten",
+		"Unread[one]":     "Eén bericht",
+		"Unread[few]":     "Enkele berichten",
+		"Unread[other]":   ":count berichten",
+		"Greeting":        "{{if .Name}}Hallo {{.Name}}{{else}}Hallo{{end}}",
+		"errors.notFound": "Niet gevonden: :id",
+	}, imported.Messages.Messages)
+	require.Equal(t, messages.Metadata{"Cats": {Description: "The number of cats"}}, imported.Metadata)
+	require.Equal(t, []string{
+		"Greeting: the other form is kept as a template, load it with WithTemplates",
+	}, imported.Warnings)
+
+	// The converted messages are valid for the translator.
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("translations", 0o755))
+	require.NoError(t, messages.NewFileStore(fs, "translations").Save(imported.Language, imported.Messages))
+	tr, err := messages.NewTranslator(fs, "translations", messages.WithDefaultLanguage(imported.Language), messages.WithTemplates())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.Equal(t, "1 kat", tr.TranslatePlural(ctx, "Cats", 1, nil))
+	require.Equal(t, "3 katten", tr.TranslatePlural(ctx, "Cats", 3, nil))
+	require.Equal(t, "0 berichten", tr.TranslatePlural(ctx, "Unread", 0, nil))
+	require.Equal(t, "Tijd:nu 12:00", tr.Translate(ctx, "Time", map[string]any{"time": "12:00"}))
+}
+
+func TestImportGoI18nTOMLEscapes(t *testing.T) {
+	imported, err := GoI18n("en.toml", []byte(`
+Say = "Say \"hi\" to {{.Name}}"
+Path = "C:\\temp\tdir"
+Euro = "\u20AC \U0001F600"
+Literal = 'No \"escapes\"'
+`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"Say":     `Say "hi" to :name`,
+		"Path":    "C:\\temp\tdir",
+		"Euro":    "\u20ac \U0001f600",
+		"Literal": `No \"escapes\"`,
+	}, imported.Messages.Messages)
+
+	_, err = GoI18n("en.toml", []byte(`welcome = "Welcome\q"`))
+	require.ErrorIs(t, err, ErrInvalidGoI18n)
+
+	_, err = GoI18n("en.toml", []byte(`welcome = "Welcome\u20"`))
+	require.ErrorIs(t, err, ErrInvalidGoI18n)
+}
+
+func TestImportGoI18nJSON(t *testing.T) {
+	imported, err := GoI18n("en.json", []byte(`{
+		"welcome": "Welcome {{.UserName}}",
+		"items": {"description": "Items in the cart", "one": "One item", "other": "{{.Count}} items"},
+		"unread": {"one": "One message"},
+		"user": {"count": "{{.Count1}} users", "plural": "{{.Count}}s"}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, messages.LanguageID{Language: "en"}, imported.Language)
+	require.Equal(t, map[string]string{
+		"welcome":       "Welcome :username",
+		"items[one]":    "One item",
+		"items[other]":  ":count items",
+		"unread[one]":   "One message",
+		"unread[other]": "One message",
+		"user.count":    "{{.Count1}} users",
+		"user.plural":   "{{.Count}}s",
+	}, imported.Messages.Messages)
+	require.Equal(t, messages.Metadata{"items": {Description: "Items in the cart"}}, imported.Metadata)
+	require.Len(t, imported.Warnings, 3)
+
+	// The array format.
+	imported, err = GoI18n("messages.json", []byte(`[{"id": "welcome", "other": "Welcome"}]`))
+	require.NoError(t, err)
+	require.True(t, imported.Language.Empty())
+	require.Equal(t, map[string]string{"welcome": "Welcome"}, imported.Messages.Messages)
+
+	_, err = GoI18n("en.yaml", []byte(`welcome: Welcome`))
+	require.ErrorIs(t, err, ErrInvalidGoI18n)
+
+	_, err = GoI18n("en.toml", []byte(`count = 1`))
+	require.ErrorIs(t, err, ErrInvalidGoI18n)
+
+	_, err = GoI18n("en.toml", []byte(`welcome = "Welcome`))
+	require.ErrorIs(t, err, ErrInvalidGoI18n)
+}
@@ -0,0 +1,84 @@
+// Package importer converts the message files of other translation libraries to the translation files of the messages package:
+// go-i18n v2 files with GoI18n, Rails YAML locale files with RailsYAML and Laravel lang files with LaravelPHP and LaravelJSON.
+// Merge the messages of a Catalog into a translation file with messages.Merge, "msgextractor import" does this for files on disk.
+package importer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/wvell/messages"
+)
+
+const (
+	// conditionSeparator separates the messages of a condition, see the conditions of the messages package.
+	conditionSeparator = " | "
+	// attributesKey is the key of the attributes in the translation files.
+	attributesKey = "attributes"
+)
+
+// Catalog is a catalog of another translation library that is converted to messages,
+// see GoI18n, RailsYAML, LaravelPHP and LaravelJSON.
+type Catalog struct {
+	// Language is the language of the catalog. It is empty if the file does not tell the language.
+	Language messages.LanguageID
+	Messages *messages.RawMessages
+	// Metadata holds the descriptions of the messages.
+	Metadata messages.Metadata
+	// Warnings describe the messages that are not converted exactly, e.g. forms that are kept as templates.
+	Warnings []string
+}
+
+func newCatalog() *Catalog {
+	return &Catalog{
+		Messages: &messages.RawMessages{Messages: make(map[string]string), Attributes: make(map[string]string)},
+		Metadata: messages.Metadata{},
+	}
+}
+
+// warn adds a warning for the message of key.
+func (imp *Catalog) warn(key, format string, args ...any) {
+	imp.Warnings = append(imp.Warnings, fmt.Sprintf("%s: ", key)+fmt.Sprintf(format, args...))
+}
+
+// sortWarnings sorts the warnings, so an import always reports them in the same order. The forms of a plural message can report
+// the same warning, duplicates are removed.
+func (imp *Catalog) sortWarnings() {
+	slices.Sort(imp.Warnings)
+	imp.Warnings = slices.Compact(imp.Warnings)
+}
+
+// joinKey joins the key of a group and the key of a message in the group with a dot.
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+// escapeColons escapes the colons that are followed by a letter, so they are not read as a placeholder.
+func escapeColons(text string) string {
+	var out strings.Builder
+	for i, r := range text {
+		if r == ':' && i+1 < len(text) && unicode.IsLetter([]rune(text[i+1:])[0]) {
+			out.WriteString(`\`)
+		}
+		out.WriteRune(r)
+	}
+
+	return out.String()
+}
+
+// importPlaceholderName converts the name of a placeholder of another library to the name of a placeholder, e.g. "firstname" for
+// "first_name". Renamed is true if the name is changed other than the case, ok is false if the name can not be converted.
+func importPlaceholderName(name string) (converted string, renamed, ok bool) {
+	converted = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	if converted == "" || strings.IndexFunc(converted, func(r rune) bool { return !unicode.IsLetter(r) && r != '.' }) >= 0 {
+		return "", false, false
+	}
+
+	return converted, converted != strings.ToLower(name), true
+}
@@ -0,0 +1,459 @@
+package importer
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/wvell/messages"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+var (
+	ErrInvalidLaravel = fmt.Errorf("invalid Laravel lang file")
+)
+
+// laravelPlaceholderRe matches the placeholders of Laravel messages, which may have underscores and digits, e.g. :first_name.
+var laravelPlaceholderRe = regexp.MustCompile(`:([A-Za-z][A-Za-z0-9_]*)`)
+
+// laravelRangeRe matches the range of a segment of a Laravel plural message, e.g. "{0} None" or "[2,*] Many".
+var laravelRangeRe = regexp.MustCompile(`^\s*(?:\{(\d+)\}|\[(\d+|\*)\s*,\s*(\d+|\*)\])\s*`)
+
+// LaravelPHP converts a Laravel PHP lang file, like lang/nl/validation.php, that returns an array of messages:
+//
+//   - The keys get the name of the file as prefix and nested keys are joined with dots, e.g. "validation.required" and
+//     "validation.between.numeric". The attributes of validation.php become the attributes of the catalog.
+//   - Placeholders like :attribute are kept, underscores are removed: :first_name becomes :firstname.
+//   - Plural messages like "One apple|:count apples" become plural messages, translate them with messages.Translator.TranslatePlural.
+//     Plural messages with ranges like "{0} None|[1,19] Some|[20,*] Many" become conditions on :count.
+//
+// The Language is the language of the directory of the file, e.g. "nl" for lang/nl/validation.php.
+func LaravelPHP(filename string, data []byte) (*Catalog, error) {
+	p := &phpParser{src: string(data)}
+	entries, err := p.file()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidLaravel, filename, err)
+	}
+
+	imported := newCatalog()
+	imported.Language, _ = messages.ParseLanguage(filepath.Base(filepath.Dir(filename)))
+
+	group := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	for _, entry := range entries {
+		// The attributes of the validation messages are the values of the :attribute replacement, like the attributes of the messages package.
+		if attributes, ok := entry.value.([]phpEntry); ok && group == "validation" && entry.key == attributesKey {
+			for _, attribute := range attributes {
+				if value, ok := attribute.value.(string); ok {
+					imported.Messages.Attributes[attribute.key] = value
+				}
+			}
+			continue
+		}
+
+		imported.addLaravel(joinKey(group, entry.key), entry.value)
+	}
+
+	imported.sortWarnings()
+	return imported, nil
+}
+
+// LaravelJSON converts a Laravel JSON lang file, like lang/nl.json, with the source text as key. The messages are converted
+// like LaravelPHP converts them. The Language is the language of the file name.
+func LaravelJSON(filename string, data []byte) (*Catalog, error) {
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidLaravel, filename, err)
+	}
+
+	imported := newCatalog()
+	imported.Language, _ = messages.ParseLanguage(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)))
+
+	for _, key := range sorted.Keys(values) {
+		imported.addLaravel(key, values[key])
+	}
+
+	imported.sortWarnings()
+	return imported, nil
+}
+
+// addLaravel adds the message or the nested messages of value with the key.
+func (imp *Catalog) addLaravel(key string, value any) {
+	switch v := value.(type) {
+	case []phpEntry:
+		for _, entry := range v {
+			imp.addLaravel(joinKey(key, entry.key), entry.value)
+		}
+	case string:
+		if !strings.Contains(v, "|") {
+			imp.Messages.Messages[key] = imp.convertLaravel(key, v)
+			return
+		}
+
+		imp.addLaravelPlural(key, strings.Split(v, "|"))
+	}
+}
+
+// addLaravelPlural adds a Laravel plural message. Two segments without ranges are the one and other forms, segments with ranges
+// become conditions on :count. Other plural messages, and ranges that do not cover every count, are kept as is with a warning.
+func (imp *Catalog) addLaravelPlural(key string, segments []string) {
+	var ranges []laravelRange
+	for _, segment := range segments {
+		r, ok := parseLaravelRange(segment)
+		if !ok {
+			break
+		}
+
+		r.text = imp.convertLaravel(key, r.text)
+		ranges = append(ranges, r)
+	}
+
+	switch {
+	case len(ranges) == 0 && len(segments) == 2:
+		imp.Messages.Messages[messages.PluralKey(key, messages.PluralOne)] = imp.convertLaravel(key, strings.TrimSpace(segments[0]))
+		imp.Messages.Messages[messages.PluralKey(key, messages.PluralOther)] = imp.convertLaravel(key, strings.TrimSpace(segments[1]))
+		return
+	case len(ranges) == len(segments):
+		// The ranges are matched in order, the last range is the message of the counts that the ranges before it do not match.
+		last := ranges[len(ranges)-1]
+		if last.to != math.MaxInt || !laravelCovered(ranges[:len(ranges)-1], last.from) {
+			imp.warn(key, "the ranges of the plural message do not cover every count, the message is kept as is")
+			imp.Messages.Messages[key] = imp.convertLaravel(key, strings.Join(segments, "|"))
+			return
+		}
+
+		message := last.text
+		for i := len(ranges) - 2; i >= 0; i-- {
+			r := ranges[i]
+			switch {
+			case r.from == r.to:
+				message = fmt.Sprintf(":%s == %d ? %s%s%s", messages.PluralCountKey, r.from, r.text, conditionSeparator, message)
+			case r.to == math.MaxInt:
+				message = fmt.Sprintf(":%s >= %d ? %s%s%s", messages.PluralCountKey, r.from, r.text, conditionSeparator, message)
+			case laravelCovered(ranges[:i], r.from):
+				// The counts before the start of the range are matched by the ranges before it, so the range is a condition on its end.
+				message = fmt.Sprintf(":%s <= %d ? %s%s%s", messages.PluralCountKey, r.to, r.text, conditionSeparator, message)
+			default:
+				imp.warn(key, "the plural message can not be converted to conditions, the message is kept as is")
+				imp.Messages.Messages[key] = imp.convertLaravel(key, strings.Join(segments, "|"))
+				return
+			}
+		}
+
+		imp.Messages.Messages[key] = message
+		return
+	}
+
+	imp.warn(key, "the plural message with %d forms can not be converted, the message is kept as is", len(segments))
+	imp.Messages.Messages[key] = imp.convertLaravel(key, strings.Join(segments, "|"))
+}
+
+// laravelRange is a segment of a Laravel plural message with the counts it matches, to is math.MaxInt for "*".
+type laravelRange struct {
+	from, to int
+	text     string
+}
+
+// parseLaravelRange parses the range of a segment, ok is false if the segment has no range.
+func parseLaravelRange(segment string) (laravelRange, bool) {
+	match := laravelRangeRe.FindStringSubmatch(segment)
+	if match == nil {
+		return laravelRange{}, false
+	}
+
+	r := laravelRange{text: strings.TrimSpace(segment[len(match[0]):])}
+	if match[1] != "" {
+		r.from, _ = strconv.Atoi(match[1])
+		r.to = r.from
+		return r, true
+	}
+
+	if match[2] != "*" {
+		r.from, _ = strconv.Atoi(match[2])
+	}
+
+	r.to = math.MaxInt
+	if match[3] != "*" {
+		r.to, _ = strconv.Atoi(match[3])
+	}
+
+	return r, true
+}
+
+// laravelCovered reports if the ranges match every count below count.
+func laravelCovered(ranges []laravelRange, count int) bool {
+	byFrom := slices.Clone(ranges)
+	slices.SortFunc(byFrom, func(a, b laravelRange) int {
+		return cmp.Compare(a.from, b.from)
+	})
+
+	next := 0
+	for _, r := range byFrom {
+		if next >= count || r.from > next {
+			break
+		}
+		if r.to == math.MaxInt {
+			return true
+		}
+
+		next = max(next, r.to+1)
+	}
+
+	return next >= count
+}
+
+// convertLaravel converts the placeholders of a Laravel message. The placeholders of Laravel are the placeholders of the messages package,
+// but they can have underscores and digits.
+func (imp *Catalog) convertLaravel(key, value string) string {
+	return laravelPlaceholderRe.ReplaceAllStringFunc(value, func(placeholder string) string {
+		name := placeholder[1:]
+		converted, renamed, ok := importPlaceholderName(name)
+		if !ok {
+			imp.warn(key, "the placeholder :%s can not be converted, it is escaped", name)
+			return `\` + placeholder
+		}
+
+		// An uppercase placeholder like :NAME uppercases the value in Laravel, here it capitalizes the value.
+		if len(name) > 1 && strings.ToUpper(name) == name {
+			imp.warn(key, "the placeholder :%s capitalizes the value instead of uppercasing it", name)
+			return ":" + strings.ToUpper(converted[:1]) + converted[1:]
+		}
+
+		if renamed {
+			imp.warn(key, "the placeholder :%s is renamed to :%s", name, converted)
+		}
+
+		if unicode.IsUpper(rune(name[0])) {
+			return ":" + strings.ToUpper(converted[:1]) + converted[1:]
+		}
+
+		return ":" + converted
+	})
+}
+
+// phpEntry is a key and value of a PHP array, the value is a string or a nested array.
+type phpEntry struct {
+	key   string
+	value any
+}
+
+// phpParser reads the PHP lang files of Laravel: a file that returns an array with strings and nested arrays.
+type phpParser struct {
+	src string
+	pos int
+}
+
+func (p *phpParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", strings.Count(p.src[:p.pos], "\n")+1, fmt.Sprintf(format, args...))
+}
+
+// skip skips spaces and comments.
+func (p *phpParser) skip() {
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		switch {
+		case unicode.IsSpace(rune(rest[0])):
+			p.pos++
+		case strings.HasPrefix(rest, "//"), rest[0] == '#':
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			p.pos += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				p.pos = len(p.src)
+				return
+			}
+			p.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+// consume skips spaces and comments and reads s if the source continues with it.
+func (p *phpParser) consume(s string) bool {
+	p.skip()
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+
+	return false
+}
+
+// file reads `<?php return [...];`, the statements before the return, like declare(strict_types=1);, are skipped.
+func (p *phpParser) file() ([]phpEntry, error) {
+	if !p.consume("<?php") {
+		return nil, p.errorf("the file does not start with <?php")
+	}
+
+	for {
+		p.skip()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("the file does not return an array")
+		}
+
+		if p.consume("return") {
+			break
+		}
+
+		end := strings.IndexByte(p.src[p.pos:], ';')
+		if end < 0 {
+			return nil, p.errorf("the file does not return an array")
+		}
+		p.pos += end + 1
+	}
+
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, ok := value.([]phpEntry)
+	if !ok {
+		return nil, p.errorf("the file does not return an array")
+	}
+
+	return entries, nil
+}
+
+// value reads a string, a number or an array. Strings can be concatenated with a dot.
+func (p *phpParser) value() (any, error) {
+	p.skip()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of file")
+	}
+
+	switch c := p.src[p.pos]; {
+	case c == '[':
+		p.pos++
+		return p.array("]")
+	case strings.HasPrefix(p.src[p.pos:], "array"):
+		p.pos += len("array")
+		if !p.consume("(") {
+			return nil, p.errorf("expected ( after array")
+		}
+		return p.array(")")
+	case c == '\'' || c == '"':
+		var text strings.Builder
+		for {
+			s, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			text.WriteString(s)
+
+			if !p.consume(".") {
+				return text.String(), nil
+			}
+			p.skip()
+			if p.pos >= len(p.src) || (p.src[p.pos] != '\'' && p.src[p.pos] != '"') {
+				return nil, p.errorf("only strings can be concatenated")
+			}
+		}
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		return p.src[start:p.pos], nil
+	}
+
+	return nil, p.errorf("unsupported value, only strings and arrays are supported")
+}
+
+// array reads the entries of an array up to the closing bracket. Entries without a key get the next index as key.
+func (p *phpParser) array(end string) ([]phpEntry, error) {
+	var entries []phpEntry
+	index := 0
+	for {
+		if p.consume(end) {
+			return entries, nil
+		}
+
+		first, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		entry := phpEntry{key: strconv.Itoa(index), value: first}
+		if p.consume("=>") {
+			key, ok := first.(string)
+			if !ok {
+				return nil, p.errorf("an array can not be a key")
+			}
+
+			entry.key = key
+			if entry.value, err = p.value(); err != nil {
+				return nil, err
+			}
+		}
+		if i, err := strconv.Atoi(entry.key); err == nil && i >= index {
+			index = i + 1
+		}
+
+		entries = append(entries, entry)
+
+		if !p.consume(",") && !strings.HasPrefix(p.src[p.pos:], end) {
+			p.skip()
+			return nil, p.errorf("expected , or %s", end)
+		}
+	}
+}
+
+// str reads a single or double quoted string.
+func (p *phpParser) str() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+
+	var text strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return text.String(), nil
+		case c == '\\' && p.pos+1 < len(p.src):
+			next := p.src[p.pos+1]
+			p.pos += 2
+
+			// Single quoted strings only escape the quote and the backslash.
+			if quote == '\'' {
+				if next != '\'' && next != '\\' {
+					text.WriteByte('\\')
+				}
+				text.WriteByte(next)
+				continue
+			}
+
+			switch next {
+			case 'n':
+				text.WriteByte('\n')
+			case 't':
+				text.WriteByte('\t')
+			case 'r':
+				text.WriteByte('\r')
+			case '"', '\\', '$':
+				text.WriteByte(next)
+			default:
+				text.WriteByte('\\')
+				text.WriteByte(next)
+			}
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/wvell/messages"
+)
+
+func TestImportLaravelPHP(t *testing.T) {
+	imported, err := LaravelPHP("lang/nl/validation.php", []byte(`<?php
+
+declare(strict_types=1);
+
+// Validation messages.
+return [
+    'required' => 'Het :attribute veld is verplicht.',
+    'between' => array(
+        'numeric' => ':Attribute moet tussen :min en :max liggen.', # Numbers.
+        "string" => ":attribute moet tussen " . ':min en :max tekens zijn.',
+    ),
+    'same' => 'De :attribute en :other_field moeten overeenkomen.',
+    'shout' => ':NAME is verplicht.',
+    'apples' => 'Eén appel|:count appels',
+    'range' => '{0} Geen appels|[1,19] Enkele appels|[20,*] Veel appels',
+    'many' => '[1,19] Enkele appels|[20,*] Veel appels',
+    'exact' => '{5} Vijf appels|[0,*] Appels',
+    'odd' => 'Een|Twee|Drie',
+    'time' => 'Tijd: nu',
+    'list' => ['eerste', 'tweede'],
+    /* The names of the attributes. */
+    'attributes' => [
+        'email' => 'e-mailadres',
+    ],
+];
+`))
+	require.NoError(t, err)
+	require.Equal(t, messages.LanguageID{Language: "nl"}, imported.Language)
+	require.Equal(t, map[string]string{
+		"validation.required":        "Het :attribute veld is verplicht.",
+		"validation.between.numeric": ":Attribute moet tussen :min en :max liggen.",
+		"validation.between.string":  ":attribute moet tussen :min en :max tekens zijn.",
+		"validation.same":            "De :attribute en :otherfield moeten overeenkomen.",
+		"validation.shout":           ":Name is verplicht.",
+		"validation.apples[one]":     "Eén appel",
+		"validation.apples[other]":   ":count appels",
+		"validation.range":           ":count == 0 ? Geen appels | :count <= 19 ? Enkele appels | Veel appels",
+		"validation.many":            "[1,19] Enkele appels|[20,*] Veel appels",
+		"validation.exact":           ":count == 5 ? Vijf appels | Appels",
+		"validation.odd":             "Een|Twee|Drie",
+		"validation.time":            "Tijd: nu",
+		"validation.list.0":          "eerste",
+		"validation.list.1":          "tweede",
+	}, imported.Messages.Messages)
+	require.Equal(t, map[string]string{"email": "e-mailadres"}, imported.Messages.Attributes)
+	require.Equal(t, []string{
+		"validation.many: the ranges of the plural message do not cover every count, the message is kept as is",
+		"validation.odd: the plural message with 3 forms can not be converted, the message is kept as is",
+		"validation.same: the placeholder :other_field is renamed to :otherfield",
+		"validation.shout: the placeholder :NAME capitalizes the value instead of uppercasing it",
+	}, imported.Warnings)
+
+	for _, data := range []string{
+		`return ['a' => 'b'];`,
+		`<?php return ['a' => 'b'`,
+		`<?php return ['a' => 'b];`,
+		`<?php return ['a' => $b];`,
+		`<?php return 'a';`,
+	} {
+		_, err := LaravelPHP("lang/nl/messages.php", []byte(data))
+		require.ErrorIs(t, err, ErrInvalidLaravel, data)
+	}
+}
+
+func TestImportLaravelJSON(t *testing.T) {
+	imported, err := LaravelJSON("lang/nl.json", []byte(`{
+		"Welcome, :name": "Welkom, :name",
+		"Cats": "Eén kat|:count katten"
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, messages.LanguageID{Language: "nl"}, imported.Language)
+	require.Equal(t, map[string]string{
+		"Welcome, :name": "Welkom, :name",
+		"Cats[one]":      "Eén kat",
+		"Cats[other]":    ":count katten",
+	}, imported.Messages.Messages)
+	require.Empty(t, imported.Warnings)
+
+	_, err = LaravelJSON("lang/nl.json", []byte(`{"a": ["b"]}`))
+	require.ErrorIs(t, err, ErrInvalidLaravel)
+}
@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/wvell/messages"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidRailsYAML = fmt.Errorf("invalid Rails locale file")
+)
+
+// railsInterpolationRe matches the interpolations of Rails messages like %{name}, and the escaped %%{name}.
+var railsInterpolationRe = regexp.MustCompile(`%?%\{([^{}]*)\}`)
+
+// RailsYAML converts a Rails locale file to a catalog per locale, the top level keys of the file:
+//
+//   - The nested keys are joined with dots, e.g. "activerecord.errors.messages.blank". Lists get the index as key, e.g. "date.day_names.0".
+//   - Interpolations like %{name} become placeholders like :name. Underscores are removed, %{first_name} becomes :firstname.
+//   - A group with the keys one, other and the other plural forms becomes a plural message, translate it with messages.Translator.TranslatePlural.
+//     The zero form of Rails is used for a count of 0 in every language, it becomes a condition in the other forms.
+//
+// Values that are not text, like the numbers in the number formats, are skipped with a warning.
+func RailsYAML(data []byte) ([]*Catalog, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRailsYAML, err)
+	}
+
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	locales := root.Content[0]
+	if locales.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w: the file has no locales", ErrInvalidRailsYAML)
+	}
+
+	var catalogs []*Catalog
+	for i := 0; i+1 < len(locales.Content); i += 2 {
+		locale := locales.Content[i].Value
+		lang, err := messages.ParseLanguage(locale)
+		if err != nil {
+			return nil, fmt.Errorf("%w: locale %q: %w", ErrInvalidRailsYAML, locale, err)
+		}
+
+		imported := newCatalog()
+		imported.Language = lang
+		imported.addRails("", locales.Content[i+1])
+		imported.sortWarnings()
+
+		catalogs = append(catalogs, imported)
+	}
+
+	slices.SortFunc(catalogs, func(a, b *Catalog) int {
+		return cmp.Compare(a.Language.String(), b.Language.String())
+	})
+
+	return catalogs, nil
+}
+
+// addRails adds the message, plural message or group of messages of the node with the key prefix.
+func (imp *Catalog) addRails(prefix string, node *yaml.Node) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		if forms, ok := railsPluralForms(node); ok {
+			imp.addRailsPlural(prefix, forms)
+			return
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			// A merge key like "<<: *defaults" adds the keys of the anchor.
+			if node.Content[i].Value == "<<" {
+				imp.addRails(prefix, node.Content[i+1])
+				continue
+			}
+
+			imp.addRails(joinKey(prefix, node.Content[i].Value), node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			imp.addRails(joinKey(prefix, strconv.Itoa(i)), item)
+		}
+	case yaml.ScalarNode:
+		switch {
+		case node.Tag == "!!null":
+			// A nil value is a missing translation in Rails.
+		case node.Tag != "!!str":
+			imp.warn(prefix, "the %s value %q is skipped, only text is imported", strings.TrimPrefix(node.Tag, "!!"), node.Value)
+		default:
+			if strings.HasPrefix(node.Value, ":") && !strings.ContainsFunc(node.Value, unicode.IsSpace) {
+				imp.warn(prefix, "the symbol %s refers to another key in Rails, it is imported as text", node.Value)
+			}
+
+			imp.Messages.Messages[prefix] = imp.convertRails(prefix, node.Value)
+		}
+	}
+}
+
+// railsPluralForms returns the forms of a mapping that is a plural message, a mapping with only plural forms and the other form.
+func railsPluralForms(node *yaml.Node) (map[messages.PluralForm]string, bool) {
+	forms := make(map[messages.PluralForm]string)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		form, value := messages.PluralForm(node.Content[i].Value), node.Content[i+1]
+		if !slices.Contains(messages.PluralForms, form) || value.Kind != yaml.ScalarNode || value.Tag != "!!str" {
+			return nil, false
+		}
+		forms[form] = value.Value
+	}
+
+	_, hasOther := forms[messages.PluralOther]
+	return forms, hasOther
+}
+
+// addRailsPlural adds the forms of a plural message. The zero form of Rails is not a CLDR form, it is used for 0 in every language.
+func (imp *Catalog) addRailsPlural(key string, forms map[messages.PluralForm]string) {
+	zero, hasZero := forms[messages.PluralZero]
+	if hasZero {
+		zero = imp.convertRails(key, zero)
+		delete(forms, messages.PluralZero)
+
+		if strings.Contains(zero, conditionSeparator) {
+			imp.warn(key, "the zero form is dropped, it can not be converted to a condition")
+			hasZero = false
+		}
+	}
+
+	for form, value := range forms {
+		message := imp.convertRails(key, value)
+		if hasZero {
+			message = fmt.Sprintf(":%s == 0 ? %s%s%s", messages.PluralCountKey, zero, conditionSeparator, message)
+		}
+
+		imp.Messages.Messages[messages.PluralKey(key, form)] = message
+	}
+}
+
+// convertRails converts the interpolations of a Rails message to placeholders, colons that would start a placeholder are escaped.
+// The message is kept as is when an interpolation can not be converted.
+func (imp *Catalog) convertRails(key, value string) string {
+	var out strings.Builder
+	rest := value
+	for {
+		loc := railsInterpolationRe.FindStringSubmatchIndex(rest)
+		if loc == nil {
+			out.WriteString(escapeColons(rest))
+			return out.String()
+		}
+
+		out.WriteString(escapeColons(rest[:loc[0]]))
+
+		// %%{name} is the literal text %{name}.
+		if strings.HasPrefix(rest[loc[0]:], "%%") {
+			out.WriteString(rest[loc[0]+1 : loc[1]])
+			rest = rest[loc[1]:]
+			continue
+		}
+
+		name := rest[loc[2]:loc[3]]
+		converted, renamed, ok := importPlaceholderName(name)
+		next := []rune(rest[loc[1]:] + " ")[0]
+		if !ok || unicode.IsLetter(next) || unicode.IsDigit(next) || next == '_' {
+			imp.warn(key, "the interpolation %%{%s} can not be converted to a placeholder, the message is kept as is", name)
+			return value
+		}
+
+		if renamed {
+			imp.warn(key, "the interpolation %%{%s} is renamed to :%s", name, converted)
+		}
+
+		out.WriteString(":" + converted)
+		rest = rest[loc[1]:]
+	}
+}
@@ -0,0 +1,69 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/wvell/messages"
+)
+
+func TestImportRailsYAML(t *testing.T) {
+	catalogs, err := RailsYAML([]byte(`
+nl:
+  defaults: &defaults
+    save: Opslaan
+  buttons:
+    <<: *defaults
+    cancel: Annuleren
+  hello: "Hallo %{first_name}"
+  discount: "%{percent}% korting, typ %%{code}"
+  time: "Tijd:nu"
+  cats:
+    zero: Geen katten
+    one: Eén kat
+    other: "%{count} katten"
+  date:
+    day_names: [zondag, maandag]
+  number:
+    precision: 2
+  missing:
+  back: :buttons.cancel
+en:
+  hello: "Hello %{name}s"
+`))
+	require.NoError(t, err)
+	require.Len(t, catalogs, 2)
+
+	en := catalogs[0]
+	require.Equal(t, messages.LanguageID{Language: "en"}, en.Language)
+	require.Equal(t, map[string]string{"hello": "Hello %{name}s"}, en.Messages.Messages)
+	require.Equal(t, []string{"hello: the interpolation %{name} can not be converted to a placeholder, the message is kept as is"}, en.Warnings)
+
+	nl := catalogs[1]
+	require.Equal(t, messages.LanguageID{Language: "nl"}, nl.Language)
+	require.Equal(t, map[string]string{
+		"defaults.save":    "Opslaan",
+		"buttons.save":     "Opslaan",
+		"buttons.cancel":   "Annuleren",
+		"hello":            "Hallo :firstname",
+		"discount":         ":percent% korting, typ %{code}",
+		"time":             `Tijd\:nu`,
+		"cats[one]":        ":count == 0 ? Geen katten | Eén kat",
+		"cats[other]":      ":count == 0 ? Geen katten | :count katten",
+		"date.day_names.0": "zondag",
+		"date.day_names.1": "maandag",
+		"back":             `\:buttons.cancel`,
+	}, nl.Messages.Messages)
+	require.Equal(t, []string{
+		"back: the symbol :buttons.cancel refers to another key in Rails, it is imported as text",
+		"hello: the interpolation %{first_name} is renamed to :firstname",
+		`number.precision: the int value "2" is skipped, only text is imported`,
+	}, nl.Warnings)
+
+	_, err = RailsYAML([]byte(`- nl`))
+	require.ErrorIs(t, err, ErrInvalidRailsYAML)
+
+	_, err = RailsYAML([]byte(`nl: [`))
+	require.ErrorIs(t, err, ErrInvalidRailsYAML)
+}
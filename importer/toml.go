@@ -0,0 +1,291 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tomlLineEndingBackslashRe matches a backslash at the end of a line of a multi-line string, with the whitespace that follows it.
+var tomlLineEndingBackslashRe = regexp.MustCompile(`\\[ \t]*\r?\n\s*`)
+
+// parseTOML parses the subset of TOML that go-i18n writes: tables, dotted and quoted keys and strings.
+// Other values, like numbers and arrays of tables, are not used in message files and return an error.
+func parseTOML(data string) (map[string]any, error) {
+	root := make(map[string]any)
+	current := root
+
+	p := &tomlParser{data: data, line: 1}
+	for {
+		p.skipSpace(true)
+		if p.done() {
+			return root, nil
+		}
+
+		if p.peek() == '[' {
+			p.pos++
+			if p.peek() == '[' {
+				return nil, p.errorf("arrays of tables are not supported")
+			}
+
+			keys, err := p.keys(']')
+			if err != nil {
+				return nil, err
+			}
+			p.pos++
+
+			current, err = tomlTable(root, keys)
+			if err != nil {
+				return nil, p.errorf("%v", err)
+			}
+		} else {
+			keys, err := p.keys('=')
+			if err != nil {
+				return nil, err
+			}
+			p.pos++
+
+			p.skipSpace(false)
+			value, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+
+			table, err := tomlTable(current, keys[:len(keys)-1])
+			if err != nil {
+				return nil, p.errorf("%v", err)
+			}
+
+			last := keys[len(keys)-1]
+			if _, exists := table[last]; exists {
+				return nil, p.errorf("duplicate key %q", last)
+			}
+			table[last] = value
+		}
+
+		p.skipSpace(false)
+		if !p.done() && p.peek() != '\n' {
+			return nil, p.errorf("unexpected %q after the value", p.peek())
+		}
+	}
+}
+
+// tomlTable returns the table with the dotted keys in root, tables that do not exist are created.
+func tomlTable(root map[string]any, keys []string) (map[string]any, error) {
+	table := root
+	for _, key := range keys {
+		value, ok := table[key]
+		if !ok {
+			value = make(map[string]any)
+			table[key] = value
+		}
+
+		next, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("key %q is not a table", key)
+		}
+		table = next
+	}
+
+	return table, nil
+}
+
+// tomlParser reads the TOML subset of parseTOML.
+type tomlParser struct {
+	data string
+	pos  int
+	line int
+}
+
+func (p *tomlParser) done() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *tomlParser) peek() byte {
+	return p.data[p.pos]
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips spaces and comments, and newlines when newlines is true.
+func (p *tomlParser) skipSpace(newlines bool) {
+	for !p.done() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && newlines:
+			p.pos++
+			p.line++
+		case c == '#':
+			for !p.done() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// keys reads dotted keys up to the end byte, e.g. `a."b.c" =`.
+func (p *tomlParser) keys(end byte) ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace(false)
+		if p.done() {
+			return nil, p.errorf("unexpected end of file")
+		}
+
+		var key string
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			var err error
+			key, err = p.str()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			start := p.pos
+			for !p.done() && (isBareKeyByte(p.peek())) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("invalid key at %q", p.peek())
+			}
+			key = p.data[start:p.pos]
+		}
+		keys = append(keys, key)
+
+		p.skipSpace(false)
+		if p.done() {
+			return nil, p.errorf("unexpected end of file")
+		}
+
+		switch p.peek() {
+		case '.':
+			p.pos++
+		case end:
+			return keys, nil
+		default:
+			return nil, p.errorf("unexpected %q in key", p.peek())
+		}
+	}
+}
+
+func isBareKeyByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// str reads a basic, literal or multi-line string.
+func (p *tomlParser) str() (string, error) {
+	if p.done() {
+		return "", p.errorf("unexpected end of file, expected a string")
+	}
+
+	rest := p.data[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, `"""`), strings.HasPrefix(rest, `'''`):
+		delim := rest[:3]
+		end := strings.Index(rest[3:], delim)
+		if end < 0 {
+			return "", p.errorf("unterminated multi-line string")
+		}
+
+		// Quotes directly before the closing delimiter are part of the string.
+		for end+3+3 < len(rest) && rest[3+end+3] == delim[0] {
+			end++
+		}
+
+		value := rest[3 : 3+end]
+		p.pos += 3 + end + 3
+		p.line += strings.Count(value, "\n")
+
+		// A newline directly after the opening delimiter is trimmed.
+		value = strings.TrimPrefix(strings.TrimPrefix(value, "\r"), "\n")
+		if delim == `'''` {
+			return value, nil
+		}
+
+		// A backslash at the end of a line trims the newline and the whitespace that follows.
+		value = tomlLineEndingBackslashRe.ReplaceAllString(value, "")
+
+		return unquoteTOML(value, p)
+	case rest[0] == '\'':
+		end := strings.IndexAny(rest[1:], "'\n")
+		if end < 0 || rest[1+end] != '\'' {
+			return "", p.errorf("unterminated string")
+		}
+
+		p.pos += end + 2
+		return rest[1 : 1+end], nil
+	case rest[0] == '"':
+		end := 1
+		for end < len(rest) && rest[end] != '"' && rest[end] != '\n' {
+			if rest[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(rest) || rest[end] != '"' {
+			return "", p.errorf("unterminated string")
+		}
+
+		p.pos += end + 1
+		return unquoteTOML(rest[1:end], p)
+	default:
+		return "", p.errorf("unsupported value, only strings are supported")
+	}
+}
+
+// unquoteTOML replaces the escape sequences of a basic string.
+func unquoteTOML(value string, p *tomlParser) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			out.WriteByte(value[i])
+			continue
+		}
+
+		if i+1 >= len(value) {
+			return "", p.errorf("invalid escape sequence in %q", value)
+		}
+
+		i++
+		switch c := value[i]; c {
+		case 'b':
+			out.WriteByte('\b')
+		case 't':
+			out.WriteByte('\t')
+		case 'n':
+			out.WriteByte('\n')
+		case 'f':
+			out.WriteByte('\f')
+		case 'r':
+			out.WriteByte('\r')
+		case 'e':
+			out.WriteByte(0x1b)
+		case '"', '\\':
+			out.WriteByte(c)
+		case 'u', 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+
+			code, err := strconv.ParseUint(value[i+1:min(i+1+size, len(value))], 16, 32)
+			if err != nil || i+size >= len(value) || !utf8.ValidRune(rune(code)) {
+				return "", p.errorf("invalid escape sequence in %q", value)
+			}
+
+			out.WriteRune(rune(code))
+			i += size
+		default:
+			return "", p.errorf("invalid escape sequence in %q", value)
+		}
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,100 @@
+package messages
+
+import (
+	"strings"
+)
+
+// The invisible characters of the in-context markers, see WithInContextMarkers.
+const (
+	inContextStart   = '\u2062' // Invisible times, starts the key.
+	inContextEnd     = '\u2063' // Invisible separator, ends the key and starts the message.
+	inContextClose   = '\u2064' // Invisible plus, ends the message.
+	inContextZeroBit = '\u200b' // Zero width space.
+	inContextOneBit  = '\u200c' // Zero width non-joiner.
+)
+
+// WithInContextMarkers wraps every translated message with invisible unicode characters that encode its key,
+// so in-context editing tools can find the key of every string on a page. The markers do not change how the message looks,
+// but they do change its length and comparisons, use it in translation environments only. See InContextKeys to read the keys.
+func WithInContextMarkers() Opt {
+	return func(t *Translator) {
+		t.inContextMarkers = true
+	}
+}
+
+// inContextMarker wraps the message with the markers of the key.
+// Every byte of the key is encoded as 8 zero width characters, most significant bit first.
+func inContextMarker(key Key, out string) string {
+	var b strings.Builder
+	b.Grow(len(key)*8*3 + len(out) + 9)
+
+	b.WriteRune(inContextStart)
+	for i := 0; i < len(key); i++ {
+		for bit := 7; bit >= 0; bit-- {
+			if key[i]&(1<<bit) != 0 {
+				b.WriteRune(inContextOneBit)
+			} else {
+				b.WriteRune(inContextZeroBit)
+			}
+		}
+	}
+	b.WriteRune(inContextEnd)
+	b.WriteString(out)
+	b.WriteRune(inContextClose)
+
+	return b.String()
+}
+
+// InContextKeys returns the keys of the messages with in-context markers in s, in the order they appear, see WithInContextMarkers.
+// Markers that can not be decoded are skipped.
+func InContextKeys(s string) []Key {
+	var keys []Key
+
+	for {
+		start := strings.IndexRune(s, inContextStart)
+		if start < 0 {
+			return keys
+		}
+		s = s[start+len(string(inContextStart)):]
+
+		end := strings.IndexRune(s, inContextEnd)
+		if end < 0 {
+			return keys
+		}
+
+		if key, ok := decodeInContextKey(s[:end]); ok {
+			keys = append(keys, key)
+		}
+		s = s[end+len(string(inContextEnd)):]
+	}
+}
+
+// decodeInContextKey decodes the bits of an in-context marker, ok is false if the bits are not a whole number of bytes.
+func decodeInContextKey(bits string) (Key, bool) {
+	var key []byte
+	var current byte
+	var n int
+
+	for _, r := range bits {
+		current <<= 1
+		switch r {
+		case inContextOneBit:
+			current |= 1
+		case inContextZeroBit:
+		default:
+			return "", false
+		}
+
+		n++
+		if n%8 == 0 {
+			key = append(key, current)
+			current = 0
+		}
+	}
+
+	if n == 0 || n%8 != 0 {
+		return "", false
+	}
+
+	return Key(key), true
+}
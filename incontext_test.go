@@ -0,0 +1,30 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInContextMarkers(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/valid", WithInContextMarkers())
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "nl")
+	require.NoError(t, err)
+
+	out := tr.Translate(ctx, "welcome.login", map[string]any{"user": "jan"})
+	require.NotEqual(t, "Welkom jan", out)
+	require.Contains(t, out, "Welkom jan")
+
+	page := "<h1>" + out + "</h1><p>" + inContextMarker("café.ünïcode", "x") + "</p>"
+	require.Equal(t, []Key{"welcome.login", "café.ünïcode"}, InContextKeys(page))
+}
+
+func TestInContextKeysInvalid(t *testing.T) {
+	require.Empty(t, InContextKeys("no markers"))
+	require.Empty(t, InContextKeys("\u2062\u200b\u200c\u2063broken"))
+	require.Empty(t, InContextKeys("\u2062unterminated"))
+}
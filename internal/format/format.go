@@ -0,0 +1,51 @@
+// Package format holds the placeholders and the formatting of replacements that the messages and tiny packages share,
+// so both format a message the same way.
+package format
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Placeholder matches placeholders like :user, :User, :address.street or :total|percent(1). The groups are the backslash of an
+// escaped placeholder like \:user, the name, the modifier and the arguments of the modifier.
+const Placeholder = `(\\?):([A-Za-z]+(?:\.[A-Za-z]+)*)(?:\|([a-z]+)(?:\(([^)]*)\))?)?`
+
+// Basic formats a replacement of a basic type: strings, integers, floats with two decimals, booleans and string slices.
+// Ok is false for the other types, the messages package formats them with reflection.
+func Basic(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case int:
+		return strconv.Itoa(v), true
+	case int8:
+		return strconv.FormatInt(int64(v), 10), true
+	case int16:
+		return strconv.FormatInt(int64(v), 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', 2, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []string:
+		return strings.Join(v, ", "), true
+	}
+
+	return "", false
+}
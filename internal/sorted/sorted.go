@@ -0,0 +1,17 @@
+// Package sorted returns the keys of maps in sorted order, so files, errors and output are written in the same order every run.
+package sorted
+
+import (
+	"cmp"
+	"slices"
+
+	"golang.org/x/exp/maps"
+)
+
+// Keys returns the keys of the map in sorted order.
+func Keys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,24 @@
+// Package wire holds the field numbers of proto/messages/v1/catalog.proto that the messages and tiny packages share,
+// so the tiny decoder reads the bundles that messages.MarshalBundle writes.
+package wire
+
+import "google.golang.org/protobuf/encoding/protowire"
+
+// The field numbers of the Bundle message.
+const (
+	BundleVersion      protowire.Number = 1
+	BundleLastModified protowire.Number = 2
+	BundleLanguages    protowire.Number = 3
+)
+
+// The field numbers of the Messages message.
+const (
+	MessagesMessages   protowire.Number = 1
+	MessagesAttributes protowire.Number = 2
+)
+
+// The field numbers of the entries of a map field.
+const (
+	MapKey   protowire.Number = 1
+	MapValue protowire.Number = 2
+)
@@ -0,0 +1,200 @@
+package messages
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyConstantsFile is the name of the file with the key constants of a package, see PackageKeys.
+const KeyConstantsFile = "messages_keys.go"
+
+// ErrDuplicateKeyConstant is returned when two keys of a package have the same constant name, e.g. "user.name" and "user_name".
+var ErrDuplicateKeyConstant = fmt.Errorf("keys have the same constant name")
+
+// PackageKeys holds the translation keys that are used by a go package.
+type PackageKeys struct {
+	// Name is the package name.
+	Name string
+	// Dir is the directory of the package.
+	Dir string
+	// Keys holds the sorted keys that are used in the package.
+	Keys []string
+
+	literals []keyLiteral
+}
+
+// keyLiteral is a string literal that is passed as the translation key in a call.
+type keyLiteral struct {
+	file       string
+	start, end int
+	key        string
+}
+
+// PackageKeysFromSourceCode finds the translation keys per package in dir and its subdirectories.
+// The key constants file of a package is skipped, so keys that are no longer used are removed when the file is generated again.
+func PackageKeysFromSourceCode(ctx context.Context, dir string, opts ...ExtractOpt) ([]*PackageKeys, error) {
+	cfg := newExtractConfig(opts...)
+
+	dirs, err := findDirsRecursively(dir, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*PackageKeys
+	for _, dir := range dirs {
+		pkgs, err := loadPackages(ctx, cfg, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pkg := range pkgs {
+			if pkg.TypesInfo == nil || len(pkg.CompiledGoFiles) == 0 {
+				continue
+			}
+
+			p := &PackageKeys{Name: pkg.Name, Dir: filepath.Dir(pkg.CompiledGoFiles[0])}
+			generated := func(pos token.Pos) bool {
+				return filepath.Base(pkg.Fset.Position(pos).Filename) == KeyConstantsFile
+			}
+
+			var keys []string
+			for expr, def := range pkg.TypesInfo.Types {
+				if generated(expr.Pos()) {
+					continue
+				}
+
+				if cfg.isKeyType(def.Type) && def.Value != nil {
+					keys = append(keys, catalogKey(strings.Trim(def.Value.ExactString(), "\"")))
+				} else if call, ok := expr.(*ast.CallExpr); ok {
+					if translation := processCallExpr(cfg, pkg.TypesInfo, call); translation != "" {
+						keys = append(keys, catalogKey(translation))
+					}
+				}
+			}
+
+			for _, file := range pkg.Syntax {
+				if generated(file.Pos()) {
+					continue
+				}
+
+				ast.Inspect(file, func(node ast.Node) bool {
+					call, ok := node.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+
+					for _, arg := range call.Args {
+						lit, ok := arg.(*ast.BasicLit)
+						if !ok || lit.Kind != token.STRING || !cfg.isKeyType(pkg.TypesInfo.TypeOf(lit)) {
+							continue
+						}
+
+						translation, err := strconv.Unquote(lit.Value)
+						// Keys with a casing directive can not be replaced by the constant.
+						if err != nil || catalogKey(translation) != translation {
+							continue
+						}
+
+						start, end := pkg.Fset.Position(lit.Pos()), pkg.Fset.Position(lit.End())
+						p.literals = append(p.literals, keyLiteral{file: start.Filename, start: start.Offset, end: end.Offset, key: translation})
+					}
+
+					return true
+				})
+			}
+
+			if len(keys) == 0 {
+				continue
+			}
+
+			p.Keys = removeDuplicates(keys)
+			slices.Sort(p.Keys)
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
+
+// KeyConstant returns the name of the constant of the key, e.g. "keyWelcomeLogin" for "welcome.login".
+// Every part of the key that is separated by a character that is not a letter or a digit starts with an uppercase letter.
+func KeyConstant(key string) string {
+	var b strings.Builder
+	b.WriteString("key")
+
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		r, size := utf8.DecodeRuneInString(part)
+		b.WriteRune(unicode.ToUpper(r))
+		b.WriteString(part[size:])
+	}
+
+	return b.String()
+}
+
+// Generate returns the source of the key constants file of the package.
+// The constants are unexported and have the key type, e.g.:
+//
+//	const keyWelcomeLogin messages.Key = "welcome.login"
+func (p *PackageKeys) Generate() ([]byte, error) {
+	names := make(map[string]string, len(p.Keys))
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by msgextractor keys. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", p.Name)
+	b.WriteString("import \"github.com/wvell/messages\"\n\n")
+	b.WriteString("const (\n")
+
+	for _, key := range p.Keys {
+		name := KeyConstant(key)
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("%w: %q and %q are both %s", ErrDuplicateKeyConstant, other, key, name)
+		}
+		names[name] = key
+
+		fmt.Fprintf(&b, "\t%s messages.Key = %s\n", name, strconv.Quote(key))
+	}
+
+	b.WriteString(")\n")
+
+	return format.Source(b.Bytes())
+}
+
+// Rewrite replaces the key literals in the calls of the package with the constants from Generate and returns the new source per file.
+// Only files with a key literal are returned, keys with a casing directive like "button.save!upper" are not replaced.
+func (p *PackageKeys) Rewrite() (map[string][]byte, error) {
+	byFile := make(map[string][]keyLiteral)
+	for _, lit := range p.literals {
+		byFile[lit.file] = append(byFile[lit.file], lit)
+	}
+
+	files := make(map[string][]byte, len(byFile))
+	for file, literals := range byFile {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		// Replace from the end of the file so the offsets of the other literals stay valid.
+		slices.SortFunc(literals, func(a, b keyLiteral) int { return b.start - a.start })
+		for _, lit := range literals {
+			src = slices.Concat(src[:lit.start], []byte(KeyConstant(lit.key)), src[lit.end:])
+		}
+
+		files[file] = src
+	}
+
+	return files, nil
+}
@@ -0,0 +1,56 @@
+package messages
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyConstant(t *testing.T) {
+	require.Equal(t, "keyWelcomeLogin", KeyConstant("welcome.login"))
+	require.Equal(t, "keyErrorsMinLength", KeyConstant("errors.min_length"))
+	require.Equal(t, "keyÜberUns", KeyConstant("über-uns"))
+}
+
+func TestPackageKeys(t *testing.T) {
+	pkgs, err := PackageKeysFromSourceCode(context.Background(), "./testdata/key-constants")
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+
+	// The unused key in the generated file is not kept.
+	pkg := pkgs[0]
+	require.Equal(t, "app", pkg.Name)
+	require.Equal(t, []string{"button.save", "errors.min_length", "welcome.login"}, pkg.Keys)
+
+	src, err := pkg.Generate()
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by msgextractor keys. DO NOT EDIT.
+
+package app
+
+import "github.com/wvell/messages"
+
+const (
+	keyButtonSave      messages.Key = "button.save"
+	keyErrorsMinLength messages.Key = "errors.min_length"
+	keyWelcomeLogin    messages.Key = "welcome.login"
+)
+`, string(src))
+
+	files, err := pkg.Rewrite()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	for file, src := range files {
+		require.Equal(t, "app.go", filepath.Base(file))
+		require.Contains(t, string(src), `tr.Translate(ctx, keyWelcomeLogin, map[string]any{"user": name})`)
+		require.Contains(t, string(src), `tr.Translate(ctx, keyErrorsMinLength, nil)`)
+		// Keys with a casing directive are kept.
+		require.Contains(t, string(src), `tr.Translate(ctx, "button.save!upper", nil)`)
+	}
+
+	_, err = (&PackageKeys{Name: "app", Keys: []string{"user.name", "user_name"}}).Generate()
+	require.ErrorIs(t, err, ErrDuplicateKeyConstant)
+}
@@ -0,0 +1,114 @@
+package messages
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// LayeredLoader is a Loader that combines the translations of multiple loaders, see Layer.
+type LayeredLoader struct {
+	loaders []Loader
+
+	// Mu protects the result of the last load, it is used for the loaders that are not modified.
+	mu       sync.Mutex
+	etag     string
+	versions []Version
+	results  []map[LanguageID]*RawMessages
+	errs     []LanguageErrors
+}
+
+var _ Loader = (*LayeredLoader)(nil)
+
+// Layer combines the translations of the loaders, e.g. the embedded defaults, the files on disk and a remote catalog.
+// A message of a later loader takes precedence over the message of an earlier loader, a key that is missing in a later loader
+// is read through to the earlier loaders. Use the result with NewTranslatorFromLoader for a single Translate entry point,
+// and WithOverrides for the overrides of single messages on top of all layers:
+//
+//	loader := messages.Layer(embedded, messages.NewFileStore(fs, "translations"), messages.NewHTTPLoader(url, nil))
+//	tr, err := messages.NewTranslatorFromLoader(ctx, loader, messages.WithOverrides(redisOverrides))
+//
+// A reload only loads the loaders again that have changed. The load fails when one of the loaders fails, the LanguageErrors of the
+// loaders are combined.
+func Layer(loaders ...Loader) *LayeredLoader {
+	return &LayeredLoader{loaders: loaders}
+}
+
+func (l *LayeredLoader) Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// The versions of the loaders are only known for the last load.
+	previous := make([]Version, len(l.loaders))
+	if since.ETag != "" && since.ETag == l.etag {
+		copy(previous, l.versions)
+	}
+
+	modified := false
+	versions := make([]Version, len(l.loaders))
+	results := make([]map[LanguageID]*RawMessages, len(l.loaders))
+	layerErrs := make([]LanguageErrors, len(l.loaders))
+	for i, loader := range l.loaders {
+		languages, version, err := loader.Load(ctx, previous[i])
+		if errors.Is(err, ErrNotModified) && previous[i].ETag != "" {
+			versions[i], results[i], layerErrs[i] = previous[i], l.results[i], l.errs[i]
+			continue
+		}
+		if err != nil && !errors.As(err, &layerErrs[i]) {
+			return nil, Version{}, fmt.Errorf("layer %d: %w", i, err)
+		}
+
+		modified = true
+		versions[i], results[i] = version, languages
+	}
+
+	version := layeredVersion(versions)
+	if !modified && version.ETag == since.ETag {
+		return nil, since, ErrNotModified
+	}
+
+	l.etag, l.versions, l.results, l.errs = version.ETag, versions, results, layerErrs
+
+	merged := make(map[LanguageID]*RawMessages)
+	for _, languages := range results {
+		for lang, raw := range languages {
+			if _, ok := merged[lang]; !ok {
+				merged[lang] = &RawMessages{Messages: make(map[string]string), Attributes: make(map[string]string)}
+			}
+
+			mergeMessages(merged[lang], raw)
+		}
+	}
+
+	errs := LanguageErrors{}
+	for i, layerErr := range layerErrs {
+		for lang, err := range layerErr {
+			if _, ok := errs[lang]; !ok {
+				errs[lang] = fmt.Errorf("layer %d: %w", i, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return merged, version, errs
+	}
+
+	return merged, version, nil
+}
+
+// layeredVersion returns the version of the layers, the ETag is a hash of the ETags of the layers.
+func layeredVersion(versions []Version) Version {
+	var version Version
+	hash := sha256.New()
+	for _, v := range versions {
+		fmt.Fprintf(hash, "%q\n", v.ETag)
+		if v.LastModified.After(version.LastModified) {
+			version.LastModified = v.LastModified
+		}
+	}
+
+	version.ETag = hex.EncodeToString(hash.Sum(nil))
+	return version
+}
@@ -0,0 +1,60 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLoader counts the loads of the wrapped loader that are not ErrNotModified.
+type countingLoader struct {
+	Loader
+	loads int
+}
+
+func (l *countingLoader) Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error) {
+	languages, version, err := l.Loader.Load(ctx, since)
+	if err == nil {
+		l.loads++
+	}
+
+	return languages, version, err
+}
+
+func TestLayer(t *testing.T) {
+	embedded := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(embedded, "defaults/en.json", []byte(`{"welcome": "Welcome", "bye": "Bye", "save": "Save", "attributes": {"email": "email"}}`), 0o644))
+	require.NoError(t, afero.WriteFile(embedded, "defaults/nl.json", []byte(`{"welcome": "Welkom", "bye": "Doei"}`), 0o644))
+
+	disk := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(disk, "translations/en.json", []byte(`{"welcome": "Welcome back", "attributes": {"email": "email address"}}`), 0o644))
+	require.NoError(t, afero.WriteFile(disk, "translations/de.json", []byte(`{"welcome": "Willkommen"}`), 0o644))
+
+	remote := NewPushLoader()
+	remote.Push(map[LanguageID]*RawMessages{{Language: "en"}: {Messages: map[string]string{"bye": "Goodbye"}}}, Version{ETag: "1"})
+
+	base := &countingLoader{Loader: NewFileStore(embedded, "defaults")}
+	tr, err := NewTranslatorFromLoader(context.Background(), Layer(base, NewFileStore(disk, "translations"), remote))
+	require.NoError(t, err)
+
+	en := ToCtx(context.Background(), "en")
+	require.Equal(t, "Welcome back", tr.Translate(en, "welcome", nil))
+	require.Equal(t, "Goodbye", tr.Translate(en, "bye", nil))
+	require.Equal(t, "Save", tr.Translate(en, "save", nil))
+	require.Equal(t, "Willkommen", tr.Translate(ToCtx(context.Background(), "de"), "welcome", nil))
+	require.Equal(t, "Doei", tr.Translate(ToCtx(context.Background(), "nl"), "bye", nil))
+
+	// Nothing has changed.
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, 1, base.loads)
+
+	// Only the changed layer is loaded again, the other layers are kept.
+	remote.Push(map[LanguageID]*RawMessages{{Language: "en"}: {Messages: map[string]string{"bye": "See you"}}}, Version{ETag: "2"})
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, 1, base.loads)
+	require.Equal(t, "See you", tr.Translate(en, "bye", nil))
+	require.Equal(t, "Welcome back", tr.Translate(en, "welcome", nil))
+	require.Equal(t, "Save", tr.Translate(en, "save", nil))
+}
@@ -0,0 +1,293 @@
+package messages
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wvell/messages/internal/sorted"
+)
+
+// LintRule identifies the rule of a LintIssue.
+type LintRule string
+
+const (
+	// LintUnknownPlaceholder is a placeholder that the message of the default language does not have, it is replaced with nothing
+	// when the code does not pass it.
+	LintUnknownPlaceholder LintRule = "unknown-placeholder"
+	// LintAttributePlaceholder is a placeholder with the name of an attribute of the same file, use :attribute to translate an attribute.
+	LintAttributePlaceholder LintRule = "attribute-placeholder"
+	// LintUnusedAttribute is an attribute that is not passed as the :attribute replacement by the code, see WithLintAttributes,
+	// or an attribute in a catalog without messages that use :attribute.
+	LintUnusedAttribute LintRule = "unused-attribute"
+	// LintMissingAttribute is an attribute that another language has, the attribute of the default language is used for it.
+	LintMissingAttribute LintRule = "missing-attribute"
+	// LintUntranslatedAttribute is an attribute with its name as value, like "first_name": "first_name".
+	LintUntranslatedAttribute LintRule = "untranslated-attribute"
+	// LintSuspiciousPlaceholder is a placeholder that is probably not meant as a placeholder, like the :id in ":id_2" or the :s in "driver:s",
+	// see PlaceholderWarning.
+	LintSuspiciousPlaceholder LintRule = "suspicious-placeholder"
+)
+
+// LintIssue is a problem in a translation file that is valid, but probably not what the translator intended.
+type LintIssue struct {
+	File     string
+	Language LanguageID
+	Key      string
+	// Attribute is the name of the attribute for the attribute rules, Key is empty then.
+	Attribute string
+	Rule      LintRule
+	// Placeholder is the name of the placeholder the issue is about, without the colon.
+	Placeholder string
+	Message     string
+}
+
+func (i LintIssue) String() string {
+	if i.Attribute != "" {
+		return fmt.Sprintf("%s: %s.%s: %s (%s)", i.File, attributesKey, i.Attribute, i.Message, i.Rule)
+	}
+
+	return fmt.Sprintf("%s: %s: %s (%s)", i.File, i.Key, i.Message, i.Rule)
+}
+
+// LintOpt is a functional option for Lint.
+type LintOpt func(*lintConfig)
+
+type lintConfig struct {
+	parserOpts []ParserOpt
+	// Placeholders that are available in every message, e.g. from a ReplacementProvider.
+	placeholders []string
+	// The attributes that are passed as the :attribute replacement, nil if they are not known.
+	attributes []string
+}
+
+// WithLintParserOpts uses the given options for the parser that reads the translation files.
+func WithLintParserOpts(opts ...ParserOpt) LintOpt {
+	return func(c *lintConfig) {
+		c.parserOpts = append(c.parserOpts, opts...)
+	}
+}
+
+// WithLintPlaceholders adds placeholders that are available in every message, e.g. the placeholders of a ReplacementProvider.
+// They are never reported as unknown.
+func WithLintPlaceholders(names ...string) LintOpt {
+	return func(c *lintConfig) {
+		for _, name := range names {
+			c.placeholders = append(c.placeholders, strings.ToLower(name))
+		}
+	}
+}
+
+// WithLintAttributes reports the attributes that are not in names as unused, names are the attributes that are passed as the
+// :attribute replacement, e.g. the Attributes of ExtractFromSourceCodeCtx.
+func WithLintAttributes(names ...string) LintOpt {
+	return func(c *lintConfig) {
+		c.attributes = append(c.attributes, names...)
+		if c.attributes == nil {
+			c.attributes = []string{}
+		}
+	}
+}
+
+// lintFile is a translation file with its parsed placeholders by key.
+type lintFile struct {
+	file     string
+	language LanguageID
+	raw      *RawMessages
+	// placeholders holds the placeholders of every key, region overrides like "color@GB" are stored under their own key.
+	placeholders map[string][]string
+	// warnings are the suspicious placeholders of the file.
+	warnings []*PlaceholderWarning
+}
+
+// Lint checks the translation files in dir for placeholders that the message of the default language does not have,
+// and placeholders with the name of an attribute. Placeholders in the metadata of a key are known as well.
+// The attributes are checked for attributes that are not used, missing in a language or not translated.
+// Suspicious placeholders, like the :id in ":id_2", are reported as well.
+// Messages that can not be parsed are skipped, NewTranslator reports them.
+func Lint(fs afero.Fs, dir string, defaultLanguage LanguageID, opts ...LintOpt) ([]LintIssue, error) {
+	cfg := &lintConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	parser := NewParser(fs, cfg.parserOpts...)
+	files, err := lintFiles(parser, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := parser.MetadataFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultFile := slices.IndexFunc(files, func(f *lintFile) bool { return f.language == defaultLanguage })
+	if defaultFile < 0 {
+		defaultFile = slices.IndexFunc(files, func(f *lintFile) bool { return f.language == LanguageID{Language: defaultLanguage.Language} })
+	}
+	if defaultFile < 0 {
+		return nil, fmt.Errorf("no translation file for the default language %s in %s", defaultLanguage, dir)
+	}
+
+	// The placeholders of the default language by key, including the placeholders of its region overrides and plural forms.
+	known := make(map[string][]string)
+	for key, names := range files[defaultFile].placeholders {
+		base := lintBaseKey(key)
+		known[base] = append(known[base], names...)
+	}
+	for key, keyMetadata := range metadata {
+		for name := range keyMetadata.Placeholders {
+			known[key] = append(known[key], strings.ToLower(name))
+		}
+	}
+
+	// The attributes of all languages, and if any message of any language uses the :attribute placeholder.
+	var allAttributes []string
+	usesAttributes := false
+	for _, f := range files {
+		for attribute := range f.raw.Attributes {
+			allAttributes = append(allAttributes, attribute)
+		}
+
+		for _, names := range f.placeholders {
+			usesAttributes = usesAttributes || slices.Contains(names, AttributeKey)
+		}
+	}
+	slices.Sort(allAttributes)
+	allAttributes = slices.Compact(allAttributes)
+
+	var issues []LintIssue
+	for i, f := range files {
+		issue := func(key string, rule LintRule, placeholder, format string, args ...any) {
+			issues = append(issues, LintIssue{
+				File: f.file, Language: f.language, Key: key, Rule: rule, Placeholder: placeholder, Message: fmt.Sprintf(format, args...),
+			})
+		}
+		attributeIssue := func(attribute string, rule LintRule, format string, args ...any) {
+			issues = append(issues, LintIssue{
+				File: f.file, Language: f.language, Attribute: attribute, Rule: rule, Message: fmt.Sprintf(format, args...),
+			})
+		}
+
+		attributes := make(map[string]bool, len(f.raw.Attributes))
+		for name := range f.raw.Attributes {
+			attributes[strings.ToLower(name)] = true
+		}
+
+		for key, names := range f.placeholders {
+			defaultNames, translated := known[lintBaseKey(key)]
+
+			for _, name := range names {
+				if attributes[name] && name != AttributeKey {
+					issue(key, LintAttributePlaceholder, name, "placeholder :%s has the name of an attribute, use :%s to translate the attribute", name, AttributeKey)
+				}
+
+				if i == defaultFile || !translated || slices.Contains(defaultNames, name) || slices.Contains(cfg.placeholders, name) {
+					continue
+				}
+
+				issue(key, LintUnknownPlaceholder, name, "placeholder :%s is not in the message of the default language", name)
+			}
+		}
+
+		for _, warning := range f.warnings {
+			issue(warning.Key, LintSuspiciousPlaceholder, strings.TrimPrefix(warning.Placeholder, ":"), "placeholder %s %s", warning.Placeholder, warning.Reason)
+		}
+
+		for _, attribute := range sorted.Keys(f.raw.Attributes) {
+			switch {
+			case cfg.attributes != nil && !slices.Contains(cfg.attributes, attribute):
+				attributeIssue(attribute, LintUnusedAttribute, "attribute is not passed as the :%s replacement", AttributeKey)
+			case cfg.attributes == nil && !usesAttributes:
+				attributeIssue(attribute, LintUnusedAttribute, "attribute is not used, no message has the :%s placeholder", AttributeKey)
+			}
+
+			value := f.raw.Attributes[attribute]
+			defaultValue, inDefault := files[defaultFile].raw.Attributes[attribute]
+			if value == attribute && (strings.ContainsAny(attribute, "_.") || (i != defaultFile && inDefault && defaultValue != attribute)) {
+				attributeIssue(attribute, LintUntranslatedAttribute, "the value of the attribute is its name")
+			}
+		}
+
+		for _, attribute := range allAttributes {
+			if _, ok := f.raw.Attributes[attribute]; !ok {
+				attributeIssue(attribute, LintMissingAttribute, "attribute is missing, other languages have it")
+			}
+		}
+	}
+
+	slices.SortFunc(issues, func(a, b LintIssue) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Key, b.Key), cmp.Compare(a.Attribute, b.Attribute), cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.Placeholder, b.Placeholder))
+	})
+
+	return issues, nil
+}
+
+// lintBaseKey returns the key without the region and plural form, e.g. "cats" for "cats[one]@GB".
+// The placeholders of all forms of a plural message are known in every form, a form often leaves out :count.
+func lintBaseKey(key string) string {
+	key, _, _ = SplitRegionKey(key)
+	key, _, _ = SplitPluralKey(key)
+
+	return key
+}
+
+// lintFiles reads the translation files in dir and parses their placeholders, sorted by file name.
+func lintFiles(parser *Parser, dir string) ([]*lintFile, error) {
+	files, err := parser.TranslationFilesFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The warnings of the parser are collected per file, and passed on to the warnings option of the parser.
+	warn := parser.warn
+	defer func() { parser.warn = warn }()
+
+	var lintFiles []*lintFile
+	for languageID, file := range files {
+		lang, err := ParseLanguage(languageID)
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err := parser.messagesWithOverlay(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading file %s: %w", file, err)
+		}
+
+		f := &lintFile{file: filepath.Base(file), language: lang, raw: raw, placeholders: make(map[string][]string, len(raw.Messages))}
+		parser.warn = func(err error) {
+			var warning *PlaceholderWarning
+			if errors.As(err, &warning) {
+				f.warnings = append(f.warnings, warning)
+			}
+			if warn != nil {
+				warn(err)
+			}
+		}
+		for key, value := range raw.Messages {
+			msg, err := parser.parseMessage(key, parser.normalizeSpace(value))
+			if err != nil {
+				continue
+			}
+
+			names := msg.placeholders(nil)
+			slices.Sort(names)
+			f.placeholders[key] = slices.Compact(names)
+		}
+
+		lintFiles = append(lintFiles, f)
+	}
+
+	slices.SortFunc(lintFiles, func(a, b *lintFile) int {
+		return cmp.Compare(a.file, b.file)
+	})
+
+	return lintFiles, nil
+}
@@ -0,0 +1,109 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{
+		"welcome": "Welcome :user",
+		"color": "Color",
+		"color@GB": "Colour for :user",
+		"sent": "Sent to :email",
+		"required": ":Attribute is required",
+		"only_nl": "English",
+		"attributes": {"email": "email address"}
+	}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{
+		"welcome": "Welkom :naam",
+		"color": "Kleur voor :user",
+		"sent": "Verstuurd",
+		"required": ":Attribute is verplicht",
+		"only_nl": "Nederlands :app",
+		"untranslated": "Alleen in het Nederlands :foo",
+		"invalid": "Welkom :user_name",
+		"attributes": {"email": "e-mailadres"}
+	}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{"welcome": {"placeholders": {"naam": "string"}}}`), 0o644))
+
+	issues, err := Lint(fs, "translations", LanguageID{Language: "en"}, WithLintPlaceholders("App"))
+	require.NoError(t, err)
+	require.Equal(t, []LintIssue{
+		{
+			File: "en.json", Language: LanguageID{Language: "en"}, Key: "sent", Rule: LintAttributePlaceholder, Placeholder: "email",
+			Message: "placeholder :email has the name of an attribute, use :attribute to translate the attribute",
+		},
+		{
+			File: "nl.json", Language: LanguageID{Language: "nl"}, Key: "invalid", Rule: LintSuspiciousPlaceholder, Placeholder: "user",
+			Message: `placeholder :user is followed by '_', only letters and dots are part of a placeholder`,
+		},
+	}, issues)
+
+	// Without the metadata and the global placeholder the placeholders are unknown.
+	require.NoError(t, fs.Remove("translations/metadata.json"))
+	issues, err = Lint(fs, "translations", LanguageID{Language: "en", Region: "US"})
+	require.NoError(t, err)
+	require.Len(t, issues, 4)
+	require.Equal(t, "nl.json: only_nl: placeholder :app is not in the message of the default language (unknown-placeholder)", issues[2].String())
+	require.Equal(t, "welcome", issues[3].Key)
+	require.Equal(t, LintUnknownPlaceholder, issues[3].Rule)
+	require.Equal(t, "naam", issues[3].Placeholder)
+
+	_, err = Lint(fs, "translations", LanguageID{Language: "de"})
+	require.Error(t, err)
+}
+
+func TestLintAttributes(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{
+		"required": ":Attribute is required",
+		"attributes": {"email": "email", "first_name": "first_name", "street": "street", "zipcode": "zip code"}
+	}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{
+		"required": ":Attribute is verplicht",
+		"attributes": {"email": "e-mail", "first_name": "voornaam", "street": "street", "city": "plaats"}
+	}`), 0o644))
+
+	issues, err := Lint(fs, "translations", LanguageID{Language: "en"})
+	require.NoError(t, err)
+
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	require.Equal(t, []string{
+		"en.json: attributes.city: attribute is missing, other languages have it (missing-attribute)",
+		"en.json: attributes.first_name: the value of the attribute is its name (untranslated-attribute)",
+		"nl.json: attributes.zipcode: attribute is missing, other languages have it (missing-attribute)",
+	}, lines)
+
+	// The attributes that are not passed by the code are unused.
+	issues, err = Lint(fs, "translations", LanguageID{Language: "en"}, WithLintAttributes("email", "first_name", "zipcode"))
+	require.NoError(t, err)
+	var unused []string
+	for _, issue := range issues {
+		if issue.Rule == LintUnusedAttribute {
+			unused = append(unused, issue.Language.String()+":"+issue.Attribute)
+		}
+	}
+	require.Equal(t, []string{"en:street", "nl:city", "nl:street"}, unused)
+
+	// Without messages that use :attribute every attribute is unused.
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"attributes": {"email": "e-mail", "street": "straat"}}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"attributes": {"email": "email", "street": "street"}}`), 0o644))
+	issues, err = Lint(fs, "translations", LanguageID{Language: "en"})
+	require.NoError(t, err)
+	require.Len(t, issues, 4)
+	for _, issue := range issues {
+		require.Equal(t, LintUnusedAttribute, issue.Rule)
+	}
+
+	// An empty list of used attributes reports every attribute as well.
+	issues, err = Lint(fs, "translations", LanguageID{Language: "en"}, WithLintAttributes())
+	require.NoError(t, err)
+	require.Len(t, issues, 4)
+}
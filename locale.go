@@ -0,0 +1,351 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// LocaleResolver resolves the language of a request, ok is false if the resolver can not resolve the language.
+type LocaleResolver interface {
+	Resolve(r *http.Request) (lang LanguageID, ok bool)
+}
+
+// MultiLocaleResolver is a LocaleResolver that resolves all acceptable languages of a request in priority order.
+// The LocaleMiddleware stores all of them, so the Translator can use the second choice when it has no messages for the first.
+type MultiLocaleResolver interface {
+	LocaleResolver
+	ResolveAll(r *http.Request) (langs []LanguageID, ok bool)
+}
+
+// LocaleResolverFunc is a function that implements LocaleResolver.
+type LocaleResolverFunc func(r *http.Request) (LanguageID, bool)
+
+func (f LocaleResolverFunc) Resolve(r *http.Request) (LanguageID, bool) {
+	return f(r)
+}
+
+// LocaleMiddleware sets the language of the request context with the first resolver that resolves the language, see WithLanguage.
+// The context has no language when no resolver resolves the language, the default language of the Translator is used then.
+// A typical chain is the setting of the user, a cookie, the Accept-Language header and GeoIP:
+//
+//	messages.LocaleMiddleware(mux,
+//		messages.UserLocale(func(ctx context.Context) (string, bool) { ... }),
+//		messages.CookieLocale("lang"),
+//		messages.AcceptLanguageLocale(),
+//		messages.GeoIPLocale(func(addr netip.Addr) (messages.LanguageID, bool) { ... }),
+//	)
+func LocaleMiddleware(next http.Handler, resolvers ...LocaleResolver) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, resolver := range resolvers {
+			if multi, ok := resolver.(MultiLocaleResolver); ok {
+				if langs, ok := multi.ResolveAll(r); ok && len(langs) > 0 {
+					r = r.WithContext(toCtx(r.Context(), langs...))
+					break
+				}
+
+				continue
+			}
+
+			if lang, ok := resolver.Resolve(r); ok && !lang.Empty() {
+				r = r.WithContext(toCtx(r.Context(), lang))
+				break
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// LocaleTransport returns a RoundTripper that sets the Accept-Language header of outgoing requests to the languages of the request context,
+// so the language of a request is propagated when a service calls another service. The base language is added as a fallback for a language
+// with a region, e.g. "nl-BE, nl;q=0.9, en;q=0.8". Requests without a language in the context, or with an Accept-Language header, are not changed.
+// The http.DefaultTransport is used when next is nil:
+//
+//	client := &http.Client{Transport: messages.LocaleTransport(nil)}
+func LocaleTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return localeTransport{next: next}
+}
+
+// localeTransport is the RoundTripper of LocaleTransport.
+type localeTransport struct {
+	next http.RoundTripper
+}
+
+func (t localeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	langs := LanguagesFromCtx(r.Context())
+	if len(langs) == 0 || r.Header.Get("Accept-Language") != "" {
+		return t.next.RoundTrip(r)
+	}
+
+	acceptLanguage := acceptLanguageHeader(langs)
+
+	// A RoundTripper must not modify the request, the header is set on a clone.
+	r = r.Clone(r.Context())
+	r.Header.Set("Accept-Language", acceptLanguage)
+
+	return t.next.RoundTrip(r)
+}
+
+// acceptLanguageHeader returns the Accept-Language header for the languages in priority order.
+// The quality decreases by 0.1 for every language, with a minimum of 0.1.
+func acceptLanguageHeader(langs []LanguageID) string {
+	var tags []string
+	for _, lang := range langs {
+		for _, tag := range []string{lang.String(), lang.Language} {
+			if slices.Contains(tags, tag) {
+				continue
+			}
+
+			tags = append(tags, tag)
+		}
+	}
+
+	for i := 1; i < len(tags); i++ {
+		tags[i] += fmt.Sprintf(";q=0.%d", max(10-i, 1))
+	}
+
+	return strings.Join(tags, ", ")
+}
+
+// LocaleOverrideHeader is the request header that forces the language of a request, see OverrideLocale.
+const LocaleOverrideHeader = "X-Locale-Override"
+
+// OverrideLocale resolves the language with the X-Locale-Override header when allowed returns true for the request,
+// e.g. for staff that reviews a page in every language without changing their settings. Put it first in the chain of the LocaleMiddleware:
+//
+//	messages.LocaleMiddleware(mux,
+//		messages.OverrideLocale(func(r *http.Request) bool { return isStaff(r.Context()) }),
+//		messages.UserLocale(...),
+//	)
+//
+// The header is ignored for requests that are not allowed.
+func OverrideLocale(allowed func(r *http.Request) bool) LocaleResolver {
+	return LocaleResolverFunc(func(r *http.Request) (LanguageID, bool) {
+		lang := r.Header.Get(LocaleOverrideHeader)
+		if lang == "" || !allowed(r) {
+			return LanguageID{}, false
+		}
+
+		return resolvedLanguage(lang)
+	})
+}
+
+// UserLocale resolves the language with the explicit setting of the user, e.g. from the authenticated user in the context.
+func UserLocale(setting func(ctx context.Context) (lang string, ok bool)) LocaleResolver {
+	return LocaleResolverFunc(func(r *http.Request) (LanguageID, bool) {
+		lang, ok := setting(r.Context())
+		if !ok {
+			return LanguageID{}, false
+		}
+
+		return resolvedLanguage(lang)
+	})
+}
+
+// CookieLocale resolves the language with the value of the cookie.
+func CookieLocale(name string) LocaleResolver {
+	return LocaleResolverFunc(func(r *http.Request) (LanguageID, bool) {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return LanguageID{}, false
+		}
+
+		return resolvedLanguage(cookie.Value)
+	})
+}
+
+// AcceptLanguageLocale resolves the languages with the Accept-Language header in the order of their quality.
+// The Translator uses the first language it has messages for, e.g. the second choice of the user before the default language.
+func AcceptLanguageLocale() MultiLocaleResolver {
+	return acceptLanguageResolver{}
+}
+
+// acceptLanguageResolver is the MultiLocaleResolver of AcceptLanguageLocale.
+type acceptLanguageResolver struct{}
+
+func (acceptLanguageResolver) Resolve(r *http.Request) (LanguageID, bool) {
+	langs, ok := acceptLanguageResolver{}.ResolveAll(r)
+	if !ok {
+		return LanguageID{}, false
+	}
+
+	return langs[0], true
+}
+
+func (acceptLanguageResolver) ResolveAll(r *http.Request) ([]LanguageID, bool) {
+	langs := parseAcceptLanguage(r.Header.Get("Accept-Language"))
+	return langs, len(langs) > 0
+}
+
+// parseAcceptLanguage returns the languages of an Accept-Language header in the order of their quality.
+func parseAcceptLanguage(header string) []LanguageID {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+
+	var langs []LanguageID
+	for _, tag := range tags {
+		// The wildcard is parsed as "mul", it and tags that can not be parsed are skipped.
+		if base, _ := tag.Base(); base.String() == "mul" {
+			continue
+		}
+
+		if lang, ok := resolvedLanguage(tag.String()); ok && !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+
+	return langs
+}
+
+// GeoIPLocale resolves the language with the address of the client, lookup returns the language of the country of the address.
+// The address is the RemoteAddr of the request, put the middleware of your proxy that sets the RemoteAddr before the LocaleMiddleware.
+func GeoIPLocale(lookup func(addr netip.Addr) (LanguageID, bool)) LocaleResolver {
+	return LocaleResolverFunc(func(r *http.Request) (LanguageID, bool) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+
+		addr, err := netip.ParseAddr(host)
+		if err != nil {
+			return LanguageID{}, false
+		}
+
+		return lookup(addr.Unmap())
+	})
+}
+
+// localeCacheSize is the maximum number of languages that CachedLocale caches.
+const localeCacheSize = 10000
+
+// CachedLocale caches the languages of the resolver for the ttl by the key of the request, e.g. the ID of the user.
+// Requests without a key are not cached. The cached resolver is a MultiLocaleResolver when the resolver is one,
+// so all acceptable languages of e.g. AcceptLanguageLocale are cached.
+func CachedLocale(resolver LocaleResolver, key func(r *http.Request) (string, bool), ttl time.Duration) LocaleResolver {
+	c := &cachedLocale{
+		resolver: resolver,
+		key:      key,
+		ttl:      ttl,
+		cache:    make(map[string]cachedLanguages),
+	}
+
+	if _, ok := resolver.(MultiLocaleResolver); ok {
+		return cachedMultiLocale{c}
+	}
+
+	return c
+}
+
+// cachedLocale is the LocaleResolver of CachedLocale.
+type cachedLocale struct {
+	resolver LocaleResolver
+	key      func(r *http.Request) (string, bool)
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedLanguages
+}
+
+// cachedLanguages are the cached languages of a key, ok is false if the resolver did not resolve the languages.
+type cachedLanguages struct {
+	langs   []LanguageID
+	ok      bool
+	expires time.Time
+}
+
+// cachedMultiLocale is the MultiLocaleResolver of CachedLocale.
+type cachedMultiLocale struct {
+	*cachedLocale
+}
+
+var _ MultiLocaleResolver = cachedMultiLocale{}
+
+func (c *cachedLocale) Resolve(r *http.Request) (LanguageID, bool) {
+	langs, ok := c.resolveAll(r)
+	if !ok || len(langs) == 0 {
+		return LanguageID{}, false
+	}
+
+	return langs[0], true
+}
+
+func (c cachedMultiLocale) ResolveAll(r *http.Request) ([]LanguageID, bool) {
+	return c.resolveAll(r)
+}
+
+// resolveAll returns the cached languages of the request, the languages are resolved when they are not cached or have expired.
+func (c *cachedLocale) resolveAll(r *http.Request) ([]LanguageID, bool) {
+	k, ok := c.key(r)
+	if !ok {
+		return c.resolve(r)
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	cached, found := c.cache[k]
+	c.mu.Unlock()
+
+	if found && now.Before(cached.expires) {
+		return cached.langs, cached.ok
+	}
+
+	langs, ok := c.resolve(r)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Remove the expired entries when the cache grows, so it does not grow without bounds.
+	if len(c.cache) >= localeCacheSize {
+		for k, cached := range c.cache {
+			if !now.Before(cached.expires) {
+				delete(c.cache, k)
+			}
+		}
+	}
+
+	if len(c.cache) < localeCacheSize {
+		c.cache[k] = cachedLanguages{langs: langs, ok: ok, expires: now.Add(c.ttl)}
+	}
+
+	return langs, ok
+}
+
+// resolve resolves the languages with the resolver, all languages when it is a MultiLocaleResolver.
+func (c *cachedLocale) resolve(r *http.Request) ([]LanguageID, bool) {
+	if multi, ok := c.resolver.(MultiLocaleResolver); ok {
+		return multi.ResolveAll(r)
+	}
+
+	lang, ok := c.resolver.Resolve(r)
+	if !ok {
+		return nil, false
+	}
+
+	return []LanguageID{lang}, true
+}
+
+// resolvedLanguage parses the language, ok is false if the language can not be parsed.
+func resolvedLanguage(lang string) (LanguageID, bool) {
+	id, err := ParseLanguage(lang)
+	if err != nil {
+		return LanguageID{}, false
+	}
+
+	return id, true
+}
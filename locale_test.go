@@ -0,0 +1,187 @@
+package messages
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type userLangKey struct{}
+
+func TestLocaleMiddleware(t *testing.T) {
+	var lang LanguageID
+	handler := LocaleMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang = FromCtx(r.Context())
+	}),
+		UserLocale(func(ctx context.Context) (string, bool) {
+			lang, ok := ctx.Value(userLangKey{}).(string)
+			return lang, ok
+		}),
+		CookieLocale("lang"),
+		AcceptLanguageLocale(),
+		GeoIPLocale(func(addr netip.Addr) (LanguageID, bool) {
+			if addr == netip.MustParseAddr("192.0.2.1") {
+				return LanguageID{Language: "nl", Region: "BE"}, true
+			}
+			return LanguageID{}, false
+		}),
+	)
+
+	serve := func(r *http.Request) string {
+		lang = LanguageID{}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		return lang.String()
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	r.Header.Set("Accept-Language", "fr-CH, fr;q=0.9, en;q=0.8")
+	r.AddCookie(&http.Cookie{Name: "lang", Value: "de"})
+	require.Equal(t, "es", serve(r.WithContext(context.WithValue(r.Context(), userLangKey{}, "es"))))
+
+	// The chain continues when a resolver can not resolve the language.
+	require.Equal(t, "de", serve(r.WithContext(context.WithValue(r.Context(), userLangKey{}, "invalid!"))))
+
+	r.Header.Del("Cookie")
+	require.Equal(t, "fr-CH", serve(r))
+
+	r.Header.Set("Accept-Language", "en;q=0.1, nl-NL")
+	require.Equal(t, "nl-NL", serve(r))
+
+	r.Header.Del("Accept-Language")
+	require.Equal(t, "nl-BE", serve(r))
+
+	r.RemoteAddr = "198.51.100.1:1234"
+	require.Equal(t, "", serve(r))
+}
+
+func TestAcceptLanguageLocaleAll(t *testing.T) {
+	var langs []LanguageID
+	handler := LocaleMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		langs = LanguagesFromCtx(r.Context())
+	}), AcceptLanguageLocale())
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "en;q=0.5, fr-CH, *;q=0.1, fr;q=0.9")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	require.Equal(t, []LanguageID{{Language: "fr", Region: "CH"}, {Language: "fr"}, {Language: "en"}}, langs)
+
+	lang, ok := AcceptLanguageLocale().Resolve(r)
+	require.True(t, ok)
+	require.Equal(t, LanguageID{Language: "fr", Region: "CH"}, lang)
+
+	r.Header.Set("Accept-Language", "*")
+	_, ok = AcceptLanguageLocale().ResolveAll(r)
+	require.False(t, ok)
+}
+
+func TestOverrideLocale(t *testing.T) {
+	var lang LanguageID
+	handler := LocaleMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang = FromCtx(r.Context())
+	}),
+		OverrideLocale(func(r *http.Request) bool {
+			return r.Header.Get("X-Role") == "staff"
+		}),
+		AcceptLanguageLocale(),
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "nl")
+	r.Header.Set(LocaleOverrideHeader, "ja-JP")
+
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	require.Equal(t, "nl", lang.String())
+
+	r.Header.Set("X-Role", "staff")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	require.Equal(t, "ja-JP", lang.String())
+}
+
+func TestCachedLocale(t *testing.T) {
+	calls := 0
+	resolver := CachedLocale(LocaleResolverFunc(func(r *http.Request) (LanguageID, bool) {
+		calls++
+		return LanguageID{Language: "nl"}, true
+	}), func(r *http.Request) (string, bool) {
+		user := r.Header.Get("X-User")
+		return user, user != ""
+	}, time.Hour)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-User", "1")
+
+	for i := 0; i < 3; i++ {
+		lang, ok := resolver.Resolve(r)
+		require.True(t, ok)
+		require.Equal(t, "nl", lang.String())
+	}
+	require.Equal(t, 1, calls)
+
+	// Requests without a key are not cached.
+	r.Header.Del("X-User")
+	resolver.Resolve(r)
+	resolver.Resolve(r)
+	require.Equal(t, 3, calls)
+
+	// All acceptable languages of a MultiLocaleResolver are cached.
+	multi, ok := CachedLocale(AcceptLanguageLocale(), func(r *http.Request) (string, bool) {
+		return r.Header.Get("X-User"), true
+	}, time.Hour).(MultiLocaleResolver)
+	require.True(t, ok)
+
+	r.Header.Set("X-User", "2")
+	r.Header.Set("Accept-Language", "fr-CH, nl;q=0.9")
+	langs, ok := multi.ResolveAll(r)
+	require.True(t, ok)
+	require.Equal(t, []LanguageID{{Language: "fr", Region: "CH"}, {Language: "nl"}}, langs)
+
+	r.Header.Set("Accept-Language", "de")
+	langs, _ = multi.ResolveAll(r)
+	require.Equal(t, []LanguageID{{Language: "fr", Region: "CH"}, {Language: "nl"}}, langs)
+}
+
+func TestLocaleTransport(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Accept-Language")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: LocaleTransport(nil)}
+	get := func(ctx context.Context, header string) string {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set("Accept-Language", header)
+		}
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		// The request of the caller is not changed.
+		require.Equal(t, header, req.Header.Get("Accept-Language"))
+
+		return got
+	}
+
+	nlBE, err := WithLanguage(context.Background(), "nl-BE")
+	require.NoError(t, err)
+	en, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	require.Equal(t, "nl-BE, nl;q=0.9", get(nlBE, ""))
+
+	multiple, err := WithLanguages(context.Background(), "nl-BE", "fr-BE", "en")
+	require.NoError(t, err)
+	require.Equal(t, "nl-BE, nl;q=0.9, fr-BE;q=0.8, fr;q=0.7, en;q=0.6", get(multiple, ""))
+	require.Equal(t, "en", get(en, ""))
+	require.Equal(t, "", get(context.Background(), ""))
+	require.Equal(t, "de", get(nlBE, "de"))
+}
@@ -0,0 +1,64 @@
+package messages
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+var memoKey = ctxKey("memo")
+
+// memoSize is the maximum number of translations that are remembered per context.
+const memoSize = 1024
+
+// memo remembers the translations of a single request. It is safe for concurrent use.
+type memo struct {
+	mu           sync.Mutex
+	translations map[memoEntry]string
+}
+
+// memoEntry identifies a translation without replacements.
+type memoEntry struct {
+	translator *Translator
+	lang       LanguageID
+	key        Key
+}
+
+// WithMemo attaches a memo to the ctx. Translate calls without replacements that use the ctx are remembered,
+// so repeated calls for the same key, like labels in a template, are only formatted once.
+//
+// The memo should only live as long as a single request. Replacement providers and key rewriters
+// are expected to return the same result for every call with the ctx.
+func WithMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memoKey, &memo{translations: make(map[memoEntry]string)})
+}
+
+// MemoMiddleware attaches a memo to the context of every request, see WithMemo.
+func MemoMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithMemo(r.Context())))
+	})
+}
+
+func memoFromCtx(ctx context.Context) *memo {
+	m, _ := ctx.Value(memoKey).(*memo)
+	return m
+}
+
+func (m *memo) get(entry memoEntry) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out, ok := m.translations[entry]
+	return out, ok
+}
+
+// set remembers the translation, nothing is remembered when the memo is full.
+func (m *memo) set(entry memoEntry, out string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.translations) < memoSize {
+		m.translations[entry] = out
+	}
+}
@@ -0,0 +1,78 @@
+package messages
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemo(t *testing.T) {
+	var formatted atomic.Int32
+
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/valid", WithPostProcess(func(_ LanguageID, _ Key, out string) string {
+		formatted.Add(1)
+		return out
+	}))
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "en_US")
+	require.NoError(t, err)
+
+	// Without a memo every call is formatted.
+	tr.Translate(ctx, "convert.case", nil)
+	tr.Translate(ctx, "convert.case", nil)
+	require.Equal(t, int32(2), formatted.Load())
+
+	formatted.Store(0)
+	ctx = WithMemo(ctx)
+	require.Equal(t, "Total: ", tr.Translate(ctx, "convert.case", nil))
+
+	// The remembered translation is used by concurrent calls.
+	var wg sync.WaitGroup
+	out := make([]string, 10)
+	for i := range out {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i] = tr.Translate(ctx, "convert.case", nil)
+		}()
+	}
+	wg.Wait()
+
+	for _, translated := range out {
+		require.Equal(t, "Total: ", translated)
+	}
+	require.Equal(t, int32(1), formatted.Load())
+
+	// Calls with replacements are not remembered.
+	formatted.Store(0)
+	require.Equal(t, "Total: 1", tr.Translate(ctx, "convert.case", map[string]any{"total": 1}))
+	require.Equal(t, "Total: 2", tr.Translate(ctx, "convert.case", map[string]any{"total": 2}))
+	require.Equal(t, int32(2), formatted.Load())
+
+	// The language is part of the memo.
+	nlCtx := ToCtx(ctx, "nl")
+	require.Equal(t, "Welkom ", tr.Translate(nlCtx, "welcome.login", nil))
+	require.Equal(t, "Welcome ", tr.Translate(ctx, "welcome.login", nil))
+}
+
+func TestMemoMiddleware(t *testing.T) {
+	var memos []*memo
+
+	handler := MemoMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		memos = append(memos, memoFromCtx(r.Context()))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Len(t, memos, 2)
+	require.NotNil(t, memos[0])
+	require.NotSame(t, memos[0], memos[1])
+}
@@ -0,0 +1,108 @@
+package messages
+
+import (
+	"unsafe"
+)
+
+// MemStats describes the memory that is used by the catalogs of a Translator.
+type MemStats struct {
+	// Languages is the number of loaded languages.
+	Languages int
+	// Messages is the number of messages in all languages, region overrides included.
+	Messages int
+	// Strings is the number of distinct strings in the catalogs and StringBytes is their total size.
+	// Strings that are shared between languages, like keys and placeholder names, are counted once.
+	Strings     int
+	StringBytes int
+	// Bytes is an estimate of the memory that is used by the catalogs, strings included.
+	// The overhead of the maps is not exact, so use it to compare catalogs rather than as an absolute number.
+	Bytes int
+}
+
+// MemStats returns the memory statistics of the loaded catalogs.
+func (t *Translator) MemStats() MemStats {
+	var stats MemStats
+
+	// Strings are identified by their data pointer and length, so interned strings are counted once.
+	type stringID struct {
+		data *byte
+		len  int
+	}
+	seen := make(map[stringID]bool)
+
+	addString := func(s string) {
+		id := stringID{data: unsafe.StringData(s), len: len(s)}
+		if len(s) == 0 || seen[id] {
+			return
+		}
+
+		seen[id] = true
+		stats.Strings++
+		stats.StringBytes += len(s)
+	}
+
+	var addMessage func(msg message)
+	addMessage = func(msg message) {
+		addString(msg.message)
+		stats.Bytes += len(msg.replacements) * int(unsafe.Sizeof(replacement{}))
+
+		for _, r := range msg.replacements {
+			addString(r.name)
+			addString(r.replacementKey)
+			addString(r.modifier)
+			addString(r.arg)
+			addString(r.suffix)
+		}
+
+		if msg.condition != nil {
+			stats.Bytes += int(unsafe.Sizeof(condition{}))
+			addString(msg.condition.name)
+			addString(msg.condition.value)
+			addMessage(msg.condition.then)
+			addMessage(msg.condition.otherwise)
+		}
+	}
+
+	const (
+		keySize     = int(unsafe.Sizeof(Key("")))
+		messageSize = int(unsafe.Sizeof(message{}))
+	)
+
+	c := t.current.Load()
+	if c == nil {
+		return stats
+	}
+
+	for _, m := range c.languages {
+		stats.Languages++
+		stats.Bytes += int(unsafe.Sizeof(*m))
+
+		stats.Messages += len(m.messages)
+		stats.Bytes += cap(m.messages)*messageSize + len(m.index)*(keySize+4)
+		for key := range m.index {
+			addString(string(key))
+		}
+		for _, msg := range m.messages {
+			addMessage(msg)
+		}
+
+		for _, regionMessages := range m.regions {
+			stats.Messages += len(regionMessages)
+			stats.Bytes += len(regionMessages) * (keySize + messageSize)
+			for key, msg := range regionMessages {
+				addString(string(key))
+				addMessage(msg)
+			}
+		}
+
+		stats.Bytes += len(m.attributes) * 2 * keySize
+		for name, value := range m.attributes {
+			addString(name)
+			addString(value)
+		}
+	}
+
+	stats.Bytes += stats.StringBytes
+
+	return stats
+}
@@ -0,0 +1,26 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemStats(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome :User", "color": "color", "color@GB": "colour", "attributes": {"first_name": "first name"}}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom :User", "color": "kleur"}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+
+	stats := tr.MemStats()
+	require.Equal(t, 2, stats.Languages)
+	require.Equal(t, 5, stats.Messages)
+
+	// The keys "welcome" and "color", and the placeholder ":User" and its name "user", are shared between the languages.
+	// Distinct strings: welcome, color, Welcome :User, :User, user, colour, first_name, first name, Welkom :User, kleur.
+	require.Equal(t, 10, stats.Strings)
+	require.Greater(t, stats.Bytes, stats.StringBytes)
+}
@@ -0,0 +1,204 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wvell/messages/internal/sorted"
+)
+
+var (
+	// ErrMergeConflict is returned by Merge when a key has a different value in both catalogs and the strategy can not resolve it.
+	ErrMergeConflict = fmt.Errorf("merge conflict")
+)
+
+// Conflict is a key that has a different value in both catalogs of a merge.
+type Conflict struct {
+	Key string
+	// Attribute is true if the key is an attribute instead of a message.
+	Attribute bool
+	// Ours is the value in the destination catalog.
+	Ours string
+	// Theirs is the value in the source catalog.
+	Theirs string
+	// Base is the value in the common ancestor of a three-way merge, see Merge3. It is empty for Merge.
+	Base string
+	// OursDeleted and TheirsDeleted are true if the key is deleted in the catalog of a three-way merge and modified in the other.
+	OursDeleted   bool
+	TheirsDeleted bool
+}
+
+// MergeStrategy resolves a conflict, it returns the merged value or an error if the conflict can not be resolved.
+type MergeStrategy func(c Conflict) (string, error)
+
+// MergeTheirs resolves conflicts with the value of the source catalog.
+func MergeTheirs() MergeStrategy {
+	return func(c Conflict) (string, error) {
+		return c.Theirs, nil
+	}
+}
+
+// MergeOurs resolves conflicts with the value of the destination catalog.
+func MergeOurs() MergeStrategy {
+	return func(c Conflict) (string, error) {
+		return c.Ours, nil
+	}
+}
+
+// MergeErrorOnConflict does not resolve conflicts, Merge returns ErrMergeConflict when the catalogs have a conflict.
+func MergeErrorOnConflict() MergeStrategy {
+	return func(c Conflict) (string, error) {
+		return "", ErrMergeConflict
+	}
+}
+
+// MergeNewest resolves conflicts with the value that was updated last according to the metadata of the catalogs, see KeyMetadata.Updated.
+// Conflicts of attributes and of keys with the same or no update time can not be resolved.
+func MergeNewest(ours, theirs Metadata) MergeStrategy {
+	return func(c Conflict) (string, error) {
+		if c.Attribute {
+			return "", ErrMergeConflict
+		}
+
+		oursUpdated, theirsUpdated := ours[c.Key].Updated, theirs[c.Key].Updated
+		switch {
+		case oursUpdated.After(theirsUpdated):
+			return c.Ours, nil
+		case theirsUpdated.After(oursUpdated):
+			return c.Theirs, nil
+		}
+
+		return "", ErrMergeConflict
+	}
+}
+
+// Merge merges the messages and attributes of src into dst. Keys that only exist in src are added,
+// keys with a different value in both catalogs are resolved with the strategy.
+// Dst is not changed when a conflict can not be resolved, the error lists all conflicts that can not be resolved.
+func Merge(dst, src *RawMessages, strategy MergeStrategy) error {
+	if dst.Messages == nil {
+		dst.Messages = make(map[string]string, len(src.Messages))
+	}
+	if dst.Attributes == nil {
+		dst.Attributes = make(map[string]string, len(src.Attributes))
+	}
+
+	messages, errMessages := mergeValues(dst.Messages, src.Messages, false, strategy)
+	attributes, errAttributes := mergeValues(dst.Attributes, src.Attributes, true, strategy)
+	if err := errors.Join(errMessages, errAttributes); err != nil {
+		return err
+	}
+
+	for key, value := range messages {
+		dst.Messages[key] = value
+	}
+	for name, value := range attributes {
+		dst.Attributes[name] = value
+	}
+
+	return nil
+}
+
+// mergeValues returns the values of src that are added or changed in dst.
+func mergeValues(dst, src map[string]string, attribute bool, strategy MergeStrategy) (map[string]string, error) {
+	merged := make(map[string]string)
+
+	var conflicts []string
+	for _, key := range sorted.Keys(src) {
+		theirs := src[key]
+
+		ours, ok := dst[key]
+		if !ok {
+			merged[key] = theirs
+			continue
+		}
+		if ours == theirs {
+			continue
+		}
+
+		value, err := strategy(Conflict{Key: key, Attribute: attribute, Ours: ours, Theirs: theirs})
+		if errors.Is(err, ErrMergeConflict) {
+			conflicts = append(conflicts, fmt.Sprintf("%q", key))
+			continue
+		}
+		if err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("%q (%v)", key, err))
+			continue
+		}
+
+		merged[key] = value
+	}
+
+	if len(conflicts) > 0 {
+		kind := "messages"
+		if attribute {
+			kind = "attributes"
+		}
+
+		return nil, fmt.Errorf("%w: %s %s", ErrMergeConflict, kind, strings.Join(conflicts, ", "))
+	}
+
+	return merged, nil
+}
+
+// Merge3 merges the changes of ours and theirs since their common ancestor base, e.g. the versions of a translation file
+// of two git branches. A key that is changed in only one of the catalogs gets the change, a key that is changed in both
+// catalogs is only a conflict when the changes differ. The merged catalog keeps the value of ours for the conflicts.
+func Merge3(base, ours, theirs *RawMessages) (*RawMessages, []Conflict) {
+	if base == nil {
+		base = &RawMessages{}
+	}
+
+	merged := &RawMessages{}
+
+	var conflicts []Conflict
+	merged.Messages, conflicts = merge3Values(base.Messages, ours.Messages, theirs.Messages, false, conflicts)
+	merged.Attributes, conflicts = merge3Values(base.Attributes, ours.Attributes, theirs.Attributes, true, conflicts)
+
+	return merged, conflicts
+}
+
+// merge3Values merges the values of ours and theirs since base, the conflicts are appended to conflicts.
+func merge3Values(base, ours, theirs map[string]string, attribute bool, conflicts []Conflict) (map[string]string, []Conflict) {
+	keys := make(map[string]bool, len(ours)+len(theirs))
+	for key := range ours {
+		keys[key] = true
+	}
+	for key := range theirs {
+		keys[key] = true
+	}
+
+	merged := make(map[string]string, len(keys))
+	for _, key := range sorted.Keys(keys) {
+		baseValue, inBase := base[key]
+		oursValue, inOurs := ours[key]
+		theirsValue, inTheirs := theirs[key]
+
+		switch {
+		case inOurs == inTheirs && oursValue == theirsValue:
+			// Both sides made the same change, or neither side changed the key.
+		case inOurs == inBase && oursValue == baseValue:
+			// Only theirs changed the key.
+			oursValue, inOurs = theirsValue, inTheirs
+		case inTheirs == inBase && theirsValue == baseValue:
+			// Only ours changed the key.
+		default:
+			conflicts = append(conflicts, Conflict{
+				Key:           key,
+				Attribute:     attribute,
+				Ours:          oursValue,
+				Theirs:        theirsValue,
+				Base:          baseValue,
+				OursDeleted:   !inOurs,
+				TheirsDeleted: !inTheirs,
+			})
+		}
+
+		if inOurs {
+			merged[key] = oursValue
+		}
+	}
+
+	return merged, conflicts
+}
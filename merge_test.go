@@ -0,0 +1,117 @@
+package messages
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	catalogs := func() (*RawMessages, *RawMessages) {
+		dst := &RawMessages{
+			Messages:   map[string]string{"welcome": "Welcome", "bye": "Bye", "color": "Color"},
+			Attributes: map[string]string{"email": "email"},
+		}
+		src := &RawMessages{
+			Messages:   map[string]string{"welcome": "Welcome!", "color": "Color", "cart": "Cart"},
+			Attributes: map[string]string{"email": "email address", "name": "name"},
+		}
+
+		return dst, src
+	}
+
+	dst, src := catalogs()
+	require.NoError(t, Merge(dst, src, MergeTheirs()))
+	require.Equal(t, map[string]string{"welcome": "Welcome!", "bye": "Bye", "color": "Color", "cart": "Cart"}, dst.Messages)
+	require.Equal(t, map[string]string{"email": "email address", "name": "name"}, dst.Attributes)
+
+	dst, src = catalogs()
+	require.NoError(t, Merge(dst, src, MergeOurs()))
+	require.Equal(t, map[string]string{"welcome": "Welcome", "bye": "Bye", "color": "Color", "cart": "Cart"}, dst.Messages)
+	require.Equal(t, map[string]string{"email": "email", "name": "name"}, dst.Attributes)
+
+	// Dst is not changed when there are conflicts.
+	dst, src = catalogs()
+	err := Merge(dst, src, MergeErrorOnConflict())
+	require.ErrorIs(t, err, ErrMergeConflict)
+	require.ErrorContains(t, err, `messages "welcome"`)
+	require.ErrorContains(t, err, `attributes "email"`)
+	require.NotContains(t, dst.Messages, "cart")
+
+	// Keys that are added or the same in both catalogs are no conflict.
+	dst, src = catalogs()
+	delete(src.Messages, "welcome")
+	delete(src.Attributes, "email")
+	require.NoError(t, Merge(dst, src, MergeErrorOnConflict()))
+	require.Equal(t, "Cart", dst.Messages["cart"])
+
+	// A nil destination map is created.
+	dst = &RawMessages{}
+	_, src = catalogs()
+	require.NoError(t, Merge(dst, src, MergeErrorOnConflict()))
+	require.Equal(t, src.Messages, dst.Messages)
+
+	// Errors of custom strategies are returned.
+	dst, src = catalogs()
+	err = Merge(dst, src, func(c Conflict) (string, error) {
+		return "", errors.New("ask a translator")
+	})
+	require.ErrorIs(t, err, ErrMergeConflict)
+	require.ErrorContains(t, err, "ask a translator")
+}
+
+func TestMergeNewest(t *testing.T) {
+	now := time.Now()
+	ours := Metadata{"welcome": {Updated: now}, "bye": {Updated: now.Add(-time.Hour)}}
+	theirs := Metadata{"welcome": {Updated: now.Add(-time.Hour)}, "bye": {Updated: now}}
+
+	dst := &RawMessages{Messages: map[string]string{"welcome": "Welcome", "bye": "Bye"}}
+	src := &RawMessages{Messages: map[string]string{"welcome": "Hello", "bye": "Goodbye"}}
+	require.NoError(t, Merge(dst, src, MergeNewest(ours, theirs)))
+	require.Equal(t, map[string]string{"welcome": "Welcome", "bye": "Goodbye"}, dst.Messages)
+
+	// Keys without update time can not be resolved.
+	dst = &RawMessages{Messages: map[string]string{"color": "Color"}}
+	src = &RawMessages{Messages: map[string]string{"color": "Colour"}}
+	require.ErrorIs(t, Merge(dst, src, MergeNewest(ours, theirs)), ErrMergeConflict)
+}
+
+func TestMerge3(t *testing.T) {
+	base := &RawMessages{
+		Messages:   map[string]string{"welcome": "Welcome", "bye": "Bye", "color": "Color", "cart": "Cart", "save": "Save"},
+		Attributes: map[string]string{"email": "email"},
+	}
+	ours := &RawMessages{
+		Messages:   map[string]string{"welcome": "Welcome!", "bye": "Bye", "color": "Colour", "cart": "Basket", "new.ours": "Ours"},
+		Attributes: map[string]string{"email": "email address"},
+	}
+	theirs := &RawMessages{
+		Messages:   map[string]string{"welcome": "Welcome", "color": "Colour", "cart": "Shopping cart", "new.theirs": "Theirs"},
+		Attributes: map[string]string{"email": "email", "name": "name"},
+	}
+
+	merged, conflicts := Merge3(base, ours, theirs)
+	require.Equal(t, map[string]string{
+		"welcome":    "Welcome!", // Only changed by ours.
+		"color":      "Colour",   // The same change on both sides.
+		"cart":       "Basket",   // A conflict keeps ours.
+		"new.ours":   "Ours",
+		"new.theirs": "Theirs",
+	}, merged.Messages)
+	require.Equal(t, map[string]string{"email": "email address", "name": "name"}, merged.Attributes)
+	require.Equal(t, []Conflict{{Key: "cart", Ours: "Basket", Theirs: "Shopping cart", Base: "Cart"}}, conflicts)
+
+	// A key that is deleted on one side and modified on the other is a conflict.
+	theirs.Messages["save"] = "Save changes"
+	_, conflicts = Merge3(base, ours, theirs)
+	require.Equal(t, []Conflict{
+		{Key: "cart", Ours: "Basket", Theirs: "Shopping cart", Base: "Cart"},
+		{Key: "save", Theirs: "Save changes", Base: "Save", OursDeleted: true},
+	}, conflicts)
+
+	// Without a common ancestor, keys that are added with different values are conflicts.
+	_, conflicts = Merge3(nil, &RawMessages{Messages: map[string]string{"a": "A"}}, &RawMessages{Messages: map[string]string{"a": "B"}})
+	require.Equal(t, []Conflict{{Key: "a", Ours: "A", Theirs: "B"}}, conflicts)
+}
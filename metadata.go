@@ -0,0 +1,160 @@
+package messages
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// MetadataFile is the name of the file in the translations directory that holds the metadata of the keys.
+// The metadata is the same for all languages:
+//
+//	{
+//		"welcome.login": {
+//			"description": "Greeting on the dashboard after the user logged in.",
+//			"samples": {"user": "jan"}
+//		}
+//	}
+const MetadataFile = "metadata.json"
+
+// Metadata holds the metadata of the keys, keyed by translation key.
+type Metadata map[string]KeyMetadata
+
+// KeyMetadata describes a translation key for translators and tooling.
+type KeyMetadata struct {
+	// Description tells the translator where and how the message is used.
+	Description string `json:"description,omitempty"`
+	// Samples are example replacement values that are used to render the message in golden files and previews.
+	Samples map[string]any `json:"samples,omitempty"`
+	// Placeholders holds the expected type of the replacements by placeholder name, e.g. {"count": "int"}, see PlaceholderTypes.
+	Placeholders PlaceholderTypes `json:"placeholders,omitempty"`
+	// MaxLength is the maximum number of characters of a translation, e.g. for a button label. Zero is no limit.
+	MaxLength int `json:"max_length,omitempty"`
+	// Code is the stable error code of the message, e.g. "E1234", see Translator.ByCode.
+	Code string `json:"code,omitempty"`
+	// ValidFrom and ValidUntil limit the time a variant of a key is used, e.g. a holiday banner, see Translator.ExpiredKeys.
+	ValidFrom  time.Time `json:"valid_from,omitempty"`
+	ValidUntil time.Time `json:"valid_until,omitempty"`
+	// Updated is the time the message was last changed, it is used to merge catalogs, see MergeNewest.
+	Updated time.Time `json:"updated,omitempty"`
+}
+
+// MetadataFromDir reads the metadata file from the translations directory.
+// An empty Metadata is returned when the directory has no metadata file.
+func (p *Parser) MetadataFromDir(dir string) (Metadata, error) {
+	data, err := afero.ReadFile(p.fs, filepath.Join(dir, MetadataFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Metadata{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading metadata: %w", err)
+	}
+
+	data, err = stripBOM(data)
+	if err != nil {
+		return nil, fmt.Errorf("file %s: %w", MetadataFile, err)
+	}
+
+	metadata := Metadata{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("decoding metadata: %w", err)
+	}
+
+	for _, keyMetadata := range metadata {
+		for name, sample := range keyMetadata.Samples {
+			keyMetadata.Samples[name] = sampleValue(sample)
+		}
+	}
+
+	if _, err := metadata.codes(); err != nil {
+		return nil, fmt.Errorf("file %s: %w", MetadataFile, err)
+	}
+
+	if err := metadata.validatePlaceholderTypes(); err != nil {
+		return nil, fmt.Errorf("file %s: %w", MetadataFile, err)
+	}
+
+	return metadata, nil
+}
+
+// sampleValue converts JSON numbers to an int64 or float64, so a sample like 3 is formatted as "3" instead of "3.00".
+func sampleValue(value any) any {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+
+	if i, err := number.Int64(); err == nil {
+		return i
+	}
+
+	if f, err := number.Float64(); err == nil {
+		return f
+	}
+
+	return number.String()
+}
+
+// MarshalJSON leaves out the times that are not set, so a written metadata file only has the fields of the key.
+func (m KeyMetadata) MarshalJSON() ([]byte, error) {
+	type keyMetadata KeyMetadata
+
+	return json.Marshal(struct {
+		keyMetadata
+		ValidFrom  *time.Time `json:"valid_from,omitempty"`
+		ValidUntil *time.Time `json:"valid_until,omitempty"`
+		Updated    *time.Time `json:"updated,omitempty"`
+	}{
+		keyMetadata: keyMetadata(m),
+		ValidFrom:   nonZeroTime(m.ValidFrom),
+		ValidUntil:  nonZeroTime(m.ValidUntil),
+		Updated:     nonZeroTime(m.Updated),
+	})
+}
+
+// nonZeroTime returns a pointer to t, or nil if t is the zero time.
+func nonZeroTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+// WithMetadata sets the metadata of the keys for NewTranslatorFromLoader and NewTranslatorFromRaw, it replaces the metadata of
+// a MetadataLoader. NewTranslator reads the metadata file of the directory.
+func WithMetadata(metadata Metadata) Opt {
+	return func(t *Translator) {
+		t.metadata = metadata
+	}
+}
+
+// setMetadata sets the metadata of the catalog, with its error codes and timed variants.
+func (c *catalog) setMetadata(metadata Metadata) error {
+	if metadata == nil {
+		metadata = Metadata{}
+	}
+
+	codes, err := metadata.codes()
+	if err != nil {
+		return fmt.Errorf("metadata: %w", err)
+	}
+
+	if err := metadata.validatePlaceholderTypes(); err != nil {
+		return fmt.Errorf("metadata: %w", err)
+	}
+
+	c.metadata = metadata
+	c.codes = codes
+	c.timedVariants = metadata.timedVariants()
+
+	return nil
+}
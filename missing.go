@@ -0,0 +1,97 @@
+package messages
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+)
+
+// Missing describes a translation that is missing or a placeholder without a replacement, see WithMissingHook.
+type Missing struct {
+	Language LanguageID
+	Key      Key
+	// Placeholder is the placeholder without a replacement, e.g. ":user". It is empty when the translation of the key is missing.
+	Placeholder string
+}
+
+// MissingHook is called for every missing translation, see WithMissingHook.
+type MissingHook func(ctx context.Context, missing Missing)
+
+// WithMissingHook calls the hook when a translated message is missing and the key is returned, e.g. to log or count the missing keys.
+// With WithPlaceholderAudit the hook is also called for placeholders without a replacement.
+func WithMissingHook(hook MissingHook) Opt {
+	return func(t *Translator) {
+		t.missingHook = hook
+	}
+}
+
+// WithPlaceholderAudit calls the missing hook for the placeholders of a message that have no replacement, for a sample of the translations.
+// A placeholder without a replacement is formatted as an empty string, so a placeholder that is renamed in the translation file but not in
+// the code goes unnoticed otherwise. The rate is the fraction of the translations that are audited, e.g. 0.01 for 1%, 1 audits every translation.
+// Replacements that are provided from the context, see WithReplacementProvider, count as given.
+func WithPlaceholderAudit(rate float64) Opt {
+	return func(t *Translator) {
+		t.placeholderAudit = rate
+	}
+}
+
+// reportMissing calls the missing hook when the translation of key is missing, or when the translation is audited and a placeholder
+// has no replacement.
+func (t *Translator) reportMissing(ctx context.Context, messages *messages, region string, key Key, replacements map[string]any) {
+	if t.missingHook == nil {
+		return
+	}
+
+	lang := messages.id(region)
+
+	msg, ok := messages.lookup(key, region)
+	if !ok {
+		if base, _, isCasing := splitCasingDirective(key); isCasing {
+			msg, ok = messages.lookup(base, region)
+		}
+	}
+
+	if !ok {
+		t.missingHook(ctx, Missing{Language: lang, Key: key})
+		return
+	}
+
+	if t.placeholderAudit <= 0 || rand.Float64() >= t.placeholderAudit {
+		return
+	}
+
+	for _, placeholder := range unreplacedPlaceholders(msg, replacements) {
+		t.missingHook(ctx, Missing{Language: lang, Key: key, Placeholder: placeholder})
+	}
+}
+
+// unreplacedPlaceholders returns the placeholders of the message, and of both messages of a condition, that have no replacement.
+func unreplacedPlaceholders(msg message, replacements map[string]any) []string {
+	if msg.condition != nil {
+		placeholders := unreplacedPlaceholders(msg.condition.then, replacements)
+		for _, placeholder := range unreplacedPlaceholders(msg.condition.otherwise, replacements) {
+			if !slices.Contains(placeholders, placeholder) {
+				placeholders = append(placeholders, placeholder)
+			}
+		}
+
+		return placeholders
+	}
+
+	var placeholders []string
+	if msg.icu != nil {
+		for _, arg := range msg.icu.args {
+			if _, ok := replacements[arg]; !ok {
+				placeholders = append(placeholders, "{"+arg+"}")
+			}
+		}
+	}
+
+	for _, r := range msg.replacements {
+		if _, ok := replacements[r.name]; !ok {
+			placeholders = append(placeholders, r.replacementKey)
+		}
+	}
+
+	return placeholders
+}
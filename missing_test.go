@@ -0,0 +1,36 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingHook(t *testing.T) {
+	var missing []Missing
+	hook := func(_ context.Context, m Missing) {
+		missing = append(missing, m)
+	}
+
+	ctx, err := WithLanguage(context.Background(), "en_US")
+	require.NoError(t, err)
+
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/valid", WithMissingHook(hook))
+	require.NoError(t, err)
+
+	tr.Translate(ctx, "welcome.login", nil)
+	tr.Translate(ctx, "welcome.login!upper", map[string]any{"user": "jan"})
+	tr.Translate(ctx, "non.existing", nil)
+	require.Equal(t, []Missing{{Language: LanguageID{Language: "en", Region: "US"}, Key: "non.existing"}}, missing)
+
+	// With the audit the placeholders without a replacement are reported.
+	missing = nil
+	tr, err = NewTranslator(afero.NewOsFs(), "./testdata/valid", WithMissingHook(hook), WithPlaceholderAudit(1))
+	require.NoError(t, err)
+
+	tr.Translate(ctx, "welcome.login", map[string]any{"username": "jan"})
+	tr.Translate(ctx, "welcome.login", map[string]any{"user": "jan"})
+	require.Equal(t, []Missing{{Language: LanguageID{Language: "en", Region: "US"}, Key: "welcome.login", Placeholder: ":User"}}, missing)
+}
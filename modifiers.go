@@ -0,0 +1,200 @@
+package messages
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	textmessage "golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+var (
+	ErrUnknownModifier = fmt.Errorf("unknown modifier")
+)
+
+// Modifier formats a replacement value for the given language.
+// Modifiers are added to a placeholder with a pipe, e.g. ":total|percent".
+// Arg holds the optional argument of the modifier, e.g. "1" for ":total|percent(1)", and is empty otherwise.
+type Modifier func(lang language.Tag, value any, arg string) string
+
+// defaultModifiers returns the modifiers that are available in every Translator.
+func defaultModifiers() map[string]Modifier {
+	return map[string]Modifier{
+		"percent":  percentModifier,
+		"compact":  compactModifier,
+		"duration": durationModifier,
+		"bytes":    bytesModifier,
+		"title":    titleModifier,
+		"phone": func(lang language.Tag, value any, _ string) string {
+			return formatPhoneNumber(lang, PhoneNumber(formatReplacement(value)))
+		},
+		"postal": func(lang language.Tag, value any, _ string) string {
+			return formatPostalCode(lang, PostalCode(formatReplacement(value)))
+		},
+		"date":     dateTimeModifier(func(f dateTimeFormat) string { return f.date }),
+		"time":     dateTimeModifier(func(f dateTimeFormat) string { return f.time }),
+		"datetime": dateTimeModifier(func(f dateTimeFormat) string { return f.date + " " + f.time }),
+		"month":    monthModifier,
+		"weekday":  weekdayModifier,
+		"region":   regionModifier,
+		"truncate": truncateModifier,
+	}
+}
+
+// formatter formats a replacement value of a specific type.
+type formatter func(lang language.Tag, value any) string
+
+// defaultFormatters returns the formatters that are available in every Translator.
+func defaultFormatters() map[reflect.Type]formatter {
+	formatters := make(map[reflect.Type]formatter)
+	addFormatter(formatters, func(lang language.Tag, d time.Duration) string {
+		// Durations are humanized by default, formatReplacement would return an empty string.
+		return durationModifier(lang, d, "")
+	})
+	addFormatter(formatters, formatPhoneNumber)
+	addFormatter(formatters, formatPostalCode)
+	addFormatter(formatters, formatDateTime)
+
+	return formatters
+}
+
+// WithFormatter adds a formatter for replacement values of type T. The formatter is used for placeholders without a modifier.
+// This allows locale aware formatting of domain types:
+//
+//	messages.WithFormatter(func(lang language.Tag, m Money) string {
+//		return m.Format(lang)
+//	})
+//
+// Lang contains the region of the context if it has one, so the formatter can use region specific formats.
+func WithFormatter[T any](fn func(lang language.Tag, value T) string) Opt {
+	return func(t *Translator) {
+		addFormatter(t.formatters, fn)
+	}
+}
+
+func addFormatter[T any](formatters map[reflect.Type]formatter, fn func(lang language.Tag, value T) string) {
+	formatters[reflect.TypeFor[T]()] = func(lang language.Tag, value any) string {
+		return fn(lang, value.(T))
+	}
+}
+
+// WithModifier adds a custom modifier that can be used in messages as ":placeholder|name".
+// A built-in modifier with the same name is replaced.
+func WithModifier(name string, modifier Modifier) Opt {
+	return func(t *Translator) {
+		t.modifiers[name] = modifier
+	}
+}
+
+// percentModifier formats a fraction as a percentage, 0.12 is formatted as 12% in English and 12 % in French.
+// The argument is the maximum number of fraction digits, which defaults to 0.
+func percentModifier(lang language.Tag, value any, arg string) string {
+	f, ok := numericValue(value)
+	if !ok {
+		return formatReplacement(value)
+	}
+
+	return textmessage.NewPrinter(lang).Sprint(number.Percent(f, number.MaxFractionDigits(intArg(arg, 0))))
+}
+
+// compactSuffixes holds the suffixes for thousands, millions, billions and trillions per language.
+// A suffix that starts with a space is separated from the number.
+var compactSuffixes = map[string][4]string{
+	"en": {"K", "M", "B", "T"},
+	"nl": {"K", " mln.", " mld.", " bln."},
+	"de": {" Tsd.", " Mio.", " Mrd.", " Bio."},
+	"fr": {" k", " M", " Md", " Bn"},
+	"es": {" mil", " M", " mil M", " B"},
+}
+
+// compactModifier formats large numbers in a short form, 12543 is formatted as 12.5K in English and 12,5 k in French.
+// The argument is the maximum number of fraction digits, which defaults to 1. Languages without compact suffixes use the English suffixes.
+func compactModifier(lang language.Tag, value any, arg string) string {
+	f, ok := numericValue(value)
+	if !ok {
+		return formatReplacement(value)
+	}
+
+	base, _ := lang.Base()
+	suffixes, ok := compactSuffixes[base.String()]
+	if !ok {
+		suffixes = compactSuffixes["en"]
+	}
+
+	// The index of the suffix, -1 for numbers below a thousand.
+	i := len(suffixes) - 1
+	for i >= 0 && math.Abs(f) < math.Pow(1000, float64(i+1)) {
+		i--
+	}
+
+	// A number that rounds up to a thousand of the unit uses the next suffix, 999950 is 1M instead of 1,000K.
+	digits := intArg(arg, 1)
+	scale := math.Pow(10, float64(digits))
+	if i < len(suffixes)-1 && math.Abs(math.Round(f/math.Pow(1000, float64(i+1))*scale)/scale) >= 1000 {
+		i++
+	}
+
+	suffix := ""
+	if i >= 0 {
+		f /= math.Pow(1000, float64(i+1))
+		suffix = suffixes[i]
+	}
+
+	return textmessage.NewPrinter(lang).Sprint(number.Decimal(f, number.MaxFractionDigits(digits))) + suffix
+}
+
+// titleModifier title cases every word of the value, "new york" is formatted as "New York".
+// The casing rules of the language are used, e.g. "ijsland" is formatted as "IJsland" in Dutch. The rest of the words is left as is.
+func titleModifier(lang language.Tag, value any, _ string) string {
+	return cases.Title(lang, cases.NoLower).String(formatReplacement(value))
+}
+
+// byteUnits holds the decimal byte units per language, languages that are not in the map use the English units.
+var byteUnits = map[string][]string{
+	"en": {"B", "kB", "MB", "GB", "TB", "PB"},
+	"fr": {"o", "ko", "Mo", "Go", "To", "Po"},
+}
+
+// bytesModifier formats a number of bytes, 1200000 is formatted as 1.2 MB in English and 1,2 Mo in French.
+// Decimal units (1 kB is 1000 bytes) are used. The argument "iec" uses binary units (1 KiB is 1024 bytes) instead.
+func bytesModifier(lang language.Tag, value any, arg string) string {
+	f, ok := numericValue(value)
+	if !ok {
+		return formatReplacement(value)
+	}
+
+	base, _ := lang.Base()
+	units, ok := byteUnits[base.String()]
+	if !ok {
+		units = byteUnits["en"]
+	}
+
+	size := 1000.0
+	if arg == "iec" {
+		size = 1024
+		units = []string{units[0], "Ki" + units[0], "Mi" + units[0], "Gi" + units[0], "Ti" + units[0], "Pi" + units[0]}
+	}
+
+	unit := 0
+	for math.Abs(f) >= size && unit < len(units)-1 {
+		f /= size
+		unit++
+	}
+
+	return textmessage.NewPrinter(lang).Sprint(number.Decimal(f, number.MaxFractionDigits(1))) + " " + units[unit]
+}
+
+// intArg parses the modifier argument as an int, def is returned when the argument is empty or invalid.
+func intArg(arg string, def int) int {
+	i, err := strconv.Atoi(arg)
+	if err != nil || i < 0 {
+		return def
+	}
+
+	return i
+}
@@ -0,0 +1,78 @@
+package messages
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func shout(lang language.Tag, value any, arg string) string {
+	return strings.ToUpper(formatReplacement(value)) + "!"
+}
+
+func TestModifiers(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/modifiers", WithModifier("shout", shout))
+	require.NoError(t, err)
+
+	cases := []struct {
+		lang         string
+		key          Key
+		replacements map[string]any
+		expected     string
+	}{
+		{lang: "en", key: "conversion", replacements: map[string]any{"rate": 0.12, "target": 0.125}, expected: "Conversion 12%, target 12.5%"},
+		{lang: "fr", key: "conversion", replacements: map[string]any{"rate": 0.12, "target": 0.125}, expected: "Conversion 12\u00a0%, objectif 12,5\u00a0%"},
+		{lang: "en", key: "visitors", replacements: map[string]any{"count": 12543}, expected: "12.5K visitors (12543 exactly)"},
+		{lang: "en", key: "visitors", replacements: map[string]any{"count": 999}, expected: "999 visitors (999 exactly)"},
+		{lang: "en", key: "visitors", replacements: map[string]any{"count": 2_000_000}, expected: "2M visitors (2000000 exactly)"},
+		{lang: "fr", key: "visitors", replacements: map[string]any{"count": 12543}, expected: "12,5 k visiteurs (12543 exactement)"},
+		// A number that rounds up to a thousand of the unit uses the next suffix.
+		{lang: "en", key: "visitors", replacements: map[string]any{"count": 999_950}, expected: "1M visitors (999950 exactly)"},
+		{lang: "en", key: "visitors", replacements: map[string]any{"count": 999_949}, expected: "999.9K visitors (999949 exactly)"},
+		{lang: "en", key: "visitors", replacements: map[string]any{"count": 999.96}, expected: "1K visitors (999.96 exactly)"},
+		{lang: "fr", key: "visitors", replacements: map[string]any{"count": 999_999_999}, expected: "1 Md visiteurs (999999999 exactement)"},
+		{lang: "en", key: "custom", replacements: map[string]any{"name": "john"}, expected: "Hello JOHN!"},
+		{lang: "en", key: "reminder", replacements: map[string]any{"remaining": 125 * time.Minute}, expected: "Reminder in 2 hours 5 minutes"},
+		{lang: "en", key: "reminder.precise", replacements: map[string]any{"remaining": 7530 * time.Second}, expected: "Reminder in 2 hours 5 minutes 30 seconds"},
+		{lang: "nl", key: "reminder", replacements: map[string]any{"remaining": 125 * time.Minute}, expected: "Herinnering over 2 uur 5 minuten"},
+		{lang: "en", key: "quota", replacements: map[string]any{"used": 1_200_000, "total": 5 << 30}, expected: "You have used 1.2 MB of 5 GiB"},
+		{lang: "en", key: "quota", replacements: map[string]any{"used": 512, "total": 1536}, expected: "You have used 512 B of 1.5 KiB"},
+		{lang: "fr", key: "quota", replacements: map[string]any{"used": 1_200_000, "total": 5 << 30}, expected: "Vous avez utilisé 1,2 Mo sur 5 Gio"},
+		{lang: "en", key: "city", replacements: map[string]any{"city": "new york", "town": "new york"}, expected: "City: New York, town: New york"},
+		{lang: "nl", key: "country", replacements: map[string]any{"country": "ijsland"}, expected: "Land: IJsland"},
+		// A pipe that is not followed by a modifier is text.
+		{lang: "en", key: "choice", replacements: map[string]any{"first": "tea", "second": "coffee"}, expected: "Pick tea|coffee|or"},
+		// A time.Duration is humanized without a modifier.
+		{lang: "en", key: "sla", replacements: map[string]any{"sla": 4 * time.Hour}, expected: "Response within 4 hours"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.lang+"_"+string(c.key), func(t *testing.T) {
+			ctx, err := WithLanguage(context.Background(), c.lang)
+			require.NoError(t, err)
+
+			require.Equal(t, c.expected, tr.Translate(ctx, c.key, c.replacements))
+		})
+	}
+}
+
+func TestUnknownModifier(t *testing.T) {
+	_, err := NewTranslator(afero.NewOsFs(), "./testdata/invalid-modifier")
+	require.ErrorIs(t, err, ErrUnknownModifier)
+}
+
+func TestTitleCaseWords(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/modifiers", WithModifier("shout", shout), WithTitleCaseWords())
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	message := tr.Translate(ctx, "city", map[string]any{"city": "new york", "town": "new york"})
+	require.Equal(t, "City: New York, town: New York", message)
+}
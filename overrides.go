@@ -0,0 +1,98 @@
+package messages
+
+import (
+	"context"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// OverrideSource returns the override of a single message, e.g. to hotfix a message without a deploy.
+// Ok is false if the message has no override. Override is called for every translation, implementations should cache the overrides.
+type OverrideSource interface {
+	Override(ctx context.Context, lang LanguageID, key Key) (value string, ok bool)
+}
+
+// WithOverrides consults the source before the translations. The override is parsed like a message in a translation file,
+// an override that can not be parsed is ignored. Sources are consulted in order, the first override is used.
+func WithOverrides(source OverrideSource) Opt {
+	return func(t *Translator) {
+		t.overrideSources = append(t.overrideSources, source)
+	}
+}
+
+// overrideCacheKey is the key of a compiled override in the cache of the messages.
+type overrideCacheKey struct {
+	lang LanguageID
+	key  Key
+}
+
+// compiledOverride is a compiled override of the value, overridden is nil if the value can not be parsed.
+// It is compiled again when the value changes, the cache is dropped with the messages when they are reloaded.
+type compiledOverride struct {
+	value      string
+	overridden *messages
+}
+
+// override returns the messages with the override of key, the messages are returned as is when the key has no override.
+// The override sources get the key without a casing directive.
+func (t *Translator) override(ctx context.Context, messages *messages, region string, key Key) *messages {
+	if len(t.overrideSources) == 0 {
+		return messages
+	}
+
+	if base, _, ok := splitCasingDirective(key); ok {
+		key = base
+	}
+
+	lang := messages.id(region)
+	for _, source := range t.overrideSources {
+		value, ok := source.Override(ctx, lang, key)
+		if !ok {
+			continue
+		}
+
+		cacheKey := overrideCacheKey{lang: lang, key: key}
+		if cached, ok := messages.overrideCache.Load(cacheKey); ok {
+			if c := cached.(compiledOverride); c.value == value {
+				if c.overridden == nil {
+					continue
+				}
+
+				return c.overridden
+			}
+		}
+
+		overridden := t.compileOverride(messages, key, value)
+		messages.overrideCache.Store(cacheKey, compiledOverride{value: value, overridden: overridden})
+		if overridden == nil {
+			continue
+		}
+
+		return overridden
+	}
+
+	return messages
+}
+
+// compileOverride returns a copy of the messages with the override of key, it returns nil if the override can not be parsed.
+func (t *Translator) compileOverride(messages *messages, key Key, value string) *messages {
+	parser := NewParser(nil, t.parserOpts...)
+	msg, err := parser.parseMessage(string(key), parser.normalizeSpace(norm.NFC.String(value)))
+	if err == nil {
+		err = messages.validateMessageModifiers(key, msg)
+	}
+	if err == nil {
+		msg, err = t.compileTemplate(key, msg)
+	}
+	if err == nil {
+		msg, err = t.compileICU(key, msg)
+	}
+	if err != nil {
+		return nil
+	}
+
+	overridden := *messages
+	overridden.overrides = map[Key]message{key: msg}
+
+	return &overridden
+}
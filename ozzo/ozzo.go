@@ -0,0 +1,132 @@
+// Package ozzo translates the errors of github.com/go-ozzo/ozzo-validation with a messages.Translator.
+//
+// The error code of a rule is translated with the "validation.<rule>" key of messages.WithValidationMessages, e.g. "validation_required"
+// uses "validation.required", "validation_is_email" uses "validation.email" and "validation_match_invalid" uses "validation.match".
+// The field name is the :attribute replacement, so the attributes of the translation files are used for the field names.
+package ozzo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/wvell/messages"
+)
+
+// rules maps the ozzo-validation error codes to the rule of the translation key when it differs from the code.
+var rules = map[string]string{
+	"validation_nil_or_not_empty_required":       "required",
+	"validation_not_nil_required":                "required",
+	"validation_length_too_long":                 "max",
+	"validation_length_too_short":                "min",
+	"validation_length_invalid":                  "len",
+	"validation_length_out_of_range":             "between",
+	"validation_min_greater_equal_than_required": "min",
+	"validation_min_greater_than_required":       "min",
+	"validation_max_less_equal_than_required":    "max",
+	"validation_max_less_than_required":          "max",
+	"validation_is_int":                          "integer",
+	"validation_is_float":                        "numeric",
+	"validation_is_digit":                        "numeric",
+	"validation_is_alphanumeric":                 "alpha_num",
+}
+
+// Key returns the translation key for the ozzo-validation error code, e.g. "validation.required" for "validation_required".
+// The "is_" prefix and "_invalid" suffix of a code are not part of the rule, "validation_match_invalid" uses "validation.match".
+func Key(code string) messages.Key {
+	rule, ok := rules[code]
+	if !ok {
+		rule = strings.TrimPrefix(code, "validation_")
+		rule = strings.TrimPrefix(rule, "is_")
+		rule = strings.TrimSuffix(rule, "_invalid")
+	}
+
+	return messages.Key("validation." + rule)
+}
+
+// Translate translates the validation error of the field.
+// The message of the error is returned when the error is not a validation.Error or the key has no translation.
+func Translate(ctx context.Context, tr *messages.Translator, field string, err error) string {
+	var validationErr validation.Error
+	if !errors.As(err, &validationErr) {
+		return err.Error()
+	}
+
+	key := Key(validationErr.Code())
+	out := tr.Translate(ctx, key, replacements(key, field, validationErr.Params()))
+	if out == string(key) {
+		return err.Error()
+	}
+
+	return out
+}
+
+// TranslateErrors translates the validation.Errors of ValidateStruct or Validate and returns the messages by field.
+// Nested errors, e.g. of a struct field or slice, use dotted field names like "address.street" or "items.0".
+// Nil is returned when err is not a validation.Errors.
+func TranslateErrors(ctx context.Context, tr *messages.Translator, err error) map[string]string {
+	var validationErrs validation.Errors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	out := make(map[string]string)
+	translateErrors(ctx, tr, "", "", validationErrs, out)
+
+	return out
+}
+
+// translateErrors adds the translations of errs to out, prefix is the dotted name of the parent field.
+func translateErrors(ctx context.Context, tr *messages.Translator, prefix, attribute string, errs validation.Errors, out map[string]string) {
+	for name, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		field := name
+		if prefix != "" {
+			field = prefix + "." + name
+		}
+
+		// The errors of slice elements are keyed by index, they use the name of the slice as attribute.
+		fieldAttribute := name
+		if _, numErr := strconv.Atoi(name); numErr == nil && attribute != "" {
+			fieldAttribute = attribute
+		}
+
+		var nested validation.Errors
+		if errors.As(err, &nested) {
+			translateErrors(ctx, tr, field, fieldAttribute, nested, out)
+			continue
+		}
+
+		out[field] = Translate(ctx, tr, fieldAttribute, err)
+	}
+}
+
+// replacements returns the replacements for the key, the threshold of the min and max rules is named after the rule.
+func replacements(key messages.Key, field string, params map[string]any) map[string]any {
+	replacements := make(map[string]any, len(params)+1)
+	for name, value := range params {
+		replacements[name] = value
+	}
+
+	switch key {
+	case "validation.min":
+		if threshold, ok := params["threshold"]; ok {
+			replacements["min"] = threshold
+		}
+	case "validation.max":
+		if threshold, ok := params["threshold"]; ok {
+			replacements["max"] = threshold
+		}
+	case "validation.len":
+		replacements["len"] = params["min"]
+	}
+
+	replacements[messages.AttributeKey] = field
+
+	return replacements
+}
@@ -0,0 +1,73 @@
+package ozzo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/messages"
+)
+
+type address struct {
+	Street string `json:"street"`
+}
+
+type user struct {
+	FirstName string   `json:"first_name"`
+	Email     string   `json:"email"`
+	Age       int      `json:"age"`
+	Address   address  `json:"address"`
+	Tags      []string `json:"tags"`
+}
+
+func (u user) Validate() error {
+	return validation.ValidateStruct(&u,
+		validation.Field(&u.FirstName, validation.Required, validation.Length(2, 20)),
+		validation.Field(&u.Email, is.Email),
+		validation.Field(&u.Age, validation.Min(18)),
+		validation.Field(&u.Address),
+		validation.Field(&u.Tags, validation.Each(validation.Required)),
+	)
+}
+
+func (a address) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.Street, validation.Required),
+	)
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tr, err := messages.NewTranslator(afero.NewOsFs(), "./testdata")
+	require.NoError(t, err)
+
+	ctx, err := messages.WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	err = user{FirstName: "J", Email: "invalid", Age: 12, Tags: []string{"a", ""}}.Validate()
+	require.Equal(t, map[string]string{
+		"first_name":     "First name must be between 2 and 20 characters.",
+		"email":          "Email must be a valid email address.",
+		"age":            "Age must be at least 18.",
+		"address.street": "Street is required.",
+		"tags.1":         "Tags is required.",
+	}, TranslateErrors(ctx, tr, err))
+
+	// The message of the error is used when there is no translation.
+	err = validation.Validate("abc", is.Digit)
+	require.Equal(t, "validation.numeric", string(Key("validation_is_digit")))
+	require.Equal(t, "must contain digits only", Translate(ctx, tr, "code", err))
+
+	require.Nil(t, TranslateErrors(ctx, tr, errors.New("internal")))
+}
+
+func TestKey(t *testing.T) {
+	require.Equal(t, messages.Key("validation.required"), Key("validation_required"))
+	require.Equal(t, messages.Key("validation.email"), Key("validation_is_email"))
+	require.Equal(t, messages.Key("validation.max"), Key("validation_length_too_long"))
+	require.Equal(t, messages.Key("validation.match"), Key("validation_match_invalid"))
+	require.Equal(t, messages.Key("validation.not_in"), Key("validation_not_in_invalid"))
+}
@@ -0,0 +1,197 @@
+package messages
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+var (
+	ErrInvalidPack = fmt.Errorf("invalid language pack")
+)
+
+// PackSuffix is the suffix of a language pack file, e.g. "nl.msgpack.gz".
+const PackSuffix = ".msgpack.gz"
+
+// packName is the name of the file in the gzip header of a pack, after the language.
+const packName = ".msgpack"
+
+// checksumPrefix is the prefix of the checksum in the comment of the gzip header.
+const checksumPrefix = "sha256:"
+
+// Pack is the translations of a single language as one artifact, e.g. to publish on a CDN. A pack is a gzip compressed
+// protobuf Bundle with the language, see proto/messages/v1/catalog.proto. The comment of the gzip header holds the checksum
+// of the bundle, so a corrupt or truncated download is an ErrInvalidPack. Create packs with "msgextractor pack".
+type Pack struct {
+	Language LanguageID
+	Messages *RawMessages
+	Version  Version
+	// Checksum is the sha256 of the bundle in the pack, it is set by MarshalBinary and UnmarshalPack.
+	Checksum string
+}
+
+// MarshalBinary encodes the pack and sets the checksum.
+func (p *Pack) MarshalBinary() ([]byte, error) {
+	bundle := MarshalBundle(map[LanguageID]*RawMessages{p.Language: p.Messages}, p.Version)
+
+	data, checksum, err := compress(p.Language.String()+packName, bundle, p.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Checksum = checksum
+	return data, nil
+}
+
+// UnmarshalPack decodes a language pack and verifies its checksum.
+func UnmarshalPack(data []byte) (*Pack, error) {
+	bundle, name, checksum, err := decompress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(name, packName) {
+		return nil, fmt.Errorf("%w: %s is not a language pack", ErrInvalidPack, name)
+	}
+
+	languages, version, err := UnmarshalBundle(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPack, err)
+	}
+
+	if len(languages) != 1 {
+		return nil, fmt.Errorf("%w: %d languages, expected 1", ErrInvalidPack, len(languages))
+	}
+
+	pack := &Pack{Version: version, Checksum: checksum}
+	for lang, raw := range languages {
+		pack.Language, pack.Messages = lang, raw
+	}
+
+	return pack, nil
+}
+
+// compress compresses the payload of a pack or delta with the checksum of the payload in the comment of the gzip header.
+func compress(name string, payload []byte, version Version) ([]byte, string, error) {
+	sum := sha256.Sum256(payload)
+	checksum := hex.EncodeToString(sum[:])
+
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	zw.Name = name
+	zw.Comment = checksumPrefix + checksum
+	zw.ModTime = version.LastModified
+
+	if _, err := zw.Write(payload); err != nil {
+		return nil, "", fmt.Errorf("compressing %s: %w", name, err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", fmt.Errorf("compressing %s: %w", name, err)
+	}
+
+	return b.Bytes(), checksum, nil
+}
+
+// decompress returns the payload, the name and the checksum of a pack or delta. The checksum is verified.
+func decompress(data []byte) (payload []byte, name, checksum string, err error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %w", ErrInvalidPack, err)
+	}
+
+	payload, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %w", ErrInvalidPack, err)
+	}
+
+	checksum, ok := strings.CutPrefix(zr.Comment, checksumPrefix)
+	if !ok {
+		return nil, "", "", fmt.Errorf("%w: missing checksum", ErrInvalidPack)
+	}
+
+	sum := sha256.Sum256(payload)
+	if hex.EncodeToString(sum[:]) != checksum {
+		return nil, "", "", fmt.Errorf("%w: checksum mismatch", ErrInvalidPack)
+	}
+
+	return payload, zr.Name, checksum, nil
+}
+
+// isPack reports if the data is gzip compressed, like a language pack.
+func isPack(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// PackLoader loads the language packs in a directory, every file with the PackSuffix is a language.
+// The ETag of the version is a hash of the checksums of the packs.
+type PackLoader struct {
+	fs  afero.Fs
+	dir string
+}
+
+var _ Loader = (*PackLoader)(nil)
+
+// NewPackLoader returns a loader for the language packs in dir.
+func NewPackLoader(fs afero.Fs, dir string) *PackLoader {
+	return &PackLoader{fs: fs, dir: dir}
+}
+
+func (l *PackLoader) Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error) {
+	files, err := afero.ReadDir(l.fs, l.dir)
+	if err != nil {
+		return nil, Version{}, fmt.Errorf("reading language packs: %w", err)
+	}
+
+	languages := make(map[LanguageID]*RawMessages)
+	errs := LanguageErrors{}
+	var version Version
+	hash := sha256.New()
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), PackSuffix) {
+			continue
+		}
+
+		data, err := afero.ReadFile(l.fs, filepath.Join(l.dir, file.Name()))
+		if err != nil {
+			return nil, Version{}, fmt.Errorf("reading language pack: %w", err)
+		}
+
+		pack, err := UnmarshalPack(data)
+		if err != nil {
+			// A corrupt pack of a language is a LanguageErrors, so the other languages are loaded with WithLenientLoad.
+			lang, langErr := ParseLanguage(strings.TrimSuffix(file.Name(), PackSuffix))
+			if langErr != nil {
+				return nil, Version{}, fmt.Errorf("file %s: %w", file.Name(), err)
+			}
+
+			errs[lang] = fmt.Errorf("file %s: %w", file.Name(), err)
+			fmt.Fprintf(hash, "%s %x\n", file.Name(), sha256.Sum256(data))
+			continue
+		}
+
+		languages[pack.Language] = pack.Messages
+		fmt.Fprintf(hash, "%s %s\n", file.Name(), pack.Checksum)
+		if pack.Version.LastModified.After(version.LastModified) {
+			version.LastModified = pack.Version.LastModified
+		}
+	}
+
+	version.ETag = hex.EncodeToString(hash.Sum(nil))
+	if version.ETag == since.ETag {
+		return nil, version, ErrNotModified
+	}
+
+	if len(errs) > 0 {
+		return languages, version, errs
+	}
+
+	return languages, version, nil
+}
@@ -0,0 +1,91 @@
+package messages
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPack(t *testing.T) {
+	nl, err := ParseLanguage("nl")
+	require.NoError(t, err)
+
+	pack := &Pack{
+		Language: nl,
+		Messages: &RawMessages{Messages: map[string]string{"welcome": "Welkom :User"}, Attributes: map[string]string{"email": "e-mailadres"}},
+		Version:  Version{ETag: "v1.2.3", LastModified: time.UnixMilli(1700000000000)},
+	}
+
+	data, err := pack.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, pack.Checksum, 64)
+
+	decoded, err := UnmarshalPack(data)
+	require.NoError(t, err)
+	require.Equal(t, pack.Language, decoded.Language)
+	require.Equal(t, pack.Messages, decoded.Messages)
+	require.Equal(t, pack.Checksum, decoded.Checksum)
+	require.Equal(t, "v1.2.3", decoded.Version.ETag)
+	require.True(t, pack.Version.LastModified.Equal(decoded.Version.LastModified))
+
+	// A truncated download is invalid.
+	_, err = UnmarshalPack(data[:len(data)-4])
+	require.ErrorIs(t, err, ErrInvalidPack)
+
+	// A pack with the checksum of other messages is invalid.
+	var tampered bytes.Buffer
+	zw := gzip.NewWriter(&tampered)
+	zw.Name = "nl" + packName
+	zw.Comment = checksumPrefix + pack.Checksum
+	_, err = zw.Write(MarshalBundle(map[LanguageID]*RawMessages{nl: {Messages: map[string]string{"welcome": "Hallo"}}}, Version{}))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	_, err = UnmarshalPack(tampered.Bytes())
+	require.ErrorIs(t, err, ErrInvalidPack)
+}
+
+func TestPackLoader(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for lang, welcome := range map[string]string{"en": "Welcome :User", "nl": "Welkom :User"} {
+		languageID, err := ParseLanguage(lang)
+		require.NoError(t, err)
+
+		data, err := (&Pack{Language: languageID, Messages: &RawMessages{Messages: map[string]string{"welcome": welcome}}}).MarshalBinary()
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fs, "packs/"+lang+PackSuffix, data, 0o644))
+	}
+
+	loader := NewPackLoader(fs, "packs")
+	tr, err := NewTranslatorFromLoader(context.Background(), loader)
+	require.NoError(t, err)
+	require.Equal(t, "Welkom Jan", tr.Translate(ToCtx(context.Background(), "nl"), "welcome", map[string]any{"user": "jan"}))
+
+	_, version, err := loader.Load(context.Background(), Version{})
+	require.NoError(t, err)
+	_, _, err = loader.Load(context.Background(), version)
+	require.ErrorIs(t, err, ErrNotModified)
+}
+
+func TestHTTPLoaderPack(t *testing.T) {
+	nl, err := ParseLanguage("nl")
+	require.NoError(t, err)
+
+	data, err := (&Pack{Language: nl, Messages: &RawMessages{Messages: map[string]string{"welcome": "Welkom :User"}}}).MarshalBinary()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	tr, err := NewTranslatorFromLoader(context.Background(), NewHTTPLoader(server.URL, server.Client()))
+	require.NoError(t, err)
+	require.Equal(t, "Welkom Jan", tr.Translate(ToCtx(context.Background(), "nl"), "welcome", map[string]any{"user": "jan"}))
+}
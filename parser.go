@@ -4,28 +4,118 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/spf13/afero"
-	"golang.org/x/exp/maps"
+	"github.com/wvell/messages/internal/format"
+	"github.com/wvell/messages/internal/sorted"
+	"golang.org/x/text/unicode/norm"
 )
 
 var (
 	ErrDuplicateReplacementWithDifferentCase = fmt.Errorf("duplicate replacement with different case")
+	ErrSuspiciousPlaceholder                 = fmt.Errorf("suspicious placeholder")
+	ErrDuplicateNormalizedKey                = fmt.Errorf("keys only differ in unicode normalization")
+	ErrUnsupportedEncoding                   = fmt.Errorf("unsupported encoding")
 )
 
-var messageRe = regexp.MustCompile(`:[A-Za-z]+(\.[A-Za-z]+)*`)
+var (
+	// Placeholders like :user, :User, :address.street or :total|percent(1).
+	// A placeholder that is escaped with a backslash, like \:user, is not replaced.
+	messageRe = regexp.MustCompile(format.Placeholder)
+	regionRe  = regexp.MustCompile(`^(?:[A-Z]{2}|\d{3})$`)
+	// Runs of whitespace, including unicode spaces like the non-breaking space.
+	spaceRunRe = regexp.MustCompile(`[\s\p{Zs}]+`)
+)
+
+func NewParser(fs afero.Fs, opts ...ParserOpt) *Parser {
+	p := &Parser{fs: fs}
 
-func NewParser(fs afero.Fs) *Parser {
-	return &Parser{fs: fs}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
 type Parser struct {
 	fs afero.Fs
+	// Optional callback for warnings about messages that are valid but probably not what the translator intended.
+	warn func(error)
+	// Languages that can use a replacement with different cases in one message.
+	mixedCase []string
+	// Whitespace normalization of message values.
+	trimSpace     bool
+	collapseSpace bool
+	// Environment of the overlay files, see WithEnvironment.
+	environment string
+	// Keep the message values without parsing placeholders and conditions, the Translator parses them, see WithMessageFormat.
+	rawValues bool
+	// Interned strings, so the keys and placeholders that are repeated in every language are only stored once.
+	strings map[string]string
+}
+
+// intern returns the interned copy of s.
+func (p *Parser) intern(s string) string {
+	if p.strings == nil {
+		p.strings = make(map[string]string)
+	}
+
+	if interned, ok := p.strings[s]; ok {
+		return interned
+	}
+
+	p.strings[s] = s
+	return s
+}
+
+// allLanguages is used in parser options that apply to all languages.
+const allLanguages = "*"
+
+// ParserOpt is a functional option for the Parser.
+type ParserOpt func(*Parser)
+
+// WithMixedCasePlaceholders allows a message to use a replacement with different cases, like :user and :User.
+// Each placeholder keeps its own casing. By default this is an ErrDuplicateReplacementWithDifferentCase error because it is usually a typo.
+// The policy applies to the given languages, e.g. "de" or "en-US", or to all languages when none are given.
+func WithMixedCasePlaceholders(languages ...string) ParserOpt {
+	return func(p *Parser) {
+		if len(languages) == 0 {
+			languages = []string{allLanguages}
+		}
+
+		p.mixedCase = append(p.mixedCase, languages...)
+	}
+}
+
+// WithTrimSpace removes leading and trailing whitespace, including zero width spaces, from message values.
+func WithTrimSpace() ParserOpt {
+	return func(p *Parser) {
+		p.trimSpace = true
+	}
+}
+
+// WithCollapseSpace replaces runs of whitespace inside message values with a single space.
+// A run is two or more whitespace characters or a run that contains a newline, single spaces like a non-breaking space are kept.
+func WithCollapseSpace() ParserOpt {
+	return func(p *Parser) {
+		p.collapseSpace = true
+	}
+}
+
+// WithWarnings calls fn for every warning while parsing messages, for example for suspicious placeholders like the ":id" in ":id_2".
+// Warnings are a *PlaceholderWarning and wrap ErrSuspiciousPlaceholder.
+func WithWarnings(fn func(error)) ParserOpt {
+	return func(p *Parser) {
+		p.warn = fn
+	}
 }
 
 // TranslationFilesFromDir returns all translation files from the given directory.
@@ -38,7 +128,9 @@ func (p *Parser) TranslationFilesFromDir(dir string) (map[string]string, error)
 
 	files := make(map[string]string)
 	for _, entry := range entries {
-		if entry.IsDir() {
+		// Hidden files, like .gitkeep, archives, overlays, packs and deltas are not translation files.
+		if entry.IsDir() || entry.Name() == MetadataFile || isArchiveFile(entry.Name()) || isOverlayFile.MatchString(entry.Name()) ||
+			strings.HasSuffix(entry.Name(), PackSuffix) || strings.HasSuffix(entry.Name(), DeltaSuffix) || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
@@ -58,48 +150,289 @@ func (p *Parser) TranslationFilesFromDir(dir string) (map[string]string, error)
 	return files, nil
 }
 
-// parseFile reads the given file and parses the translations.
-func (p *Parser) parseFile(file string) (*messages, error) {
-	rawMessages, err := p.MessagesFromFile(file)
+// parseFile reads the given file with the translations for languageID and parses the translations on top of the optional layer messages.
+func (p *Parser) parseFile(languageID, file string, layer *RawMessages) (*messages, error) {
+	rawMessages, err := p.messagesWithOverlay(file)
 	if err != nil {
 		return nil, fmt.Errorf("reading file: %w", err)
 	}
 
-	messages := &messages{
-		messages:   make(map[Key]message),
-		attributes: rawMessages.Attributes,
+	return p.parseLayeredMessages(languageID, file, layer, rawMessages)
+}
+
+// parseLayeredMessages parses the raw messages on top of the optional layer messages, the keys that only exist in the layer are marked.
+func (p *Parser) parseLayeredMessages(languageID, source string, layer, rawMessages *RawMessages) (*messages, error) {
+	messages, err := p.parseMessages(languageID, source, layered(layer, rawMessages))
+	if err != nil || layer == nil {
+		return messages, err
 	}
 
-	for key, value := range rawMessages.Messages {
-		message := message{
-			message:      value,
-			replacements: make(map[string]replacement),
+	messages.layerKeys = make(map[Key]struct{})
+	for key := range layer.Messages {
+		if _, ok := rawMessages.Messages[key]; !ok {
+			messages.layerKeys[Key(p.intern(key))] = struct{}{}
 		}
+	}
 
-		replacements := messageRe.FindAllString(value, -1)
-		for _, replacementMatch := range replacements {
-			runes := []rune(replacementMatch[1:])
-			replacementKey := strings.ToLower(replacementMatch[1:])
-			isUpper := unicode.IsUpper(runes[0])
+	return messages, nil
+}
 
-			// Check if the replacement already exists with a different case.
-			if existing, ok := message.replacements[replacementKey]; ok {
-				if existing.isUpper != isUpper {
-					return nil, fmt.Errorf("%w: message %q replacement %q", ErrDuplicateReplacementWithDifferentCase, key, replacementKey)
-				}
-			}
+// parseMessages parses the raw messages for languageID, source is the file or other source of the messages that is used in errors.
+func (p *Parser) parseMessages(languageID, source string, rawMessages *RawMessages) (*messages, error) {
+	messages := &messages{
+		source:     source,
+		messages:   make([]message, 0, len(rawMessages.Messages)),
+		index:      make(map[Key]int32, len(rawMessages.Messages)),
+		regions:    make(map[string]map[Key]message),
+		attributes: make(map[string]string, len(rawMessages.Attributes)),
+		// The compiled overrides, see Translator.override.
+		overrideCache: &sync.Map{},
+	}
+
+	for name, value := range rawMessages.Attributes {
+		messages.attributes[p.intern(name)] = p.intern(value)
+	}
+
+	// The keys are sorted, so the same file always returns the same error.
+	for _, key := range sorted.Keys(rawMessages.Messages) {
+		value := rawMessages.Messages[key]
+		key = p.intern(key)
+		value = p.intern(p.normalizeSpace(value))
 
-			message.replacements[replacementKey] = replacement{
-				isUpper:        isUpper,
-				replacementKey: replacementMatch,
+		message, err := p.parseMessage(key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.allowMixedCase(languageID) {
+			err := checkReplacementCase(source, key, message)
+			if err != nil {
+				return nil, err
 			}
 		}
-		messages.messages[Key(key)] = message
+
+		// Region overrides like "color@GB" are stored per region.
+		if base, region, ok := SplitRegionKey(key); ok {
+			messages.addRegionOverride(p.intern(region), Key(p.intern(base)), message)
+			continue
+		}
+
+		messages.add(Key(key), message)
 	}
 
 	return messages, nil
 }
 
+// normalizeSpace applies the whitespace normalization options to the message value.
+func (p *Parser) normalizeSpace(value string) string {
+	if p.trimSpace {
+		value = strings.TrimFunc(value, func(r rune) bool {
+			return unicode.IsSpace(r) || isZeroWidth(r)
+		})
+	}
+
+	if p.collapseSpace {
+		value = spaceRunRe.ReplaceAllStringFunc(value, func(run string) string {
+			if utf8.RuneCountInString(run) == 1 && !strings.ContainsAny(run, "\r\n") {
+				return run
+			}
+
+			return " "
+		})
+	}
+
+	return value
+}
+
+func isZeroWidth(r rune) bool {
+	return r == '\u200b' || r == '\u200c' || r == '\u200d' || r == '\ufeff'
+}
+
+// parseMessage parses the message value of key.
+//
+// A placeholder that is directly followed by a digit or underscore, like :user_name, only replaces :user. The message is parsed
+// and a PlaceholderWarning is reported, like for placeholders inside a word, e.g. "driver:s", and single letter placeholders
+// followed by punctuation.
+// Escape the colon with a backslash to use it literally: "driver\\:s" in JSON.
+func (p *Parser) parseMessage(key, value string) (message, error) {
+	if p.rawValues {
+		return message{message: value}, nil
+	}
+
+	// Conditional messages select one of two messages at format time.
+	if cond, ok, err := p.parseCondition(key, value); ok || err != nil {
+		if err != nil {
+			return message{}, err
+		}
+
+		return message{message: value, condition: cond}, nil
+	}
+
+	message := message{
+		message: value,
+	}
+
+	replacements := messageRe.FindAllStringSubmatchIndex(value, -1)
+	for _, loc := range replacements {
+		// Escaped placeholders are not replaced.
+		if loc[3] > loc[2] {
+			continue
+		}
+
+		replacementMatch, name := value[loc[0]:loc[1]], value[loc[4]:loc[5]]
+
+		p.checkPlaceholder(key, value, loc[0], loc[1], name)
+
+		// A placeholder that is used more than once is stored once.
+		if _, ok := message.replacement(replacementMatch); ok {
+			continue
+		}
+
+		runes := []rune(name)
+		isUpper := unicode.IsUpper(runes[0])
+
+		message.replacements = append(message.replacements, replacement{
+			name:           p.intern(strings.ToLower(name)),
+			isUpper:        isUpper,
+			replacementKey: p.intern(replacementMatch),
+			modifier:       p.intern(substring(value, loc[6], loc[7])),
+			arg:            substring(value, loc[8], loc[9]),
+		})
+	}
+
+	return message, nil
+}
+
+// ReplacementCaseError is returned when a message uses a replacement with different cases, like :user and :User.
+// It wraps ErrDuplicateReplacementWithDifferentCase.
+type ReplacementCaseError struct {
+	File        string
+	Key         string
+	Replacement string
+}
+
+func (e *ReplacementCaseError) Error() string {
+	return fmt.Sprintf("%s: file %s message %q replacement %q", ErrDuplicateReplacementWithDifferentCase, e.File, e.Key, e.Replacement)
+}
+
+func (e *ReplacementCaseError) Unwrap() error {
+	return ErrDuplicateReplacementWithDifferentCase
+}
+
+// checkReplacementCase checks that every replacement in the message, and the messages of its conditions, is used with one case.
+func checkReplacementCase(file, key string, msg message) error {
+	if msg.condition != nil {
+		err := checkReplacementCase(file, key, msg.condition.then)
+		if err != nil {
+			return err
+		}
+
+		return checkReplacementCase(file, key, msg.condition.otherwise)
+	}
+
+	isUpper := make(map[string]bool)
+	for _, replacement := range msg.replacements {
+		if existing, ok := isUpper[replacement.name]; ok && existing != replacement.isUpper {
+			return &ReplacementCaseError{File: file, Key: key, Replacement: replacement.name}
+		}
+
+		isUpper[replacement.name] = replacement.isUpper
+	}
+
+	return nil
+}
+
+// allowMixedCase reports if the messages of the language can use a replacement with different cases.
+func (p *Parser) allowMixedCase(languageID string) bool {
+	lang, _, _ := strings.Cut(languageID, "-")
+	return slices.Contains(p.mixedCase, allLanguages) || slices.Contains(p.mixedCase, languageID) || slices.Contains(p.mixedCase, lang)
+}
+
+// PlaceholderWarning is a placeholder that is probably not meant as a placeholder, see WithWarnings.
+// It wraps ErrSuspiciousPlaceholder.
+type PlaceholderWarning struct {
+	Key string
+	// Placeholder is the placeholder as it is in the message, e.g. ":id".
+	Placeholder string
+	// Reason tells why the placeholder is suspicious.
+	Reason string
+}
+
+func (w *PlaceholderWarning) Error() string {
+	return fmt.Sprintf("%s: message %q placeholder %q %s", ErrSuspiciousPlaceholder, w.Key, w.Placeholder, w.Reason)
+}
+
+func (w *PlaceholderWarning) Unwrap() error {
+	return ErrSuspiciousPlaceholder
+}
+
+// checkPlaceholder warns about the placeholder at value[start:end] with the given name when it is suspicious.
+func (p *Parser) checkPlaceholder(key, value string, start, end int, name string) {
+	if p.warn == nil {
+		return
+	}
+
+	placeholder := value[start:end]
+	if end < len(value) && (value[end] == '_' || unicode.IsDigit(rune(value[end]))) {
+		p.warn(&PlaceholderWarning{Key: key, Placeholder: placeholder, Reason: fmt.Sprintf("is followed by %q, only letters and dots are part of a placeholder", value[end])})
+		return
+	}
+
+	before, _ := utf8.DecodeLastRuneInString(value[:start])
+	if start > 0 && (unicode.IsLetter(before) || unicode.IsDigit(before)) {
+		p.warn(&PlaceholderWarning{Key: key, Placeholder: placeholder, Reason: "is inside a word, escape the colon with a backslash if it is not a placeholder"})
+		return
+	}
+
+	after, _ := utf8.DecodeRuneInString(value[end:])
+	if len(name) == 1 && end < len(value) && unicode.IsPunct(after) {
+		p.warn(&PlaceholderWarning{Key: key, Placeholder: placeholder, Reason: fmt.Sprintf("is a single letter followed by %q, escape the colon with a backslash if it is not a placeholder", after)})
+	}
+}
+
+// substring returns value[start:end], or an empty string for an unmatched submatch.
+func substring(value string, start, end int) string {
+	if start < 0 {
+		return ""
+	}
+
+	return value[start:end]
+}
+
+// Placeholders returns the lowercase names of the placeholders in the message, sorted and without duplicates.
+// These are the names of the replacements that the message uses, escaped placeholders are skipped.
+func Placeholders(value string) []string {
+	var names []string
+	for _, loc := range messageRe.FindAllStringSubmatchIndex(value, -1) {
+		if loc[3] > loc[2] {
+			continue
+		}
+
+		name := strings.ToLower(value[loc[4]:loc[5]])
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// SplitRegionKey splits a region override key like "color@GB" in the key "color" and the region "GB".
+// The region must be an uppercase region code (GB) or a numeric area code (419), otherwise ok is false.
+func SplitRegionKey(key string) (base, region string, ok bool) {
+	i := strings.LastIndex(key, regionSeparator)
+	if i <= 0 {
+		return key, "", false
+	}
+
+	if !regionRe.MatchString(key[i+1:]) {
+		return key, "", false
+	}
+
+	return key[:i], key[i+1:], true
+}
+
 // RawTranslationsFromFile reads the translations from the given file and returns them as a map.
 func (p *Parser) MessagesFromFile(filename string) (*RawMessages, error) {
 	// Open the translations file.
@@ -114,17 +447,22 @@ func (p *Parser) MessagesFromFile(filename string) (*RawMessages, error) {
 		Attributes: make(map[string]string),
 	}
 
-	stat, err := f.Stat()
+	data, err := io.ReadAll(f)
 	if err != nil {
-		return nil, fmt.Errorf("error getting file info: %w", err)
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	data, err = stripBOM(data)
+	if err != nil {
+		return nil, fmt.Errorf("file %s: %w", filename, err)
 	}
 
 	// If the file is empty, return an empty map.
-	if stat.Size() == 0 {
+	if len(bytes.TrimSpace(data)) == 0 {
 		return rawMessages, nil
 	}
 
-	err = json.NewDecoder(f).Decode(&rawMessages)
+	err = json.Unmarshal(data, &rawMessages)
 	if err != nil {
 		return nil, fmt.Errorf("decoding file: %w", err)
 	}
@@ -132,6 +470,26 @@ func (p *Parser) MessagesFromFile(filename string) (*RawMessages, error) {
 	return rawMessages, nil
 }
 
+// stripBOM removes the UTF-8 byte order mark that is added by tools like Excel.
+// UTF-16 and UTF-32 files are not supported, an ErrUnsupportedEncoding error is returned for them.
+func stripBOM(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return data[3:], nil
+	case bytes.HasPrefix(data, []byte{0x00, 0x00, 0xFE, 0xFF}), bytes.HasPrefix(data, []byte{0xFF, 0xFE, 0x00, 0x00}):
+		return nil, fmt.Errorf("%w: the file is UTF-32 encoded, save the file as UTF-8", ErrUnsupportedEncoding)
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}), bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return nil, fmt.Errorf("%w: the file is UTF-16 encoded, save the file as UTF-8", ErrUnsupportedEncoding)
+	case len(data) >= 2 && (data[0] == 0 || data[1] == 0):
+		// UTF-16 without a byte order mark, the first character of a JSON file is ASCII.
+		return nil, fmt.Errorf("%w: the file looks UTF-16 encoded, save the file as UTF-8", ErrUnsupportedEncoding)
+	case !utf8.Valid(data):
+		return nil, fmt.Errorf("%w: the file is not valid UTF-8", ErrUnsupportedEncoding)
+	}
+
+	return data, nil
+}
+
 type RawMessages struct {
 	Messages   map[string]string
 	Attributes map[string]string
@@ -146,7 +504,17 @@ func (r *RawMessages) UnmarshalJSON(data []byte) error {
 	r.Messages = make(map[string]string)
 	r.Attributes = make(map[string]string)
 
-	for key, value := range temp {
+	// Keys and values are normalized to NFC, so a key with a combining accent matches the same key with a precomposed character.
+	// The keys are sorted, so the same file always returns the same error.
+	normalizedKeys := make(map[string]string)
+	for _, key := range sorted.Keys(temp) {
+		value := temp[key]
+		normalizedKey := norm.NFC.String(key)
+		if existing, ok := normalizedKeys[normalizedKey]; ok {
+			return fmt.Errorf("%w: %q and %q", ErrDuplicateNormalizedKey, existing, key)
+		}
+		normalizedKeys[normalizedKey] = key
+
 		if key == attributesKey {
 			var attributes map[string]string
 			err := json.Unmarshal(value, &attributes)
@@ -154,14 +522,40 @@ func (r *RawMessages) UnmarshalJSON(data []byte) error {
 				return fmt.Errorf("invalid format for @transform: %w", err)
 			}
 
-			r.Attributes = attributes
+			for name, attribute := range attributes {
+				r.Attributes[norm.NFC.String(name)] = norm.NFC.String(attribute)
+			}
+		} else if trimmed := bytes.TrimSpace(value); len(trimmed) > 0 && trimmed[0] == '{' {
+			// A plural message holds its forms in an object, they are stored under the keys of the forms, see PluralKey.
+			var forms map[string]string
+			if err := json.Unmarshal(value, &forms); err != nil {
+				return fmt.Errorf("invalid format for plural message value: %s: %w", key, err)
+			}
+
+			if _, ok := forms[string(PluralOther)]; !ok {
+				return fmt.Errorf("%w: message %q has no %q form", ErrInvalidPluralForm, key, PluralOther)
+			}
+
+			for _, form := range sorted.Keys(forms) {
+				if !slices.Contains(PluralForms, PluralForm(form)) {
+					return fmt.Errorf("%w: message %q form %q, use one of %q", ErrInvalidPluralForm, key, form, PluralForms)
+				}
+
+				formKey := pluralFormKey(normalizedKey, PluralForm(form))
+				if existing, ok := normalizedKeys[formKey]; ok {
+					return fmt.Errorf("%w: %q and %q", ErrDuplicateNormalizedKey, existing, key)
+				}
+				normalizedKeys[formKey] = key
+
+				r.Messages[formKey] = norm.NFC.String(forms[form])
+			}
 		} else {
 			var message string
 			if err := json.Unmarshal(value, &message); err != nil {
 				return fmt.Errorf("invalid format for message value: %s: %w", key, err)
 			}
 
-			r.Messages[key] = message
+			r.Messages[normalizedKey] = norm.NFC.String(message)
 		}
 	}
 	return nil
@@ -171,7 +565,17 @@ func (r *RawMessages) MarshalJSON() ([]byte, error) {
 	// First marshal all the Messages and Attributes to one map with the values as json.RawMessage.
 	// We can then sort the whole map.
 	var rawValues = make(map[string]json.RawMessage)
+	plurals := make(map[string]map[PluralForm]string)
 	for key, value := range r.Messages {
+		// The forms of a plural message are written as one object under the key of the message.
+		if jsonKey, form, ok := splitPluralJSONKey(key); ok {
+			if plurals[jsonKey] == nil {
+				plurals[jsonKey] = make(map[PluralForm]string)
+			}
+			plurals[jsonKey][form] = value
+			continue
+		}
+
 		data, err := json.Marshal(value)
 		if err != nil {
 			return nil, fmt.Errorf("marshaling message: %w", err)
@@ -180,6 +584,19 @@ func (r *RawMessages) MarshalJSON() ([]byte, error) {
 		rawValues[key] = json.RawMessage(data)
 	}
 
+	for jsonKey, forms := range plurals {
+		if _, ok := rawValues[jsonKey]; ok {
+			return nil, fmt.Errorf("%w: message %q has plural forms and a message without a form", ErrInvalidPluralForm, jsonKey)
+		}
+
+		data, err := marshalPluralForms(forms)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling plural message: %w", err)
+		}
+
+		rawValues[jsonKey] = data
+	}
+
 	if r.Attributes == nil {
 		r.Attributes = make(map[string]string)
 	}
@@ -199,6 +616,32 @@ func (r *RawMessages) MarshalJSON() ([]byte, error) {
 	return json.MarshalIndent(sortedMessages, "", "  ")
 }
 
+// marshalPluralForms marshals the forms of a plural message to an object with the forms in CLDR order, the other form last.
+func marshalPluralForms(forms map[PluralForm]string) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, form := range PluralForms {
+		value, ok := forms[form]
+		if !ok {
+			continue
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&buf, "%q:%s", form, data)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
 // MarshalMapToJSON sorts the given map alphabetically by it's key and marshals it JSON and writes it to the given writer.
 func marshalMapToJSON[T any](src map[string]T) (json.RawMessage, error) {
 	var buf bytes.Buffer
@@ -207,8 +650,7 @@ func marshalMapToJSON[T any](src map[string]T) (json.RawMessage, error) {
 		return []byte("{}"), nil
 	}
 
-	keys := maps.Keys(src)
-	slices.Sort(keys)
+	keys := sorted.Keys(src)
 
 	buf.Write([]byte{'{'})
 
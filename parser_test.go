@@ -2,6 +2,7 @@ package messages
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"os"
 	"testing"
@@ -43,3 +44,109 @@ func TestMarshalSorts(t *testing.T) {
 		require.True(t, bytes.Equal(data, expected), "expected: %q\n, got: %q\n", string(expected), string(data))
 	}
 }
+
+func TestInvalidPlaceholder(t *testing.T) {
+	// A placeholder that is followed by an underscore or digit is a warning, the rest of the word is text.
+	var warnings []error
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/invalid-placeholder", WithParserOpts(WithWarnings(func(err error) {
+		warnings = append(warnings, err)
+	})))
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], ErrSuspiciousPlaceholder)
+
+	var warning *PlaceholderWarning
+	require.ErrorAs(t, warnings[0], &warning)
+	require.Equal(t, &PlaceholderWarning{Key: "welcome", Placeholder: ":user", Reason: `is followed by '_', only letters and dots are part of a placeholder`}, warning)
+
+	ctx, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+	require.Equal(t, "Welcome jan_name", tr.Translate(ctx, "welcome", map[string]any{"user": "jan"}))
+}
+
+func TestSuspiciousPlaceholders(t *testing.T) {
+	var warnings []error
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/placeholders", WithParserOpts(WithWarnings(func(err error) {
+		warnings = append(warnings, err)
+	})))
+	require.NoError(t, err)
+
+	require.Len(t, warnings, 2)
+	for _, warning := range warnings {
+		require.ErrorIs(t, warning, ErrSuspiciousPlaceholder)
+	}
+
+	ctx, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	message := tr.Translate(ctx, "escaped", map[string]any{"name": "dates", "value": "ignored"})
+	require.Equal(t, "Use the format key:value for dates", message)
+
+	message = tr.Translate(ctx, "time", nil)
+	require.Equal(t, "Starts at 10:30", message)
+}
+
+func TestWhitespaceNormalization(t *testing.T) {
+	ctx, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		opts     []ParserOpt
+		key      Key
+		expected string
+	}{
+		{name: "none", key: "welcome", expected: "  Welcome\u200b  john,\n\n  glad   you are here.\t"},
+		{name: "trim", opts: []ParserOpt{WithTrimSpace()}, key: "welcome", expected: "Welcome\u200b  john,\n\n  glad   you are here."},
+		{name: "collapse", opts: []ParserOpt{WithCollapseSpace()}, key: "welcome", expected: " Welcome\u200b john, glad you are here.\t"},
+		{name: "trim and collapse", opts: []ParserOpt{WithTrimSpace(), WithCollapseSpace()}, key: "welcome", expected: "Welcome\u200b john, glad you are here."},
+		// A single non-breaking space is kept.
+		{name: "non-breaking space", opts: []ParserOpt{WithTrimSpace(), WithCollapseSpace()}, key: "price", expected: "Price:\u00a05"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tr, err := NewTranslator(afero.NewOsFs(), "./testdata/whitespace", WithParserOpts(c.opts...))
+			require.NoError(t, err)
+
+			require.Equal(t, c.expected, tr.Translate(ctx, c.key, map[string]any{"name": "john", "price": 5}))
+		})
+	}
+}
+
+func TestUnicodeNormalization(t *testing.T) {
+	// The file uses the decomposed form NFD.
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/normalization")
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "fr")
+	require.NoError(t, err)
+
+	for _, key := range []Key{"menu.cafe\u0301", "menu.caf\u00e9"} {
+		message := tr.Translate(ctx, key, nil)
+		require.Equal(t, "Le caf\u00e9 du jour", message)
+	}
+
+	_, err = NewTranslator(afero.NewOsFs(), "./testdata/invalid-normalization")
+	require.ErrorIs(t, err, ErrDuplicateNormalizedKey)
+}
+
+func TestEncoding(t *testing.T) {
+	parser := NewParser(afero.NewOsFs())
+
+	raw, err := parser.MessagesFromFile("./testdata/encoding/bom.json")
+	require.NoError(t, err)
+	require.Equal(t, "Welcome", raw.Messages["welcome"])
+
+	for _, file := range []string{"utf16.json", "utf16-nobom.json", "latin1.json"} {
+		t.Run(file, func(t *testing.T) {
+			_, err := parser.MessagesFromFile("./testdata/encoding/" + file)
+			require.ErrorIs(t, err, ErrUnsupportedEncoding)
+		})
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	require.Equal(t, []string{"count", "user"}, Placeholders(`Welcome :User, :count messages for :user|upper and \:escaped`))
+	require.Empty(t, Placeholders("Welcome"))
+}
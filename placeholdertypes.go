@@ -0,0 +1,206 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/wvell/messages/internal/sorted"
+)
+
+var (
+	ErrUnknownPlaceholderType = fmt.Errorf("unknown placeholder type")
+	ErrPlaceholderType        = fmt.Errorf("placeholder type mismatch")
+)
+
+// PlaceholderTypes holds the expected type of the replacements by placeholder name. In the metadata it is an object,
+// or a list of "name:type" declarations:
+//
+//	"placeholders": {"count": "int", "user": "string"}
+//	"placeholders": ["count:int", "user:string", "price:money"]
+//
+// The types are string, int, float, number (an int or float), money (an int, float or a value with a formatter), bool, time, duration and list.
+type PlaceholderTypes map[string]string
+
+// placeholderTypes reports if a replacement value matches the type.
+var placeholderTypes = map[string]func(value reflect.Value) bool{
+	"string": func(value reflect.Value) bool {
+		_, isStringer := value.Interface().(fmt.Stringer)
+		return value.Kind() == reflect.String || isStringer
+	},
+	"int":   isIntValue,
+	"float": isFloatValue,
+	"number": func(value reflect.Value) bool {
+		return isIntValue(value) || isFloatValue(value)
+	},
+	"money": func(value reflect.Value) bool {
+		return isIntValue(value) || isFloatValue(value)
+	},
+	"bool": func(value reflect.Value) bool {
+		return value.Kind() == reflect.Bool
+	},
+	"time": func(value reflect.Value) bool {
+		return value.Type() == reflect.TypeFor[time.Time]()
+	},
+	"duration": func(value reflect.Value) bool {
+		return value.Type() == reflect.TypeFor[time.Duration]()
+	},
+	"list": func(value reflect.Value) bool {
+		return value.Kind() == reflect.Slice || value.Kind() == reflect.Array
+	},
+}
+
+func isIntValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Type() != reflect.TypeFor[time.Duration]()
+	}
+
+	return false
+}
+
+func isFloatValue(value reflect.Value) bool {
+	return value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64
+}
+
+// UnmarshalJSON decodes the object or the list of "name:type" declarations.
+func (p *PlaceholderTypes) UnmarshalJSON(data []byte) error {
+	var types map[string]string
+	if err := json.Unmarshal(data, &types); err == nil {
+		*p = types
+		return nil
+	}
+
+	var declarations []string
+	if err := json.Unmarshal(data, &declarations); err != nil {
+		return fmt.Errorf("placeholders must be an object or a list of \"name:type\": %w", err)
+	}
+
+	types = make(map[string]string, len(declarations))
+	for _, declaration := range declarations {
+		name, typ, ok := strings.Cut(declaration, ":")
+		if !ok || name == "" {
+			return fmt.Errorf("%w: %q is not \"name:type\"", ErrUnknownPlaceholderType, declaration)
+		}
+		types[name] = typ
+	}
+
+	*p = types
+	return nil
+}
+
+// validatePlaceholderTypes returns an ErrUnknownPlaceholderType for the first type that is not supported.
+func (m Metadata) validatePlaceholderTypes() error {
+	for _, key := range sorted.Keys(m) {
+		types := m[key].Placeholders
+		for _, name := range sorted.Keys(types) {
+			if _, ok := placeholderTypes[types[name]]; !ok {
+				return fmt.Errorf("%w: %q of placeholder %q of %q", ErrUnknownPlaceholderType, types[name], name, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// TypeMismatch is a replacement that does not have the type of the placeholder in the metadata.
+type TypeMismatch struct {
+	Language    LanguageID
+	Key         Key
+	Placeholder string
+	// Expected is the type in the metadata, e.g. "int".
+	Expected string
+	Value    any
+}
+
+func (m *TypeMismatch) Error() string {
+	return fmt.Sprintf("%s: %s of %q expects %s, got %T", ErrPlaceholderType, m.Placeholder, m.Key, m.Expected, m.Value)
+}
+
+func (m *TypeMismatch) Unwrap() error {
+	return ErrPlaceholderType
+}
+
+// TypeMismatchHook is called for every replacement that does not match its type, see WithTypeCheck.
+type TypeMismatchHook func(ctx context.Context, mismatch *TypeMismatch)
+
+// WithTypeCheck checks the replacements given to Translate against the placeholder types in the metadata and calls the hook for
+// every mismatch, e.g. to log it or to panic in tests. Without the check a struct where a string is expected is formatted as an empty string.
+// Values with a formatter, see WithFormatter, match every type. Use Translator.CheckReplacements to get the mismatches as an error.
+func WithTypeCheck(hook TypeMismatchHook) Opt {
+	return func(t *Translator) {
+		t.typeMismatchHook = hook
+	}
+}
+
+// CheckReplacements returns the TypeMismatch errors of the replacements for the placeholder types of the key in the default language.
+// Placeholders without a type, and types without a replacement, are not checked.
+func (t *Translator) CheckReplacements(key Key, replacements map[string]any) error {
+	c := t.current.Load()
+	if c == nil {
+		return nil
+	}
+
+	messages := c.languages[t.defaultLanguage.String()]
+	if messages == nil {
+		messages = c.languages[t.defaultLanguage.Language]
+	}
+
+	var errs []error
+	for _, mismatch := range c.metadata.typeMismatches(messages, key, replacements) {
+		mismatch.Language = t.defaultLanguage
+		errs = append(errs, mismatch)
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkTypes calls the type mismatch hook for the replacements that do not match their placeholder type.
+func (t *Translator) checkTypes(ctx context.Context, messages *messages, region string, key Key, replacements map[string]any) {
+	if t.typeMismatchHook == nil || len(replacements) == 0 {
+		return
+	}
+
+	c := t.current.Load()
+	if c == nil {
+		return
+	}
+
+	for _, mismatch := range c.metadata.typeMismatches(messages, key, replacements) {
+		mismatch.Language = messages.id(region)
+		t.typeMismatchHook(ctx, mismatch)
+	}
+}
+
+// typeMismatches returns the replacements that do not match the placeholder types of the key, sorted by placeholder.
+// Messages are used for the formatters of the language and may be nil.
+func (m Metadata) typeMismatches(messages *messages, key Key, replacements map[string]any) []*TypeMismatch {
+	base, _, _ := splitCasingDirective(key)
+	types := m[string(base)].Placeholders
+
+	var mismatches []*TypeMismatch
+	for _, name := range sorted.Keys(types) {
+		value, ok := replacements[name]
+		if !ok || value == nil {
+			continue
+		}
+
+		valueOf := reflect.ValueOf(value)
+		if messages != nil {
+			if _, ok := messages.formatters[valueOf.Type()]; ok {
+				continue
+			}
+		}
+
+		if !placeholderTypes[types[name]](valueOf) {
+			mismatches = append(mismatches, &TypeMismatch{Key: base, Placeholder: name, Expected: types[name], Value: value})
+		}
+	}
+
+	return mismatches
+}
@@ -0,0 +1,72 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestTypeCheck(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"cart": ":User has :count items of :price, ordered :at"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"cart": ":User heeft :count artikelen van :price, besteld :at"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{
+		"cart": {"placeholders": ["count:int", "user:string", "price:money", "at:time"]}
+	}`), 0o644))
+
+	var mismatches []*TypeMismatch
+	hook := func(_ context.Context, mismatch *TypeMismatch) {
+		mismatches = append(mismatches, mismatch)
+	}
+
+	tr, err := NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "en"}), WithTypeCheck(hook))
+	require.NoError(t, err)
+
+	nl := ToCtx(context.Background(), "nl")
+
+	tr.Translate(nl, "cart", map[string]any{"user": "jan", "count": 3, "price": 9.95, "at": time.Now()})
+	require.Empty(t, mismatches)
+
+	user := struct{ Name string }{Name: "jan"}
+	tr.Translate(nl, "cart!upper", map[string]any{"user": user, "count": "3", "price": 10})
+	require.Equal(t, []*TypeMismatch{
+		{Language: LanguageID{Language: "nl"}, Key: "cart", Placeholder: "count", Expected: "int", Value: "3"},
+		{Language: LanguageID{Language: "nl"}, Key: "cart", Placeholder: "user", Expected: "string", Value: user},
+	}, mismatches)
+
+	err = tr.CheckReplacements("cart", map[string]any{"count": 1.5})
+	require.ErrorIs(t, err, ErrPlaceholderType)
+	require.EqualError(t, err, `placeholder type mismatch: count of "cart" expects int, got float64`)
+	require.NoError(t, tr.CheckReplacements("cart", map[string]any{"count": 1, "at": time.Now()}))
+}
+
+func TestTypeCheckFormatter(t *testing.T) {
+	type money struct{ cents int }
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"total": "Total :price"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{"total": {"placeholders": {"price": "money"}}}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "en"}), WithFormatter(func(_ language.Tag, m money) string {
+		return fmt.Sprintf("€%d.%02d", m.cents/100, m.cents%100)
+	}))
+	require.NoError(t, err)
+
+	// Values with a formatter match every type.
+	require.NoError(t, tr.CheckReplacements("total", map[string]any{"price": money{cents: 995}}))
+	require.Error(t, tr.CheckReplacements("total", map[string]any{"price": "9.95"}))
+}
+
+func TestUnknownPlaceholderType(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"cart": "You have :count items"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{"cart": {"placeholders": {"count": "integer"}}}`), 0o644))
+
+	_, err := NewTranslator(fs, "translations")
+	require.ErrorIs(t, err, ErrUnknownPlaceholderType)
+}
@@ -0,0 +1,150 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+var (
+	// ErrInvalidPluralForm is returned for a plural message with a form that is not a CLDR plural form, or without the other form.
+	ErrInvalidPluralForm = fmt.Errorf("invalid plural form")
+)
+
+// PluralForm is a CLDR plural category of a plural message, see TranslatePlural.
+type PluralForm string
+
+const (
+	PluralZero  PluralForm = "zero"
+	PluralOne   PluralForm = "one"
+	PluralTwo   PluralForm = "two"
+	PluralFew   PluralForm = "few"
+	PluralMany  PluralForm = "many"
+	PluralOther PluralForm = "other"
+)
+
+// PluralForms are the CLDR plural forms in the order they are written to the translation files.
+var PluralForms = []PluralForm{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+
+// pluralFormsByCategory maps the forms of golang.org/x/text/feature/plural to the plural forms.
+var pluralFormsByCategory = map[plural.Form]PluralForm{
+	plural.Zero:  PluralZero,
+	plural.One:   PluralOne,
+	plural.Two:   PluralTwo,
+	plural.Few:   PluralFew,
+	plural.Many:  PluralMany,
+	plural.Other: PluralOther,
+}
+
+// PluralCountKey is the replacement that TranslatePlural adds with the count, e.g. ":count cats".
+const PluralCountKey = "count"
+
+// PluralKey returns the key of a form of a plural message, e.g. "cats[one]" for "cats".
+// A translation file holds the forms in an object under the key itself, the forms are stored under their plural keys in RawMessages:
+//
+//	{
+//		"cats": {"one": ":count cat", "other": ":count cats"}
+//	}
+func PluralKey(key string, form PluralForm) string {
+	return key + "[" + string(form) + "]"
+}
+
+// SplitPluralKey splits the key of a plural form like "cats[one]" in the key "cats" and the form "one".
+// Ok is false if the key is not the key of a plural form.
+func SplitPluralKey(key string) (base string, form PluralForm, ok bool) {
+	i := strings.LastIndex(key, "[")
+	if i <= 0 || !strings.HasSuffix(key, "]") {
+		return key, "", false
+	}
+
+	form = PluralForm(key[i+1 : len(key)-1])
+	if !slices.Contains(PluralForms, form) {
+		return key, "", false
+	}
+
+	return key[:i], form, true
+}
+
+// splitPluralJSONKey returns the key of the plural message in the translation file for the key of a plural form,
+// e.g. "cats@GB" for the region override "cats[one]@GB".
+func splitPluralJSONKey(key string) (jsonKey string, form PluralForm, ok bool) {
+	base, region, hasRegion := SplitRegionKey(key)
+	base, form, ok = SplitPluralKey(base)
+	if !ok {
+		return key, "", false
+	}
+
+	if hasRegion {
+		base += regionSeparator + region
+	}
+
+	return base, form, true
+}
+
+// pluralFormKey returns the key of the form in the translation file of a plural message, e.g. "cats[one]@GB" for "cats@GB".
+func pluralFormKey(jsonKey string, form PluralForm) string {
+	if base, region, ok := SplitRegionKey(jsonKey); ok {
+		return PluralKey(base, form) + regionSeparator + region
+	}
+
+	return PluralKey(jsonKey, form)
+}
+
+// pluralForm returns the CLDR plural form of count in the language.
+func pluralForm(lang language.Tag, count int) PluralForm {
+	return pluralFormOf(plural.Cardinal, lang, float64(count))
+}
+
+// pluralFormOf returns the plural form of n in the language with the cardinal or ordinal rules.
+// The fraction digits are the digits that the shortest representation of n has, 1.5 has one fraction digit.
+func pluralFormOf(rules *plural.Rules, lang language.Tag, n float64) PluralForm {
+	integer, fraction, _ := strings.Cut(strconv.FormatFloat(math.Abs(n), 'f', -1, 64), ".")
+	i, _ := strconv.Atoi(integer)
+	f, _ := strconv.Atoi(fraction)
+
+	return pluralFormsByCategory[rules.MatchPlural(lang, i, len(fraction), len(fraction), f, f)]
+}
+
+// TranslatePlural translates the form of the plural message key that the CLDR plural rules of the language select for count,
+// e.g. "few" for 3 in Polish. The other form is used when the message has no translation for the form, a key without plural forms
+// is translated as a normal message. The count is added to the replacements as :count, unless the replacements have a count,
+// e.g. a formatted count like "1,000".
+// Every form can use the features of a normal message, like conditions: ":count == 0 ? No cats | :count cats".
+func (t *Translator) TranslatePlural(ctx context.Context, key Key, count int, replacements map[string]any) string {
+	if _, ok := replacements[PluralCountKey]; !ok {
+		withCount := make(map[string]any, len(replacements)+1)
+		for name, value := range replacements {
+			withCount[name] = value
+		}
+		withCount[PluralCountKey] = count
+		replacements = withCount
+	}
+
+	t.refreshIfStale()
+
+	messages, region := t.messages(ctx)
+	if messages == nil {
+		return t.Translate(ctx, key, replacements)
+	}
+
+	// The forms get the casing directive of the key, e.g. "cats[one]!upper" for "cats!upper".
+	base, directive := key, Key("")
+	if b, _, ok := splitCasingDirective(key); ok {
+		base, directive = b, key[len(b):]
+	}
+
+	for _, form := range []PluralForm{pluralForm(messages.tag(region), count), PluralOther} {
+		formKey := Key(PluralKey(string(base), form))
+		if _, ok := messages.lookup(formKey, region); ok {
+			return t.Translate(ctx, formKey+directive, replacements)
+		}
+	}
+
+	return t.Translate(ctx, key, replacements)
+}
@@ -0,0 +1,111 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslatePlural(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{
+		"cats": {"one": "One cat", "other": ":count == 0 ? No cats | :count cats"},
+		"cats@GB": {"one": "One moggy", "other": ":count moggies"},
+		"owner": {"one": ":user has one cat", "other": ":user has :count cats"},
+		"welcome": "Welcome"
+	}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/pl.json", []byte(`{
+		"cats": {"one": ":count kot", "few": ":count koty", "many": ":count kotów", "other": ":count kota"}
+	}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/ar.json", []byte(`{
+		"cats": {"zero": "لا قطط", "other": ":count قطط"}
+	}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "en"}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.Equal(t, "No cats", tr.TranslatePlural(ctx, "cats", 0, nil))
+	require.Equal(t, "One cat", tr.TranslatePlural(ctx, "cats", 1, nil))
+	require.Equal(t, "2 cats", tr.TranslatePlural(ctx, "cats", 2, nil))
+	require.Equal(t, "ONE CAT", tr.TranslatePlural(ctx, "cats!upper", 1, nil))
+	require.Equal(t, "jan has one cat", tr.TranslatePlural(ctx, "owner", 1, map[string]any{"user": "jan"}))
+	// A count replacement is shown instead of the count, e.g. a formatted count.
+	require.Equal(t, "jan has 1,000 cats", tr.TranslatePlural(ctx, "owner", 1000, map[string]any{"user": "jan", "count": "1,000"}))
+	require.Equal(t, "Welcome", tr.TranslatePlural(ctx, "welcome", 2, nil))
+	require.Equal(t, "unknown", tr.TranslatePlural(ctx, "unknown", 2, nil))
+
+	gb := ToCtx(ctx, "en-GB")
+	require.Equal(t, "One moggy", tr.TranslatePlural(gb, "cats", 1, nil))
+	require.Equal(t, "3 moggies", tr.TranslatePlural(gb, "cats", 3, nil))
+
+	pl := ToCtx(ctx, "pl")
+	require.Equal(t, "1 kot", tr.TranslatePlural(pl, "cats", 1, nil))
+	require.Equal(t, "3 koty", tr.TranslatePlural(pl, "cats", 3, nil))
+	require.Equal(t, "5 kotów", tr.TranslatePlural(pl, "cats", 5, nil))
+	require.Equal(t, "22 koty", tr.TranslatePlural(pl, "cats", 22, nil))
+	require.Equal(t, "-3 koty", tr.TranslatePlural(pl, "cats", -3, nil))
+
+	// The Arabic message has no one form, the other form is used.
+	ar := ToCtx(ctx, "ar")
+	require.Equal(t, "لا قطط", tr.TranslatePlural(ar, "cats", 0, nil))
+	require.Equal(t, "1 قطط", tr.TranslatePlural(ar, "cats", 1, nil))
+}
+
+func TestPluralMessagesJSON(t *testing.T) {
+	var raw RawMessages
+	require.NoError(t, raw.UnmarshalJSON([]byte(`{
+		"cats": {"other": ":count cats", "one": "One cat"},
+		"cats@GB": {"other": ":count moggies"},
+		"welcome": "Welcome"
+	}`)))
+	require.Equal(t, map[string]string{
+		"cats[one]":      "One cat",
+		"cats[other]":    ":count cats",
+		"cats[other]@GB": ":count moggies",
+		"welcome":        "Welcome",
+	}, raw.Messages)
+
+	data, err := raw.MarshalJSON()
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "attributes": {},
+  "cats": {
+    "one": "One cat",
+    "other": ":count cats"
+  },
+  "cats@GB": {
+    "other": ":count moggies"
+  },
+  "welcome": "Welcome"
+}`, string(data))
+
+	err = raw.UnmarshalJSON([]byte(`{"cats": {"one": "One cat"}}`))
+	require.ErrorIs(t, err, ErrInvalidPluralForm)
+
+	err = raw.UnmarshalJSON([]byte(`{"cats": {"single": "One cat", "other": ":count cats"}}`))
+	require.ErrorIs(t, err, ErrInvalidPluralForm)
+
+	err = raw.UnmarshalJSON([]byte(`{"cats": {"other": ":count cats"}, "cats[other]": "Cats"}`))
+	require.ErrorIs(t, err, ErrDuplicateNormalizedKey)
+
+	raw.Messages = map[string]string{"cats": "Cats", "cats[other]": ":count cats"}
+	_, err = raw.MarshalJSON()
+	require.ErrorIs(t, err, ErrInvalidPluralForm)
+}
+
+func TestSplitPluralKey(t *testing.T) {
+	base, form, ok := SplitPluralKey("cats[few]")
+	require.True(t, ok)
+	require.Equal(t, "cats", base)
+	require.Equal(t, PluralFew, form)
+
+	for _, key := range []string{"cats", "cats[]", "cats[single]", "[one]", "cats[one]@GB"} {
+		_, _, ok := SplitPluralKey(key)
+		require.False(t, ok, key)
+	}
+
+	require.Equal(t, "cats[one]", PluralKey("cats", PluralOne))
+}
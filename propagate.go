@@ -0,0 +1,105 @@
+package messages
+
+import (
+	"context"
+)
+
+// The headers that InjectLocale sets and ExtractLocale reads.
+const (
+	LanguageHeader = "Accept-Language"
+	TimeZoneHeader = "X-Time-Zone"
+	TenantHeader   = "X-Tenant"
+	ChannelHeader  = "X-Channel"
+)
+
+var (
+	tenantKey  = ctxKey("tenant")
+	channelKey = ctxKey("channel")
+)
+
+// HeaderCarrier holds the headers of a queue message. http.Header and nats.Header implement it,
+// use a MapCarrier for Kafka record headers or SQS message attributes.
+type HeaderCarrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// MapCarrier is a HeaderCarrier that stores the headers in a map.
+type MapCarrier map[string]string
+
+func (c MapCarrier) Get(key string) string {
+	return c[key]
+}
+
+func (c MapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// WithTenant sets the tenant in the ctx, e.g. for a KeyRewriter with tenant specific messages.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// TenantFromCtx returns the tenant of the ctx, it is empty if the ctx has no tenant.
+func TenantFromCtx(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey).(string)
+	return tenant
+}
+
+// WithChannel sets the channel the message is shown in in the ctx, e.g. "email" or "sms" for a VariantResolver.
+func WithChannel(ctx context.Context, channel string) context.Context {
+	return context.WithValue(ctx, channelKey, channel)
+}
+
+// ChannelFromCtx returns the channel of the ctx, it is empty if the ctx has no channel.
+func ChannelFromCtx(ctx context.Context) string {
+	channel, _ := ctx.Value(channelKey).(string)
+	return channel
+}
+
+// InjectLocale sets the languages, time zone, tenant and channel of the ctx in the headers of a message that is produced to a queue,
+// so the consumer translates in the locale of the request that produced the message. Values that are not in the ctx are not set:
+//
+//	headers := messages.MapCarrier{}
+//	messages.InjectLocale(ctx, headers)
+func InjectLocale(ctx context.Context, headers HeaderCarrier) {
+	if langs := LanguagesFromCtx(ctx); len(langs) > 0 {
+		headers.Set(LanguageHeader, acceptLanguageHeader(langs))
+	}
+
+	if loc := TimeZoneFromCtx(ctx); loc != nil {
+		headers.Set(TimeZoneHeader, loc.String())
+	}
+
+	if tenant := TenantFromCtx(ctx); tenant != "" {
+		headers.Set(TenantHeader, tenant)
+	}
+
+	if channel := ChannelFromCtx(ctx); channel != "" {
+		headers.Set(ChannelHeader, channel)
+	}
+}
+
+// ExtractLocale restores the locale that InjectLocale set in the headers of a consumed message in the ctx.
+// Headers that are missing or can not be parsed are skipped, the ctx keeps its own value then.
+func ExtractLocale(ctx context.Context, headers HeaderCarrier) context.Context {
+	if langs := parseAcceptLanguage(headers.Get(LanguageHeader)); len(langs) > 0 {
+		ctx = toCtx(ctx, langs...)
+	}
+
+	if name := headers.Get(TimeZoneHeader); name != "" {
+		if loc, ok := loadLocation(name); ok {
+			ctx = WithTimeZone(ctx, loc)
+		}
+	}
+
+	if tenant := headers.Get(TenantHeader); tenant != "" {
+		ctx = WithTenant(ctx, tenant)
+	}
+
+	if channel := headers.Get(ChannelHeader); channel != "" {
+		ctx = WithChannel(ctx, channel)
+	}
+
+	return ctx
+}
@@ -0,0 +1,54 @@
+package messages
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalePropagation(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+
+	ctx, err := WithLanguages(context.Background(), "nl-BE", "en")
+	require.NoError(t, err)
+	ctx = WithTimeZone(ctx, amsterdam)
+	ctx = WithTenant(ctx, "acme")
+	ctx = WithChannel(ctx, "email")
+
+	headers := MapCarrier{}
+	InjectLocale(ctx, headers)
+	require.Equal(t, MapCarrier{
+		LanguageHeader: "nl-BE, nl;q=0.9, en;q=0.8",
+		TimeZoneHeader: "Europe/Amsterdam",
+		TenantHeader:   "acme",
+		ChannelHeader:  "email",
+	}, headers)
+
+	consumed := ExtractLocale(context.Background(), headers)
+	require.Equal(t, LanguageID{Language: "nl", Region: "BE"}, FromCtx(consumed))
+	require.Equal(t, []LanguageID{{Language: "nl", Region: "BE"}, {Language: "nl"}, {Language: "en"}}, LanguagesFromCtx(consumed))
+	require.Equal(t, amsterdam, TimeZoneFromCtx(consumed))
+	require.Equal(t, "acme", TenantFromCtx(consumed))
+	require.Equal(t, "email", ChannelFromCtx(consumed))
+
+	// http.Header is a HeaderCarrier as well.
+	header := http.Header{}
+	InjectLocale(ctx, header)
+	require.Equal(t, "acme", header.Get(TenantHeader))
+	require.Equal(t, "email", ChannelFromCtx(ExtractLocale(context.Background(), header)))
+
+	// Nothing is set for a ctx without a locale.
+	headers = MapCarrier{}
+	InjectLocale(context.Background(), headers)
+	require.Empty(t, headers)
+
+	// Invalid headers are skipped, the ctx keeps its own values.
+	own := ToCtx(context.Background(), "de")
+	consumed = ExtractLocale(own, MapCarrier{LanguageHeader: "*", TimeZoneHeader: "Mars/Olympus"})
+	require.Equal(t, LanguageID{Language: "de"}, FromCtx(consumed))
+	require.Nil(t, TimeZoneFromCtx(consumed))
+}
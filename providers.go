@@ -0,0 +1,89 @@
+package messages
+
+import (
+	"context"
+	"strings"
+)
+
+// ReplacementProvider returns the value of a replacement from the context, e.g. the name of the authenticated user.
+// Ok is false if the context has no value for the replacement.
+type ReplacementProvider func(ctx context.Context) (value any, ok bool)
+
+// WithReplacementProvider registers a provider for the replacement with the given name, so callers don't have to pass it on every call:
+//
+//	messages.WithReplacementProvider("username", func(ctx context.Context) (any, bool) {
+//		user, ok := auth.UserFromCtx(ctx)
+//		return user.Name, ok
+//	})
+//
+// The provider only runs when the message uses the replacement. A replacement that is given by the caller takes precedence.
+func WithReplacementProvider(name string, provider ReplacementProvider) Opt {
+	return func(t *Translator) {
+		if t.providers == nil {
+			t.providers = make(map[string]ReplacementProvider)
+		}
+
+		t.providers[strings.ToLower(name)] = provider
+	}
+}
+
+// provideReplacements adds the values of the providers for the replacements that are used by the message of key.
+// The replacements of the caller are not modified, a copy is returned when a value is added.
+func (t *Translator) provideReplacements(ctx context.Context, messages *messages, region string, key Key, replacements map[string]any) map[string]any {
+	if len(t.providers) == 0 {
+		return replacements
+	}
+
+	msg, ok := messages.lookup(key, region)
+	if !ok {
+		base, _, found := splitCasingDirective(key)
+		if !found {
+			return replacements
+		}
+
+		if msg, ok = messages.lookup(base, region); !ok {
+			return replacements
+		}
+	}
+
+	copied := false
+	for name, provider := range t.providers {
+		if _, ok := replacements[name]; ok || !msg.uses(name) {
+			continue
+		}
+
+		value, ok := provider(ctx)
+		if !ok {
+			continue
+		}
+
+		if !copied {
+			withProvided := make(map[string]any, len(replacements)+1)
+			for k, v := range replacements {
+				withProvided[k] = v
+			}
+
+			replacements = withProvided
+			copied = true
+		}
+
+		replacements[name] = value
+	}
+
+	return replacements
+}
+
+// uses reports if the message, or one of the messages of its condition, uses the replacement.
+func (m message) uses(name string) bool {
+	if m.condition != nil {
+		return m.condition.name == name || m.condition.then.uses(name) || m.condition.otherwise.uses(name)
+	}
+
+	for _, replacement := range m.replacements {
+		if replacement.name == name {
+			return true
+		}
+	}
+
+	return false
+}
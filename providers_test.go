@@ -0,0 +1,53 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+type userKey struct{}
+
+func TestReplacementProviders(t *testing.T) {
+	calls := 0
+
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/providers",
+		WithReplacementProvider("username", func(ctx context.Context) (any, bool) {
+			calls++
+			user, ok := ctx.Value(userKey{}).(string)
+			return user, ok
+		}),
+		WithReplacementProvider("Tenant", func(ctx context.Context) (any, bool) {
+			return "acme", true
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	userCtx := context.WithValue(ctx, userKey{}, "jan")
+
+	require.Equal(t, "Welcome Jan", tr.Translate(userCtx, "welcome", nil))
+	require.Equal(t, "WELCOME JAN", tr.Translate(userCtx, "welcome!upper", nil))
+
+	// The replacement of the caller takes precedence.
+	replacements := map[string]any{"username": "piet"}
+	require.Equal(t, "Welcome Piet", tr.Translate(userCtx, "welcome", replacements))
+	require.Equal(t, map[string]any{"username": "piet"}, replacements)
+
+	// The provider has no value.
+	require.Equal(t, "Welcome ", tr.Translate(ctx, "welcome", nil))
+
+	// Providers are used in conditions.
+	require.Equal(t, "No users in acme", tr.Translate(ctx, "users", map[string]any{"count": 0}))
+	require.Equal(t, "3 users in acme", tr.Translate(ctx, "users", map[string]any{"count": 3}))
+
+	// The provider only runs when the message uses the replacement.
+	calls = 0
+	require.Equal(t, "Hello", tr.Translate(userCtx, "plain", nil))
+	require.Equal(t, "unknown", tr.Translate(userCtx, "unknown", nil))
+	require.Equal(t, 0, calls)
+}
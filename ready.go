@@ -0,0 +1,86 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wvell/messages/internal/sorted"
+)
+
+// ErrNotReady is returned by Ready when the translations are not ready to serve.
+var ErrNotReady = fmt.Errorf("translations not ready")
+
+// WithReadyLanguages makes Ready fail when one of the languages is not loaded.
+func WithReadyLanguages(langs ...LanguageID) Opt {
+	return func(t *Translator) {
+		t.readyLanguages = append(t.readyLanguages, langs...)
+	}
+}
+
+// WithReadyMinKeys makes Ready fail when a language has less than n keys, e.g. when an empty translation file is deployed.
+func WithReadyMinKeys(n int) Opt {
+	return func(t *Translator) {
+		t.readyMinKeys = n
+	}
+}
+
+// Ready returns an ErrNotReady error when the translations are not ready to serve, for the readiness probe of a deployment.
+// The translations are not ready when they are not loaded, a language failed to load (see WithLenientLoad), the default language
+// or a language of WithReadyLanguages is missing, or a language has less keys than WithReadyMinKeys.
+// The error lists all problems.
+func (t *Translator) Ready() error {
+	c := t.current.Load()
+	if c == nil {
+		return fmt.Errorf("%w: translations are not loaded", ErrNotReady)
+	}
+
+	var errs []error
+	for _, lang := range sortedLanguages(c.loadErrors) {
+		errs = append(errs, fmt.Errorf("language %s failed to load: %w", lang, c.loadErrors[lang]))
+	}
+
+	required := t.readyLanguages
+	if !t.defaultLanguage.Empty() {
+		required = append([]LanguageID{t.defaultLanguage}, required...)
+	}
+
+	for _, lang := range required {
+		_, ok := c.languages[lang.String()]
+		if !ok && lang.Region != "" {
+			// A language with a region is served by the translations of the language.
+			_, ok = c.languages[lang.Language]
+		}
+
+		if !ok {
+			errs = append(errs, fmt.Errorf("language %s is not loaded", lang))
+		}
+	}
+
+	if t.readyMinKeys > 0 {
+		for _, languageID := range sorted.Keys(c.languages) {
+			if n := len(c.languages[languageID].index); n < t.readyMinKeys {
+				errs = append(errs, fmt.Errorf("language %s has %d keys, expected at least %d", languageID, n, t.readyMinKeys))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrNotReady, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// sortedLanguages returns the languages of the map in sorted order.
+func sortedLanguages[T any](m map[LanguageID]T) []LanguageID {
+	langs := make([]LanguageID, 0, len(m))
+	for lang := range m {
+		langs = append(langs, lang)
+	}
+
+	slices.SortFunc(langs, func(a, b LanguageID) int { return strings.Compare(a.String(), b.String()) })
+
+	return langs
+}
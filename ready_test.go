@@ -0,0 +1,37 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReady(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome", "bye": "Bye"}`), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom"}`), 0o600))
+
+	en := LanguageID{Language: "en"}
+	tr, err := NewTranslator(fs, "translations", WithDefaultLanguage(en), WithReadyLanguages(LanguageID{Language: "nl", Region: "BE"}))
+	require.NoError(t, err)
+	require.NoError(t, tr.Ready())
+
+	tr, err = NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "de"}), WithReadyLanguages(LanguageID{Language: "fr"}), WithReadyMinKeys(2))
+	require.NoError(t, err)
+
+	err = tr.Ready()
+	require.ErrorIs(t, err, ErrNotReady)
+	require.ErrorContains(t, err, "language de is not loaded")
+	require.ErrorContains(t, err, "language fr is not loaded")
+	require.ErrorContains(t, err, "language nl has 1 keys, expected at least 2")
+
+	// A language that fails to load with lenient loading.
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom :user en :User"}`), 0o600))
+
+	tr, err = NewTranslator(fs, "translations", WithLenientLoad())
+	require.NoError(t, err)
+	require.ErrorContains(t, tr.Ready(), "language nl failed to load")
+
+	require.ErrorIs(t, (&Translator{}).Ready(), ErrNotReady)
+}
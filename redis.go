@@ -0,0 +1,204 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// RedisKeyPrefix is the prefix of the Redis keys of the overrides, the keys have the pattern "i18n:<lang>:<key>", e.g. "i18n:nl:welcome".
+	RedisKeyPrefix = "i18n:"
+
+	// RedisInvalidateChannel is the Redis channel that invalidates the cached overrides.
+	// The payload is the Redis key of the changed override, an empty payload invalidates all overrides.
+	RedisInvalidateChannel = "i18n:invalidate"
+)
+
+// ErrRedisReadOnly is returned when an override is changed with a RedisClient that does not implement RedisWriter.
+var ErrRedisReadOnly = fmt.Errorf("redis client is read only")
+
+// RedisClient is the part of a Redis client that is used by RedisOverrides.
+// It is implemented with a few lines for the Redis client of your application, e.g. github.com/redis/go-redis.
+type RedisClient interface {
+	// Get returns the value of the key, ok is false if the key does not exist.
+	Get(ctx context.Context, key string) (value string, ok bool, err error)
+	// Subscribe calls fn with the payload of the messages that are published to the channel, until ctx is done.
+	Subscribe(ctx context.Context, channel string, fn func(payload string)) error
+}
+
+// RedisWriter is a RedisClient that can change the overrides, see RedisOverrides.Set.
+type RedisWriter interface {
+	RedisClient
+	// Set sets the value of the key.
+	Set(ctx context.Context, key, value string) error
+	// Del removes the key.
+	Del(ctx context.Context, key string) error
+	// Publish publishes the payload to the channel.
+	Publish(ctx context.Context, channel, payload string) error
+}
+
+// RedisOverrides is an OverrideSource for overrides that are stored in Redis with the key pattern "i18n:<lang>:<key>".
+// The override of the language with region, e.g. "i18n:nl-BE:welcome", takes precedence over the override of the language.
+//
+// Overrides, and keys without an override, are cached for the ttl. Publish the Redis key to RedisInvalidateChannel after
+// changing an override to update all instances at once, see Listen:
+//
+//	SET i18n:nl:welcome "Welkom!"
+//	PUBLISH i18n:invalidate i18n:nl:welcome
+//
+// Set and Delete do both with a RedisWriter.
+type RedisOverrides struct {
+	client RedisClient
+	ttl    time.Duration
+
+	// Mu protects the cache and the audit hook.
+	mu    sync.RWMutex
+	cache map[string]redisOverride
+
+	// Audit receives the changed overrides, see SetAuditHook.
+	audit AuditHook
+}
+
+var _ OverrideSource = (*RedisOverrides)(nil)
+
+// redisOverride is a cached override, ok is false if the key has no override.
+type redisOverride struct {
+	value   string
+	ok      bool
+	expires time.Time
+}
+
+// NewRedisOverrides returns the overrides in Redis, they are cached for the ttl.
+func NewRedisOverrides(client RedisClient, ttl time.Duration) *RedisOverrides {
+	return &RedisOverrides{
+		client: client,
+		ttl:    ttl,
+		cache:  make(map[string]redisOverride),
+	}
+}
+
+// RedisKey returns the Redis key of the override of key in the language.
+func RedisKey(lang LanguageID, key Key) string {
+	return RedisKeyPrefix + lang.String() + ":" + string(key)
+}
+
+func (o *RedisOverrides) Override(ctx context.Context, lang LanguageID, key Key) (string, bool) {
+	if lang.Region != "" {
+		if value, ok := o.get(ctx, RedisKey(lang, key)); ok {
+			return value, true
+		}
+	}
+
+	return o.get(ctx, RedisKey(LanguageID{Language: lang.Language}, key))
+}
+
+// get returns the cached override of the Redis key, the override is loaded from Redis when it is not cached or has expired.
+// Errors are not cached, e.g. the error of a cancelled ctx would hide the override for the other requests.
+// The expired override is used when Redis fails, the key is not overridden when it was not cached.
+func (o *RedisOverrides) get(ctx context.Context, redisKey string) (string, bool) {
+	now := time.Now()
+
+	o.mu.RLock()
+	cached, ok := o.cache[redisKey]
+	o.mu.RUnlock()
+
+	if ok && now.Before(cached.expires) {
+		return cached.value, cached.ok
+	}
+
+	value, found, err := o.client.Get(ctx, redisKey)
+	if err != nil {
+		return cached.value, cached.ok
+	}
+
+	o.mu.Lock()
+	o.cache[redisKey] = redisOverride{value: value, ok: found, expires: now.Add(o.ttl)}
+	o.mu.Unlock()
+
+	return value, found
+}
+
+// Invalidate removes the override of the Redis key from the cache, all overrides are removed when the key is empty.
+func (o *RedisOverrides) Invalidate(redisKey string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if redisKey == "" {
+		o.cache = make(map[string]redisOverride)
+		return
+	}
+
+	delete(o.cache, redisKey)
+}
+
+// SetAuditHook calls the hook when Set or Delete changes an override, the actor of the event is the actor of the ctx, see WithActor.
+// Only the instance that writes the override emits the event, changes that are made outside the application are not audited.
+func (o *RedisOverrides) SetAuditHook(hook AuditHook) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.audit = hook
+}
+
+// auditHook returns the hook of SetAuditHook.
+func (o *RedisOverrides) auditHook() AuditHook {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return o.audit
+}
+
+// Listen invalidates the cached overrides that are published to RedisInvalidateChannel, until ctx is done.
+func (o *RedisOverrides) Listen(ctx context.Context) error {
+	return o.client.Subscribe(ctx, RedisInvalidateChannel, func(payload string) {
+		o.Invalidate(strings.TrimSpace(payload))
+	})
+}
+
+// Set sets the override of key in the language and publishes the change to RedisInvalidateChannel.
+// It returns ErrRedisReadOnly if the client does not implement RedisWriter.
+func (o *RedisOverrides) Set(ctx context.Context, lang LanguageID, key Key, value string) error {
+	return o.write(ctx, lang, key, value, func(w RedisWriter, redisKey string) error {
+		return w.Set(ctx, redisKey, value)
+	})
+}
+
+// Delete removes the override of key in the language and publishes the change to RedisInvalidateChannel.
+// It returns ErrRedisReadOnly if the client does not implement RedisWriter.
+func (o *RedisOverrides) Delete(ctx context.Context, lang LanguageID, key Key) error {
+	return o.write(ctx, lang, key, "", func(w RedisWriter, redisKey string) error {
+		return w.Del(ctx, redisKey)
+	})
+}
+
+// write changes the override with fn and calls the audit hook. The old value is read from Redis, not from the cache,
+// so a change of an override that this instance did not read is audited as a change.
+func (o *RedisOverrides) write(ctx context.Context, lang LanguageID, key Key, value string, fn func(w RedisWriter, redisKey string) error) error {
+	writer, ok := o.client.(RedisWriter)
+	if !ok {
+		return ErrRedisReadOnly
+	}
+
+	redisKey := RedisKey(lang, key)
+	old, _, err := writer.Get(ctx, redisKey)
+	if err != nil {
+		return fmt.Errorf("reading override %s: %w", redisKey, err)
+	}
+
+	if err := fn(writer, redisKey); err != nil {
+		return fmt.Errorf("writing override %s: %w", redisKey, err)
+	}
+
+	// The override has changed in Redis, it is audited also when the change can not be published.
+	audit(ctx, o.auditHook(), "redis", lang, key, old, value)
+
+	o.Invalidate(redisKey)
+	if err := writer.Publish(ctx, RedisInvalidateChannel, redisKey); err != nil {
+		return fmt.Errorf("publishing override %s: %w", redisKey, err)
+	}
+
+	return nil
+}
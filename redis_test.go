@@ -0,0 +1,271 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRedis struct {
+	mu          sync.Mutex
+	values      map[string]string
+	gets        int
+	err         error
+	subscribers []func(payload string)
+}
+
+func (r *fakeRedis) Get(ctx context.Context, key string) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gets++
+	if r.err != nil {
+		return "", false, r.err
+	}
+
+	value, ok := r.values[key]
+	return value, ok, nil
+}
+
+func (r *fakeRedis) Subscribe(ctx context.Context, channel string, fn func(payload string)) error {
+	r.mu.Lock()
+	r.subscribers = append(r.subscribers, fn)
+	r.mu.Unlock()
+
+	<-ctx.Done()
+	return nil
+}
+
+func (r *fakeRedis) publish(payload string) {
+	r.mu.Lock()
+	subscribers := append([]func(payload string){}, r.subscribers...)
+	r.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(payload)
+	}
+}
+
+// fakeRedisWriter is a fakeRedis that can write, it publishes to the subscribers of the fakeRedis.
+type fakeRedisWriter struct {
+	*fakeRedis
+	publishErr error
+}
+
+func (r *fakeRedisWriter) Set(ctx context.Context, key, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.values[key] = value
+	return nil
+}
+
+func (r *fakeRedisWriter) Del(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.values, key)
+	return nil
+}
+
+func (r *fakeRedisWriter) Publish(ctx context.Context, channel, payload string) error {
+	if r.publishErr != nil {
+		return r.publishErr
+	}
+
+	r.publish(payload)
+	return nil
+}
+
+func TestRedisOverrides(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome :name", "bye": "Bye"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom :name", "bye": "Doei"}`), 0o644))
+
+	redis := &fakeRedis{values: map[string]string{
+		"i18n:nl:welcome":    "Hallo :Name",
+		"i18n:en-GB:welcome": "Cheers :name",
+		"i18n:en:bye":        "Goodbye :name|unknown(1)",
+	}}
+	overrides := NewRedisOverrides(redis, time.Hour)
+
+	tr, err := NewTranslator(fs, "translations", WithOverrides(overrides))
+	require.NoError(t, err)
+
+	nl := ToCtx(context.Background(), "nl")
+	en := ToCtx(context.Background(), "en")
+	gb := ToCtx(context.Background(), "en-GB")
+	replacements := map[string]any{"name": "jan"}
+
+	require.Equal(t, "Hallo Jan", tr.Translate(nl, "welcome", replacements))
+	require.Equal(t, "HALLO JAN", tr.Translate(nl, "welcome!upper", replacements))
+	require.Equal(t, "Doei", tr.Translate(nl, "bye", nil))
+	require.Equal(t, "Cheers jan", tr.Translate(gb, "welcome", replacements))
+	require.Equal(t, "Welcome jan", tr.Translate(en, "welcome", replacements))
+
+	// An override with an unknown modifier is ignored.
+	require.Equal(t, "Bye", tr.Translate(en, "bye", nil))
+
+	// The overrides are cached.
+	gets := redis.gets
+	require.Equal(t, "Hallo Jan", tr.Translate(nl, "welcome", replacements))
+	require.Equal(t, gets, redis.gets)
+
+	// The compiled override is reused until the override changes.
+	msgs, region := tr.messages(nl)
+	overridden := tr.override(nl, msgs, region, "welcome")
+	require.NotSame(t, msgs, overridden)
+	require.Same(t, overridden, tr.override(nl, msgs, region, "welcome"))
+
+	// The compiled overrides are dropped with the messages when the translations are reloaded.
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom :name", "bye": "Dag"}`), 0o644))
+	require.NoError(t, tr.Reload(context.Background()))
+	reloaded, _ := tr.messages(nl)
+	require.NotSame(t, msgs, reloaded)
+	_, ok := reloaded.overrideCache.Load(overrideCacheKey{lang: LanguageID{Language: "nl"}, key: "welcome"})
+	require.False(t, ok)
+	require.NotSame(t, overridden, tr.override(nl, reloaded, region, "welcome"))
+	require.Equal(t, "Dag", tr.Translate(nl, "bye", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		require.NoError(t, overrides.Listen(ctx))
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		redis.mu.Lock()
+		defer redis.mu.Unlock()
+		return len(redis.subscribers) == 1
+	}, time.Second, time.Millisecond)
+
+	redis.mu.Lock()
+	redis.values["i18n:nl:welcome"] = "Goedendag :name"
+	redis.mu.Unlock()
+	redis.publish("i18n:nl:welcome")
+	require.Equal(t, "Goedendag jan", tr.Translate(nl, "welcome", replacements))
+
+	// The translations are used when Redis fails.
+	redis.mu.Lock()
+	redis.err = errors.New("connection refused")
+	redis.mu.Unlock()
+	redis.publish("")
+	require.Equal(t, "Welkom jan", tr.Translate(nl, "welcome", replacements))
+
+	// Failures are not cached, the override is used again when Redis recovers.
+	redis.mu.Lock()
+	redis.err = nil
+	redis.mu.Unlock()
+	require.Equal(t, "Goedendag jan", tr.Translate(nl, "welcome", replacements))
+
+	cancel()
+	<-done
+}
+
+func TestRedisOverridesAudit(t *testing.T) {
+	redis := &fakeRedis{values: map[string]string{"i18n:nl:welcome": "Hallo"}}
+	redisWriter := &fakeRedisWriter{fakeRedis: redis}
+	writer := NewRedisOverrides(redisWriter, time.Hour)
+	listener := NewRedisOverrides(redis, time.Hour)
+
+	var events []AuditEvent
+	hook := func(ctx context.Context, event AuditEvent) {
+		events = append(events, event)
+	}
+	writer.SetAuditHook(hook)
+	listener.SetAuditHook(hook)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		require.NoError(t, listener.Listen(ctx))
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		redis.mu.Lock()
+		defer redis.mu.Unlock()
+		return len(redis.subscribers) == 1
+	}, time.Second, time.Millisecond)
+
+	nl := LanguageID{Language: "nl"}
+	value, ok := listener.Override(context.Background(), nl, "welcome")
+	require.True(t, ok)
+	require.Equal(t, "Hallo", value)
+
+	// The old message is read from Redis, the writer did not read the override before.
+	require.NoError(t, writer.Set(WithActor(context.Background(), "jan@example.com"), nl, "welcome", "Goedendag"))
+
+	// The listener invalidated its cache, but only the writer emits the event.
+	value, ok = listener.Override(context.Background(), nl, "welcome")
+	require.True(t, ok)
+	require.Equal(t, "Goedendag", value)
+
+	// Unchanged overrides are not audited.
+	require.NoError(t, writer.Set(context.Background(), nl, "welcome", "Goedendag"))
+	require.NoError(t, writer.Delete(context.Background(), nl, "welcome"))
+
+	cancel()
+	<-done
+
+	require.Len(t, events, 2)
+	require.Equal(t, "redis", events[0].Source)
+	require.Equal(t, nl, events[0].Language)
+	require.Equal(t, Key("welcome"), events[0].Key)
+	require.Equal(t, "Hallo", events[0].Old)
+	require.Equal(t, "Goedendag", events[0].New)
+	require.Equal(t, "jan@example.com", events[0].Actor)
+	require.Equal(t, "Goedendag", events[1].Old)
+	require.Empty(t, events[1].New)
+
+	_, ok = listener.Override(context.Background(), nl, "welcome")
+	require.False(t, ok)
+
+	// A client that can not write returns an error.
+	require.ErrorIs(t, listener.Set(context.Background(), nl, "welcome", "Hoi"), ErrRedisReadOnly)
+
+	// A change that can not be published is audited, it has changed in Redis.
+	redisWriter.publishErr = errors.New("connection reset")
+	require.ErrorIs(t, writer.Set(context.Background(), nl, "welcome", "Hoi"), redisWriter.publishErr)
+	require.Len(t, events, 3)
+	require.Equal(t, "Hoi", events[2].New)
+}
+
+func TestRedisOverridesExpiredOnError(t *testing.T) {
+	redis := &fakeRedis{values: map[string]string{"i18n:nl:welcome": "Hallo"}}
+	overrides := NewRedisOverrides(redis, time.Millisecond)
+	nl := LanguageID{Language: "nl"}
+
+	value, ok := overrides.Override(context.Background(), nl, "welcome")
+	require.True(t, ok)
+	require.Equal(t, "Hallo", value)
+
+	// The expired override is used when Redis fails, e.g. when the ctx of the request is cancelled.
+	time.Sleep(2 * time.Millisecond)
+	redis.mu.Lock()
+	redis.err = context.Canceled
+	redis.mu.Unlock()
+
+	value, ok = overrides.Override(context.Background(), nl, "welcome")
+	require.True(t, ok)
+	require.Equal(t, "Hallo", value)
+
+	// A key that was not cached is not overridden, and the failure is not cached.
+	_, ok = overrides.Override(context.Background(), nl, "bye")
+	require.False(t, ok)
+
+	redis.mu.Lock()
+	redis.err = nil
+	redis.values["i18n:nl:bye"] = "Doei"
+	redis.mu.Unlock()
+
+	value, ok = overrides.Override(context.Background(), nl, "bye")
+	require.True(t, ok)
+	require.Equal(t, "Doei", value)
+}
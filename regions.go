@@ -0,0 +1,35 @@
+package messages
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// Region returns the name of the country or region in the language of the ctx, e.g. "Nederland" for "NL" in Dutch.
+// Code is an ISO 3166-1 alpha-2 or UN M.49 code, the code is returned when it is unknown.
+func (t *Translator) Region(ctx context.Context, code string) string {
+	return regionName(t.tag(ctx), code)
+}
+
+// regionName returns the name of the region in the language, the code is returned when it is unknown.
+func regionName(lang language.Tag, code string) string {
+	region, err := language.ParseRegion(strings.TrimSpace(code))
+	if err != nil {
+		return code
+	}
+
+	name := display.Regions(lang).Name(region)
+	if name == "" {
+		return code
+	}
+
+	return name
+}
+
+// regionModifier formats an ISO country code as the name of the country, e.g. ":country|region".
+func regionModifier(lang language.Tag, value any, _ string) string {
+	return regionName(lang, formatReplacement(value))
+}
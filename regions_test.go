@@ -0,0 +1,32 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegion(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"ships_to": "Ships to :country|region"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"ships_to": "Verzending naar :country|region"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/de.json", []byte(`{}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+
+	en := ToCtx(context.Background(), "en")
+	nl := ToCtx(context.Background(), "nl")
+	de := ToCtx(context.Background(), "de")
+
+	require.Equal(t, "Netherlands", tr.Region(en, "NL"))
+	require.Equal(t, "Nederland", tr.Region(nl, "NL"))
+	require.Equal(t, "Duitsland", tr.Region(nl, "de"))
+	require.Equal(t, "Belgien", tr.Region(de, "BE"))
+	require.Equal(t, "XX!", tr.Region(nl, "XX!"))
+
+	require.Equal(t, "Ships to Belgium", tr.Translate(en, "ships_to", map[string]any{"country": "BE"}))
+	require.Equal(t, "Verzending naar België", tr.Translate(nl, "ships_to", map[string]any{"country": "BE"}))
+}
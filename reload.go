@@ -0,0 +1,447 @@
+package messages
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/wvell/messages/internal/sorted"
+)
+
+var (
+	// ErrNotModified is returned by a Loader when the translations have not changed since the given version.
+	ErrNotModified = fmt.Errorf("translations not modified")
+)
+
+// Version identifies a version of the translations of a Loader.
+type Version struct {
+	// ETag changes when the translations change, e.g. a hash of the files or the ETag header of a remote catalog.
+	ETag string
+	// LastModified is the time the translations were last changed, it is zero if it is unknown.
+	LastModified time.Time
+}
+
+// Loader loads the translations of all languages from a backend.
+type Loader interface {
+	// Load returns the messages of all languages and their version.
+	// ErrNotModified is returned when the version of the translations is the same as since, so a reload is cheap when nothing has changed.
+	// LanguageErrors is returned with the messages and the version when some languages can not be loaded, see WithLenientLoad.
+	Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error)
+}
+
+// MetadataLoader is a Loader that also loads the metadata of the keys, e.g. FileStore reads the metadata file of its directory.
+// NewTranslatorFromLoader uses the metadata for WithTypeCheck, Describe, Preview, ByCode and the timed variants, the metadata of
+// other loaders is empty unless it is set with WithMetadata.
+type MetadataLoader interface {
+	Loader
+	// Metadata returns the metadata of the keys, it is called after every Load that returns messages.
+	Metadata(ctx context.Context) (Metadata, error)
+}
+
+// LanguageErrors holds the errors of the languages that a Loader can not load, e.g. a translation file with a typo.
+type LanguageErrors map[LanguageID]error
+
+func (e LanguageErrors) Error() string {
+	var msgs []string
+	for _, err := range e.Unwrap() {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of the languages sorted by language, so errors.Is finds the cause of every language.
+func (e LanguageErrors) Unwrap() []error {
+	languages := maps.Keys(e)
+	slices.SortFunc(languages, func(a, b LanguageID) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+
+	errs := make([]error, 0, len(e))
+	for _, lang := range languages {
+		errs = append(errs, e[lang])
+	}
+
+	return errs
+}
+
+// catalog holds the loaded translations of a Translator.
+type catalog struct {
+	languages map[string]*messages
+	// Metadata of the keys, see MetadataFile.
+	metadata Metadata
+	// Codes holds the keys of the error codes in the metadata, see ByCode.
+	codes map[string]Key
+	// TimedVariants holds the variants with a valid from or valid until time by key, see KeyMetadata.ValidFrom.
+	timedVariants map[Key][]timedVariant
+	version       Version
+	// LoadErrors holds the errors of the languages that failed to load, see WithLenientLoad.
+	loadErrors map[LanguageID]error
+	// LastReload is the time of the last successful reload, also if the translations were not modified.
+	lastReload time.Time
+}
+
+// loadFunc loads and prepares the translations, ErrNotModified is returned when they have not changed since the version.
+type loadFunc func(ctx context.Context, since Version) (*catalog, error)
+
+// NewTranslatorFromLoader loads the translations from the loader and returns a new Translator.
+// Use Reload to reload the translations when they have changed. The metadata of the keys is read from a MetadataLoader,
+// or set with WithMetadata. Without metadata WithTypeCheck, Describe, Preview, ByCode and the timed variants have nothing to work with.
+func NewTranslatorFromLoader(ctx context.Context, loader Loader, opts ...Opt) (*Translator, error) {
+	t := newTranslator(opts...)
+
+	t.load = func(ctx context.Context, since Version) (*catalog, error) {
+		languages, version, err := loader.Load(ctx, since)
+		var errs LanguageErrors
+		if err != nil && (!t.lenientLoad || !errors.As(err, &errs)) {
+			return nil, err
+		}
+
+		// A new parser is used for every load, so the interned strings of old translations are not kept.
+		parser := NewParser(nil, t.parserOpts...)
+
+		layers, err := t.loadLayers(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		c := &catalog{languages: make(map[string]*messages), loadErrors: make(map[LanguageID]error), metadata: Metadata{}, version: version}
+		for lang, raw := range languages {
+			messages, err := parser.parseLayeredMessages(lang.String(), lang.String(), layerFor(layers, lang.String()), raw)
+			if err == nil {
+				err = t.addLanguage(c.languages, lang.String(), messages)
+			}
+
+			if err != nil {
+				err = t.isolateLoadError(c, lang.String(), fmt.Errorf("language %s: %w", lang, err))
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		for lang, err := range errs {
+			if err := t.isolateLoadError(c, lang.String(), fmt.Errorf("language %s: %w", lang, err)); err != nil {
+				return nil, err
+			}
+		}
+
+		metadata := t.metadata
+		if metadataLoader, ok := loader.(MetadataLoader); ok && metadata == nil {
+			metadata, err = metadataLoader.Metadata(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("loading metadata: %w", err)
+			}
+		}
+
+		if err := c.setMetadata(metadata); err != nil {
+			return nil, err
+		}
+
+		return c, nil
+	}
+
+	err := t.Reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// NewTranslatorFromRaw returns a new Translator for the messages by language, e.g. catalogs that are read from a database.
+// The messages are parsed and compiled like the translation files of NewTranslator, invalid messages are returned as an error.
+// Reload does not change the messages, use NewTranslatorFromLoader for messages that change. Set the metadata with WithMetadata.
+func NewTranslatorFromRaw(languages map[LanguageID]*RawMessages, opts ...Opt) (*Translator, error) {
+	return NewTranslatorFromLoader(context.Background(), rawLoader(languages), opts...)
+}
+
+// rawLoader is the Loader of NewTranslatorFromRaw, the messages never change.
+type rawLoader map[LanguageID]*RawMessages
+
+func (l rawLoader) Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error) {
+	version := Version{ETag: "raw"}
+	if since.ETag == version.ETag {
+		return nil, version, ErrNotModified
+	}
+
+	languages := make(map[LanguageID]*RawMessages, len(l))
+	for lang, raw := range l {
+		if raw == nil {
+			raw = &RawMessages{}
+		}
+		languages[lang] = raw
+	}
+
+	return languages, version, nil
+}
+
+// Reload reloads the translations when they have changed since the last reload.
+// Reloading is cheap when nothing has changed: the translation files are only parsed again when their modification time or size has changed,
+// and remote loaders use the ETag of the last response.
+// The translations are replaced at once, a Translate call uses either the old or the new translations.
+// The current translations are kept when the reload fails.
+func (t *Translator) Reload(ctx context.Context) error {
+	t.reloadMu.Lock()
+	defer t.reloadMu.Unlock()
+
+	current := t.current.Load()
+
+	var since Version
+	if current != nil {
+		since = current.version
+	}
+
+	if t.loadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.loadTimeout)
+		defer cancel()
+	}
+
+	c, err := t.load(ctx, since)
+	if errors.Is(err, ErrNotModified) && current != nil {
+		updated := *current
+		updated.lastReload = time.Now()
+		t.current.Store(&updated)
+
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	t.linkDefaultLanguage(c.languages)
+	c.lastReload = time.Now()
+	t.current.Store(c)
+
+	return nil
+}
+
+// WithLoadTimeout limits the time of a load, a load that takes longer fails with context.DeadlineExceeded and the current translations are kept.
+// The deadline of the ctx of NewTranslatorFromLoader and Reload is used when it is earlier.
+func WithLoadTimeout(timeout time.Duration) Opt {
+	return func(t *Translator) {
+		t.loadTimeout = timeout
+	}
+}
+
+// WithBackgroundRefresh reloads the translations in the background when a translation is requested and the translations were
+// reloaded more than maxAge ago. The current translations are served while they are reloaded, so a slow loader never blocks a request.
+// One reload runs at a time and a failed reload is retried after maxAge, the errors are passed to onError.
+// The reloads do not use the ctx of the request, use WithLoadTimeout to limit their time.
+func WithBackgroundRefresh(maxAge time.Duration, onError func(error)) Opt {
+	return func(t *Translator) {
+		t.refreshMaxAge = maxAge
+		t.refreshOnError = onError
+	}
+}
+
+// refreshIfStale starts a background reload when the last reload attempt is older than the max age, see WithBackgroundRefresh.
+func (t *Translator) refreshIfStale() {
+	if t.refreshMaxAge <= 0 {
+		return
+	}
+
+	// The age is the time since the last successful reload or the last attempt, so a failing loader is not retried on every translation.
+	now := time.Now()
+	last := time.Unix(0, t.lastRefresh.Load())
+	if c := t.current.Load(); c != nil && c.lastReload.After(last) {
+		last = c.lastReload
+	}
+
+	if now.Sub(last) < t.refreshMaxAge || !t.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	t.lastRefresh.Store(now.UnixNano())
+
+	go func() {
+		defer t.refreshing.Store(false)
+
+		if err := t.Reload(context.Background()); err != nil && t.refreshOnError != nil {
+			t.refreshOnError(err)
+		}
+	}()
+}
+
+// WithLenientLoad loads the languages that are valid when other languages fail to load, instead of failing NewTranslator or Reload.
+// A language that fails to load is served from the previous load if it loaded before, otherwise the fallback languages are used,
+// see WithDefaultLanguage. The errors are returned by LoadErrors.
+// Errors that affect all languages, like a translations directory that can not be read, still fail the load.
+func WithLenientLoad() Opt {
+	return func(t *Translator) {
+		t.lenientLoad = true
+	}
+}
+
+// WithStrictLoad fails NewTranslator and Reload when a language fails to load. This is the default.
+// Use it with WithLenientLoad to choose the behavior per environment, the last option wins:
+//
+//	opts := []messages.Opt{messages.WithStrictLoad()}
+//	if env == "production" {
+//		opts = append(opts, messages.WithLenientLoad())
+//	}
+func WithStrictLoad() Opt {
+	return func(t *Translator) {
+		t.lenientLoad = false
+	}
+}
+
+// LoadErrors returns the errors of the languages that failed to load in the last load, see WithLenientLoad.
+func (t *Translator) LoadErrors() map[LanguageID]error {
+	errs := make(map[LanguageID]error)
+
+	c := t.current.Load()
+	if c == nil {
+		return errs
+	}
+
+	for lang, err := range c.loadErrors {
+		errs[lang] = err
+	}
+
+	return errs
+}
+
+// isolateLoadError records the error of a language that failed to load when lenient loading is enabled, otherwise the error is returned.
+// The messages of the previous load are kept for the language.
+func (t *Translator) isolateLoadError(c *catalog, languageID string, err error) error {
+	if !t.lenientLoad {
+		return err
+	}
+
+	lang, parseErr := ParseLanguage(languageID)
+	if parseErr != nil {
+		return err
+	}
+
+	c.loadErrors[lang] = err
+
+	if previous := t.current.Load(); previous != nil {
+		// The messages are copied, because the fallback is linked again while the previous catalog can still be in use.
+		if messages, ok := previous.languages[languageID]; ok {
+			copied := *messages
+			copied.overrideCache = &sync.Map{}
+			c.languages[languageID] = &copied
+		}
+	}
+
+	return nil
+}
+
+// Watch reloads the translations every interval until ctx is done.
+// The errors of failed reloads are passed to onError, the current translations are kept when a reload fails.
+func (t *Translator) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Reload(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// LastReload returns the time of the last successful reload, also when the translations were not modified.
+func (t *Translator) LastReload() time.Time {
+	c := t.current.Load()
+	if c == nil {
+		return time.Time{}
+	}
+
+	return c.lastReload
+}
+
+// LastModified returns the time the loaded translations were last changed, it is zero if the loader does not know it.
+func (t *Translator) LastModified() time.Time {
+	c := t.current.Load()
+	if c == nil {
+		return time.Time{}
+	}
+
+	return c.version.LastModified
+}
+
+// Load loads the translation files in the directory of the store.
+// The ETag of the version is a hash of the names, sizes and modification times of the files, the files are not read when it has not changed.
+func (s *FileStore) Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error) {
+	version, err := s.version()
+	if err != nil {
+		return nil, Version{}, err
+	}
+
+	if version.ETag == since.ETag {
+		return nil, version, ErrNotModified
+	}
+
+	languages, err := rawMessagesFromDir(s.parser, s.dir)
+	var errs LanguageErrors
+	if err != nil && !errors.As(err, &errs) {
+		return nil, Version{}, err
+	}
+
+	return languages, version, err
+}
+
+// Metadata reads the metadata file of the directory, see MetadataLoader.
+func (s *FileStore) Metadata(ctx context.Context) (Metadata, error) {
+	return s.parser.MetadataFromDir(s.dir)
+}
+
+// version returns the version of the translation files and the metadata file in the directory of the store.
+func (s *FileStore) version() (Version, error) {
+	files, err := s.parser.TranslationFilesFromDir(s.dir)
+	if err != nil {
+		return Version{}, fmt.Errorf("reading translations files: %w", err)
+	}
+
+	names := sorted.Keys(files)
+	// The archives and the overlays are part of the version, so changes are seen by translators that serve them.
+	for _, name := range sorted.Keys(files) {
+		names = append(names, name+ArchiveSuffix)
+		files[name+ArchiveSuffix] = archiveFileOf(files[name])
+
+		if s.parser.environment != "" {
+			names = append(names, name+"."+s.parser.environment)
+			files[name+"."+s.parser.environment] = overlayFile(files[name], s.parser.environment)
+		}
+	}
+	names = append(names, MetadataFile)
+	files[MetadataFile] = filepath.Join(s.dir, MetadataFile)
+
+	var version Version
+	hash := sha256.New()
+	for _, name := range names {
+		stat, err := s.fs.Stat(files[name])
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Version{}, fmt.Errorf("getting file info: %w", err)
+		}
+
+		fmt.Fprintf(hash, "%s %d %d\n", files[name], stat.Size(), stat.ModTime().UnixNano())
+		if stat.ModTime().After(version.LastModified) {
+			version.LastModified = stat.ModTime()
+		}
+	}
+
+	version.ETag = hex.EncodeToString(hash.Sum(nil))
+	return version, nil
+}
@@ -0,0 +1,313 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome"}`), 0o644))
+
+	parses := 0
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+
+	// Count the parses with a wrapped loader.
+	load := tr.load
+	tr.load = func(ctx context.Context, since Version) (*catalog, error) {
+		c, err := load(ctx, since)
+		if err == nil {
+			parses++
+		}
+		return c, err
+	}
+
+	ctx := ToCtx(context.Background(), "en")
+	require.Equal(t, "Welcome", tr.Translate(ctx, "welcome", nil))
+
+	firstReload := tr.LastReload()
+	require.False(t, firstReload.IsZero())
+	require.False(t, tr.LastModified().IsZero())
+
+	// Nothing has changed.
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, 0, parses)
+	require.False(t, tr.LastReload().Before(firstReload))
+
+	// The file has changed.
+	modified := time.Now().Add(time.Minute)
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Hello"}`), 0o644))
+	require.NoError(t, fs.Chtimes("translations/en.json", modified, modified))
+
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, 1, parses)
+	require.Equal(t, "Hello", tr.Translate(ctx, "welcome", nil))
+	require.True(t, tr.LastModified().Equal(modified))
+
+	// An invalid file keeps the current translations.
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": `), 0o644))
+	require.Error(t, tr.Reload(context.Background()))
+	require.Equal(t, "Hello", tr.Translate(ctx, "welcome", nil))
+}
+
+func TestHTTPLoader(t *testing.T) {
+	requests, downloads := 0, 0
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		_, _ = w.Write([]byte(`{"en": {"welcome": "Welcome :User"}, "nl_NL": {"welcome": "Welkom :User"}}`))
+	}))
+	defer server.Close()
+
+	tr, err := NewTranslatorFromLoader(context.Background(), NewHTTPLoader(server.URL, server.Client()))
+	require.NoError(t, err)
+
+	require.Equal(t, "Welkom Jan", tr.Translate(ToCtx(context.Background(), "nl-NL"), "welcome", map[string]any{"user": "jan"}))
+	require.True(t, tr.LastModified().Equal(lastModified))
+
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Equal(t, 2, requests)
+	require.Equal(t, 1, downloads)
+	require.Equal(t, "Welcome Jan", tr.Translate(ToCtx(context.Background(), "en"), "welcome", map[string]any{"user": "jan"}))
+}
+
+func TestFileStoreLoad(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en_GB.json", []byte(`{"color": "colour"}`), 0o644))
+
+	store := NewFileStore(fs, "translations")
+
+	tr, err := NewTranslatorFromLoader(context.Background(), store)
+	require.NoError(t, err)
+	require.Equal(t, "colour", tr.Translate(ToCtx(context.Background(), "en-GB"), "color", nil))
+
+	_, version, err := store.Load(context.Background(), Version{})
+	require.NoError(t, err)
+
+	_, _, err = store.Load(context.Background(), version)
+	require.ErrorIs(t, err, ErrNotModified)
+}
+
+func TestLoaderMetadata(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"not_found": "Not found"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(`{"not_found": {"description": "Error page", "code": "E404"}}`), 0o644))
+
+	ctx := ToCtx(context.Background(), "en")
+
+	// A FileStore loads the metadata file.
+	tr, err := NewTranslatorFromLoader(context.Background(), NewFileStore(fs, "translations"))
+	require.NoError(t, err)
+	require.Equal(t, "Error page", tr.Describe("not_found").Description)
+	message, ok := tr.ByCode(ctx, "E404", nil)
+	require.True(t, ok)
+	require.Equal(t, "Not found", message)
+
+	// The metadata of the option replaces the metadata of the loader.
+	raw := map[LanguageID]*RawMessages{{Language: "en"}: {Messages: map[string]string{"not_found": "Not found"}}}
+	tr, err = NewTranslatorFromRaw(raw, WithMetadata(Metadata{"not_found": {Code: "E4040"}}))
+	require.NoError(t, err)
+	_, ok = tr.ByCode(ctx, "E404", nil)
+	require.False(t, ok)
+	message, ok = tr.ByCode(ctx, "E4040", nil)
+	require.True(t, ok)
+	require.Equal(t, "Not found", message)
+
+	// Other loaders have no metadata.
+	tr, err = NewTranslatorFromRaw(raw)
+	require.NoError(t, err)
+	_, ok = tr.ByCode(ctx, "E404", nil)
+	require.False(t, ok)
+
+	_, err = NewTranslatorFromRaw(raw, WithMetadata(Metadata{"not_found": {Placeholders: PlaceholderTypes{"count": "complex"}}}))
+	require.Error(t, err)
+}
+
+func TestNewTranslatorFromRaw(t *testing.T) {
+	en, nl := LanguageID{Language: "en"}, LanguageID{Language: "nl"}
+	tr, err := NewTranslatorFromRaw(map[LanguageID]*RawMessages{
+		en: {
+			Messages:   map[string]string{"welcome": "Welcome :User", "required": ":Attribute is required", "cats[one]": "One cat", "cats[other]": ":count cats"},
+			Attributes: map[string]string{"email": "e-mail address"},
+		},
+		nl: {Messages: map[string]string{"welcome": "Welkom :user"}},
+	}, WithDefaultLanguage(en))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.Equal(t, "Welcome Jan", tr.Translate(ctx, "welcome", map[string]any{"user": "jan"}))
+	require.Equal(t, "E-mail address is required", tr.Translate(ctx, "required", map[string]any{"attribute": "email"}))
+	require.Equal(t, "3 cats", tr.TranslatePlural(ctx, "cats", 3, nil))
+	require.Equal(t, "Welkom jan", tr.Translate(ToCtx(ctx, "nl"), "welcome", map[string]any{"user": "jan"}))
+
+	require.NoError(t, tr.Reload(ctx))
+	require.Equal(t, "Welkom jan", tr.Translate(ToCtx(ctx, "nl"), "welcome", map[string]any{"user": "jan"}))
+
+	_, err = NewTranslatorFromRaw(map[LanguageID]*RawMessages{en: {Messages: map[string]string{"welcome": "Welcome :user and :User"}}})
+	require.ErrorIs(t, err, ErrDuplicateReplacementWithDifferentCase)
+
+	tr, err = NewTranslatorFromRaw(map[LanguageID]*RawMessages{en: nil}, WithDefaultLanguage(en))
+	require.NoError(t, err)
+	require.Equal(t, "welcome", tr.Translate(ctx, "welcome", nil))
+}
+
+func TestLenientLoad(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/pt.json", []byte(`{"welcome": `), 0o644))
+
+	// By default a broken language fails the load.
+	_, err := NewTranslator(fs, "translations")
+	require.Error(t, err)
+
+	// The last option wins.
+	_, err = NewTranslator(fs, "translations", WithLenientLoad(), WithStrictLoad())
+	require.Error(t, err)
+
+	_, err = NewTranslator(fs, "translations", WithStrictLoad(), WithLenientLoad())
+	require.NoError(t, err)
+
+	en := LanguageID{Language: "en"}
+	tr, err := NewTranslator(fs, "translations", WithLenientLoad(), WithDefaultLanguage(en))
+	require.NoError(t, err)
+
+	errs := tr.LoadErrors()
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[LanguageID{Language: "pt"}], "pt.json")
+
+	// The broken language uses the default language.
+	require.Equal(t, "Welcome", tr.Translate(ToCtx(context.Background(), "pt"), "welcome", nil))
+	require.Equal(t, "Welkom", tr.Translate(ToCtx(context.Background(), "nl"), "welcome", nil))
+
+	// A language that breaks after it loaded keeps the previous messages.
+	modified := time.Now().Add(time.Minute)
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom :user en :User"}`), 0o644))
+	require.NoError(t, fs.Chtimes("translations/nl.json", modified, modified))
+
+	require.NoError(t, tr.Reload(context.Background()))
+	require.Len(t, tr.LoadErrors(), 2)
+	require.ErrorIs(t, tr.LoadErrors()[LanguageID{Language: "nl"}], ErrDuplicateReplacementWithDifferentCase)
+	require.Equal(t, "Welkom", tr.Translate(ToCtx(context.Background(), "nl"), "welcome", nil))
+}
+
+func TestLenientLoadFromLoader(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/pt.json", []byte(`{"welcome": `), 0o644))
+
+	en, pt := LanguageID{Language: "en"}, LanguageID{Language: "pt"}
+	packs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(packs, "packs/pt"+PackSuffix, []byte("broken"), 0o644))
+
+	for name, loader := range map[string]Loader{
+		"store": NewFileStore(fs, "translations"),
+		"layer": Layer(NewFileStore(fs, "translations"), NewPackLoader(packs, "packs")),
+	} {
+		// The loader returns the languages that are valid with the errors of the other languages.
+		languages, _, err := loader.Load(context.Background(), Version{})
+		var errs LanguageErrors
+		require.ErrorAs(t, err, &errs, name)
+		require.Contains(t, errs, pt, name)
+		require.Contains(t, languages, en, name)
+
+		_, err = NewTranslatorFromLoader(context.Background(), loader)
+		require.ErrorContains(t, err, "pt.json", name)
+
+		tr, err := NewTranslatorFromLoader(context.Background(), loader, WithLenientLoad(), WithDefaultLanguage(en))
+		require.NoError(t, err, name)
+		require.ErrorContains(t, tr.LoadErrors()[pt], "pt.json", name)
+		require.Equal(t, "Welcome", tr.Translate(ToCtx(context.Background(), "pt"), "welcome", nil), name)
+		require.Equal(t, "Welkom", tr.Translate(ToCtx(context.Background(), "nl"), "welcome", nil), name)
+	}
+
+	// A corrupt pack of a language is isolated as well.
+	tr, err := NewTranslatorFromLoader(context.Background(), NewPackLoader(packs, "packs"), WithLenientLoad())
+	require.NoError(t, err)
+	require.ErrorIs(t, tr.LoadErrors()[pt], ErrInvalidPack)
+}
+
+// slowLoader blocks every load after the first until release is closed or the ctx is done.
+type slowLoader struct {
+	loads   atomic.Int32
+	release chan struct{}
+	message string
+}
+
+func (l *slowLoader) Load(ctx context.Context, since Version) (map[LanguageID]*RawMessages, Version, error) {
+	n := l.loads.Add(1)
+	if n > 1 {
+		select {
+		case <-l.release:
+		case <-ctx.Done():
+			return nil, Version{}, ctx.Err()
+		}
+	}
+
+	return map[LanguageID]*RawMessages{
+		{Language: "en"}: {Messages: map[string]string{"welcome": fmt.Sprintf("%s %d", l.message, n)}},
+	}, Version{ETag: fmt.Sprint(n)}, nil
+}
+
+func TestLoadTimeout(t *testing.T) {
+	loader := &slowLoader{release: make(chan struct{}), message: "Welcome"}
+	tr, err := NewTranslatorFromLoader(context.Background(), loader, WithLoadTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	// The reload fails at the timeout and the current translations are kept.
+	require.ErrorIs(t, tr.Reload(context.Background()), context.DeadlineExceeded)
+	require.Equal(t, "Welcome 1", tr.Translate(ToCtx(context.Background(), "en"), "welcome", nil))
+}
+
+func TestBackgroundRefresh(t *testing.T) {
+	loader := &slowLoader{release: make(chan struct{}), message: "Welcome"}
+
+	var mu sync.Mutex
+	var errs []error
+	tr, err := NewTranslatorFromLoader(context.Background(), loader, WithBackgroundRefresh(time.Millisecond, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+
+	ctx := ToCtx(context.Background(), "en")
+	time.Sleep(2 * time.Millisecond)
+
+	// The stale translations are served while the reload is blocked.
+	require.Equal(t, "Welcome 1", tr.Translate(ctx, "welcome", nil))
+	require.Eventually(t, func() bool { return loader.loads.Load() == 2 }, time.Second, time.Millisecond)
+	require.Equal(t, "Welcome 1", tr.Translate(ctx, "welcome", nil))
+
+	// Only one reload runs at a time.
+	time.Sleep(2 * time.Millisecond)
+	require.Equal(t, "Welcome 1", tr.Translate(ctx, "welcome", nil))
+	require.Equal(t, int32(2), loader.loads.Load())
+
+	close(loader.release)
+	require.Eventually(t, func() bool { return tr.Translate(ctx, "welcome", nil) != "Welcome 1" }, time.Second, time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Empty(t, errs)
+}
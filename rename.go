@@ -0,0 +1,148 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidPlaceholder is returned when a placeholder is renamed to a name that is not a valid placeholder, e.g. "user_name".
+	ErrInvalidPlaceholder = fmt.Errorf("invalid placeholder")
+	// ErrPlaceholderExists is returned when a placeholder is renamed to a placeholder that the message already has.
+	ErrPlaceholderExists = fmt.Errorf("placeholder already exists")
+)
+
+var placeholderNameRe = regexp.MustCompile(`^[A-Za-z]+(?:\.[A-Za-z]+)*$`)
+
+// RenamePlaceholder renames the placeholder from to to in the message and returns the number of renamed placeholders.
+// The names are compared case insensitive and the case of the first letter is kept, so :User becomes :Username.
+// Modifiers and escaped placeholders are kept as is. It returns ErrPlaceholderExists if the message already has the placeholder to,
+// the rename would merge the two placeholders.
+func RenamePlaceholder(value, from, to string) (string, int, error) {
+	if !placeholderNameRe.MatchString(to) {
+		return value, 0, fmt.Errorf("%w: %q", ErrInvalidPlaceholder, to)
+	}
+
+	locs := messageRe.FindAllStringSubmatchIndex(value, -1)
+	if !strings.EqualFold(from, to) {
+		for _, loc := range locs {
+			if loc[3] <= loc[2] && strings.EqualFold(value[loc[4]:loc[5]], to) {
+				return value, 0, fmt.Errorf("%w: message %q has placeholder :%s", ErrPlaceholderExists, value, to)
+			}
+		}
+	}
+
+	var b strings.Builder
+	renamed, last := 0, 0
+	for _, loc := range locs {
+		name := value[loc[4]:loc[5]]
+		if loc[3] > loc[2] || !strings.EqualFold(name, from) {
+			continue
+		}
+
+		newName := to
+		if first, _ := utf8.DecodeRuneInString(name); unicode.IsUpper(first) {
+			r, size := utf8.DecodeRuneInString(to)
+			newName = string(unicode.ToUpper(r)) + to[size:]
+		}
+
+		b.WriteString(value[last:loc[4]])
+		b.WriteString(newName)
+		last = loc[5]
+		renamed++
+	}
+
+	b.WriteString(value[last:])
+	return b.String(), renamed, nil
+}
+
+// ReplacementUsage is a call in the source code that passes a replacement to the message of a key.
+type ReplacementUsage struct {
+	Key         string
+	Replacement string
+	Pos         token.Position
+}
+
+// FindReplacementUsages finds the calls with the translation key that pass the replacement in a map literal, e.g. for the key "welcome"
+// and the replacement "user":
+//
+//	tr.Translate(ctx, "welcome", map[string]any{"user": name})
+//
+// Replacements that are not passed as a map literal in the call are not found.
+func FindReplacementUsages(ctx context.Context, dir, key, replacement string, opts ...ExtractOpt) ([]ReplacementUsage, error) {
+	cfg := newExtractConfig(opts...)
+
+	dirs, err := findDirsRecursively(dir, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []ReplacementUsage
+	for _, dir := range dirs {
+		pkgs, err := loadPackages(ctx, cfg, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pkg := range pkgs {
+			if pkg.TypesInfo == nil {
+				continue
+			}
+
+			for _, file := range pkg.Syntax {
+				ast.Inspect(file, func(node ast.Node) bool {
+					call, ok := node.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+
+					translation := processCallExpr(cfg, pkg.TypesInfo, call)
+					if translation == "" || catalogKey(translation) != key {
+						return true
+					}
+
+					for _, pos := range replacementsInArgs(pkg.TypesInfo, call.Args, replacement) {
+						usages = append(usages, ReplacementUsage{Key: key, Replacement: replacement, Pos: pkg.Fset.Position(pos)})
+					}
+
+					return true
+				})
+			}
+		}
+	}
+
+	return usages, nil
+}
+
+// replacementsInArgs returns the positions of the replacement in the map literals of the arguments.
+func replacementsInArgs(info *types.Info, args []ast.Expr, replacement string) []token.Pos {
+	var positions []token.Pos
+	for _, arg := range args {
+		lit, ok := arg.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			value := info.Types[kv.Key].Value
+			if value != nil && value.Kind() == constant.String && strings.EqualFold(constant.StringVal(value), replacement) {
+				positions = append(positions, kv.Key.Pos())
+			}
+		}
+	}
+
+	return positions
+}
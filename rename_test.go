@@ -0,0 +1,53 @@
+package messages
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenamePlaceholder(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		renamed int
+	}{
+		{value: "Welcome :user", want: "Welcome :username", renamed: 1},
+		{value: "Welcome :User, bye :user|upper", want: "Welcome :Username, bye :username|upper", renamed: 2},
+		{value: "Welcome :users and \\:user", want: "Welcome :users and \\:user", renamed: 0},
+		{value: ":user == admin ? Hi boss | Hi :user", want: ":username == admin ? Hi boss | Hi :username", renamed: 2},
+	}
+
+	for _, tt := range tests {
+		got, renamed, err := RenamePlaceholder(tt.value, "user", "username")
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got)
+		require.Equal(t, tt.renamed, renamed)
+	}
+
+	_, _, err := RenamePlaceholder("Welcome :user", "user", "user_name")
+	require.ErrorIs(t, err, ErrInvalidPlaceholder)
+
+	_, _, err = RenamePlaceholder("Welcome :user, you are :Username", "user", "username")
+	require.ErrorIs(t, err, ErrPlaceholderExists)
+
+	// Escaped placeholders are text.
+	got, renamed, err := RenamePlaceholder("Welcome :user, use \\:username", "user", "username")
+	require.NoError(t, err)
+	require.Equal(t, "Welcome :username, use \\:username", got)
+	require.Equal(t, 1, renamed)
+}
+
+func TestFindReplacementUsages(t *testing.T) {
+	usages, err := FindReplacementUsages(context.Background(), "./testdata/rename-placeholder", "welcome", "user")
+	require.NoError(t, err)
+
+	var lines []int
+	for _, usage := range usages {
+		require.Equal(t, "rename.go", filepath.Base(usage.Pos.Filename))
+		lines = append(lines, usage.Pos.Line)
+	}
+	require.ElementsMatch(t, []int{14, 15, 16}, lines)
+}
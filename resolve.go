@@ -0,0 +1,95 @@
+package messages
+
+import (
+	"context"
+)
+
+// ResolutionSource is the layer a message is resolved from, see Resolve.
+type ResolutionSource string
+
+const (
+	// SourceOverride is a message of an OverrideSource, see WithOverrides.
+	SourceOverride ResolutionSource = "override"
+	// SourceRegion is a region override in the translation file, e.g. "color@GB".
+	SourceRegion ResolutionSource = "region"
+	// SourceLanguage is a message in the translation file of the language of the context.
+	SourceLanguage ResolutionSource = "language"
+	// SourceBase is a message in the translation file of the language without region, e.g. nl.json for nl-BE.
+	SourceBase ResolutionSource = "base"
+	// SourceDefault is a message in the translation file of the default language, the language of the context has no translations.
+	SourceDefault ResolutionSource = "default"
+	// SourceEmbedded is a built-in message that is not in the translation file, see WithValidationMessages.
+	SourceEmbedded ResolutionSource = "embedded"
+)
+
+// Resolution describes where the message of a key is resolved from.
+type Resolution struct {
+	// Key is the key that is looked up, after the key rewriters.
+	Key Key
+	// Requested is the language of the context, or the default language if the context has no language.
+	Requested LanguageID
+	// Language is the language of the message, including the region of the context.
+	Language LanguageID
+	// Source is the layer the message is resolved from.
+	Source ResolutionSource
+	// File is the translation file, or the language of the Loader, the message is loaded from.
+	File string
+}
+
+// Resolve reports where the message of key is resolved from for the language of the ctx, e.g. to find out why a message is not translated.
+// Ok is false if the key has no message, Translate returns the key then.
+func (t *Translator) Resolve(ctx context.Context, key Key) (Resolution, bool) {
+	messages, region := t.messages(ctx)
+	if messages == nil {
+		return Resolution{}, false
+	}
+
+	requested := FromCtx(ctx)
+	if requested.Empty() {
+		requested = t.defaultLanguage
+	}
+
+	key = t.rewriteKey(ctx, messages, region, key)
+	key = t.activeVariant(messages, region, key)
+	if base, _, ok := splitCasingDirective(key); ok {
+		if _, found := messages.lookup(key, region); !found {
+			key = base
+		}
+	}
+
+	res := Resolution{
+		Key:       key,
+		Requested: requested,
+		Language:  messages.id(region),
+		File:      messages.source,
+	}
+
+	overridden := t.override(ctx, messages, region, key)
+	if _, ok := overridden.overrides[key]; ok {
+		res.Source = SourceOverride
+		return res, true
+	}
+
+	if _, ok := messages.lookup(key, region); !ok {
+		return Resolution{}, false
+	}
+
+	_, isRegion := messages.regions[region][key]
+	_, isLayer := messages.layerKeys[key]
+	base, _ := messages.lang.Base()
+
+	switch {
+	case isRegion:
+		res.Source = SourceRegion
+	case isLayer:
+		res.Source = SourceEmbedded
+	case base.String() != requested.Language:
+		res.Source = SourceDefault
+	case messages.lang.String() != requested.String():
+		res.Source = SourceBase
+	default:
+		res.Source = SourceLanguage
+	}
+
+	return res, true
+}
@@ -0,0 +1,64 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+type staticOverrides map[string]string
+
+func (o staticOverrides) Override(ctx context.Context, lang LanguageID, key Key) (string, bool) {
+	value, ok := o[lang.String()+":"+string(key)]
+	return value, ok
+}
+
+func TestResolve(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"color": "Color", "color@GB": "Colour", "welcome": "Welcome"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"color": "Kleur", "welcome": "Welkom"}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations",
+		WithDefaultLanguage(LanguageID{Language: "en"}),
+		WithValidationMessages(),
+		WithOverrides(staticOverrides{"nl:welcome": "Hallo"}),
+	)
+	require.NoError(t, err)
+
+	ctx := func(lang string) context.Context {
+		return ToCtx(context.Background(), lang)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		key    Key
+		source ResolutionSource
+		lang   string
+		file   string
+	}{
+		{name: "language", ctx: ctx("nl"), key: "color", source: SourceLanguage, lang: "nl", file: "translations/nl.json"},
+		{name: "no language in context", ctx: context.Background(), key: "color", source: SourceLanguage, lang: "en", file: "translations/en.json"},
+		{name: "base", ctx: ctx("nl-BE"), key: "color", source: SourceBase, lang: "nl-BE", file: "translations/nl.json"},
+		{name: "region", ctx: ctx("en-GB"), key: "color", source: SourceRegion, lang: "en-GB", file: "translations/en.json"},
+		{name: "default", ctx: ctx("fr"), key: "color", source: SourceDefault, lang: "en", file: "translations/en.json"},
+		{name: "embedded", ctx: ctx("nl"), key: "validation.required", source: SourceEmbedded, lang: "nl", file: "translations/nl.json"},
+		{name: "override", ctx: ctx("nl"), key: "welcome", source: SourceOverride, lang: "nl", file: "translations/nl.json"},
+		{name: "casing directive", ctx: ctx("nl"), key: "color!upper", source: SourceLanguage, lang: "nl", file: "translations/nl.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, ok := tr.Resolve(tt.ctx, tt.key)
+			require.True(t, ok)
+			require.Equal(t, tt.source, res.Source)
+			require.Equal(t, tt.lang, res.Language.String())
+			require.Equal(t, tt.file, res.File)
+		})
+	}
+
+	_, ok := tr.Resolve(ctx("nl"), "missing")
+	require.False(t, ok)
+}
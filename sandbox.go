@@ -0,0 +1,280 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// Defaults of TemplateSandbox.
+const (
+	DefaultTemplateTimeout   = 100 * time.Millisecond
+	DefaultTemplateMaxOutput = 64 << 10
+)
+
+// DefaultTemplateFuncs are the functions that sandboxed templates can use when TemplateSandbox.Funcs is nil.
+var DefaultTemplateFuncs = []string{
+	"and", "or", "not", "eq", "ne", "lt", "le", "gt", "ge", "len", "index", "slice", "print", "printf",
+	"upper", "lower", "trim", "join", "replace", "contains", "default", "add", "sub",
+}
+
+// sandboxRangeFunc is the function that the sandbox adds to the pipeline of every range, see TemplateSandbox.check.
+const sandboxRangeFunc = "sandboxRange"
+
+// errTemplatePrintfWidth is returned by the printf of the sandbox for widths and precisions that exceed the maximum output.
+var errTemplatePrintfWidth = errors.New("printf width or precision too large")
+
+var (
+	errTemplateOutputTooLarge = errors.New("template output too large")
+	errTemplateTimeout        = errors.New("template timed out")
+)
+
+// TemplateSandbox limits what template messages can do, for translation files that are edited by people you don't trust.
+type TemplateSandbox struct {
+	// Funcs are the names of the functions that templates can use, DefaultTemplateFuncs if nil.
+	// The text/template function call is never allowed.
+	Funcs []string
+	// Timeout is the maximum execution time of a template, DefaultTemplateTimeout if zero.
+	Timeout time.Duration
+	// MaxOutput is the maximum size in bytes of the output of a template, DefaultTemplateMaxOutput if zero.
+	MaxOutput int
+}
+
+// WithTemplateSandbox enables the template messages of WithTemplates with the limits of the sandbox:
+//
+//   - Templates can only use the functions of the sandbox, and can not include other templates.
+//   - Templates only see strings, numbers, booleans and lists and maps of them. Other replacement values are formatted
+//     as a string first, so a template can not read the fields or call the methods of a value.
+//   - A range can only loop over lists and maps, a range over a number fails.
+//   - The widths and precisions of printf can not add up to more than the maximum output.
+//   - Templates that run longer than the timeout, or write more than the maximum output, are stopped and return the key.
+//
+// A template that uses a function that is not allowed is an ErrInvalidTemplate when the translations are loaded.
+func WithTemplateSandbox(sandbox TemplateSandbox) Opt {
+	return func(t *Translator) {
+		if sandbox.Funcs == nil {
+			sandbox.Funcs = DefaultTemplateFuncs
+		}
+		if sandbox.Timeout <= 0 {
+			sandbox.Timeout = DefaultTemplateTimeout
+		}
+		if sandbox.MaxOutput <= 0 {
+			sandbox.MaxOutput = DefaultTemplateMaxOutput
+		}
+
+		t.templates = true
+		t.templateSandbox = &sandbox
+	}
+}
+
+// check returns an error if the template does something that the sandbox does not allow.
+func (s *TemplateSandbox) check(tmpl *template.Template) error {
+	if len(tmpl.Templates()) > 1 {
+		return fmt.Errorf("defining templates is not allowed")
+	}
+
+	var err error
+	walkTemplate(tmpl.Tree.Root, func(node parse.Node) {
+		if err != nil {
+			return
+		}
+
+		switch n := node.(type) {
+		case *parse.IdentifierNode:
+			if n.Ident == "call" || !slices.Contains(s.Funcs, n.Ident) {
+				err = fmt.Errorf("function %q is not allowed", n.Ident)
+			}
+		case *parse.TemplateNode:
+			err = fmt.Errorf("including template %q is not allowed", n.Name)
+		case *parse.RangeNode:
+			// A range over a number can loop for a long time without output.
+			for _, cmd := range n.Pipe.Cmds {
+				for _, arg := range cmd.Args {
+					if _, ok := arg.(*parse.NumberNode); ok {
+						err = fmt.Errorf("range over a number is not allowed")
+					}
+				}
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// The value of every range goes through the range function, which only allows lists and maps and stops the execution
+	// after a timeout. A range over a number from a variable or a function is only known when the template is executed.
+	walkTemplate(tmpl.Tree.Root, func(node parse.Node) {
+		if n, ok := node.(*parse.RangeNode); ok {
+			n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+				NodeType: parse.NodeCommand,
+				Pos:      n.Pipe.Pos,
+				Args:     []parse.Node{parse.NewIdentifier(sandboxRangeFunc).SetPos(n.Pipe.Pos)},
+			})
+		}
+	})
+
+	return nil
+}
+
+// execute executes the template with the sandboxed data within the limits of the sandbox.
+func (s *TemplateSandbox) execute(tmpl *template.Template, data map[string]any) (string, bool) {
+	sandboxed := make(map[string]any, len(data))
+	for name, value := range data {
+		sandboxed[name] = sandboxValue(reflect.ValueOf(value))
+	}
+
+	type result struct {
+		out string
+		err error
+	}
+
+	// The template can not be interrupted, it stops at the next range or write after the timeout.
+	stop := &atomic.Bool{}
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", false
+	}
+	tmpl.Funcs(template.FuncMap{
+		sandboxRangeFunc: func(value any) (any, error) {
+			if stop.Load() {
+				return nil, errTemplateTimeout
+			}
+
+			switch v := reflect.ValueOf(value); v.Kind() {
+			case reflect.Invalid, reflect.Slice, reflect.Array, reflect.Map:
+				return value, nil
+			default:
+				return nil, fmt.Errorf("range over %T is not allowed", value)
+			}
+		},
+		// The padding of printf is allocated before the output reaches the writer.
+		"printf": func(format string, args ...any) (string, error) {
+			if err := checkPrintfWidth(format, s.MaxOutput); err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf(format, args...), nil
+		},
+	})
+
+	done := make(chan result, 1)
+	go func() {
+		w := &limitedWriter{max: s.MaxOutput, stop: stop}
+		err := tmpl.Execute(w, sandboxed)
+		done <- result{out: w.b.String(), err: err}
+	}()
+
+	timer := time.NewTimer(s.Timeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		return res.out, res.err == nil
+	case <-timer.C:
+		stop.Store(true)
+		return "", false
+	}
+}
+
+// checkPrintfWidth returns an error if the widths and precisions of the verbs in the format add up to more than max,
+// or if a width or precision is an argument with *.
+func checkPrintfWidth(format string, max int) error {
+	total := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		// The flags, argument indexes, width and precision come before the verb.
+		n := 0
+		for i++; i < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[i]) >= 0; i++ {
+			switch c := format[i]; {
+			case c == '*':
+				return errTemplatePrintfWidth
+			case c >= '0' && c <= '9':
+				n = n*10 + int(c-'0')
+			default:
+				total, n = total+n, 0
+			}
+
+			if total+n > max {
+				return errTemplatePrintfWidth
+			}
+		}
+		total += n
+	}
+
+	return nil
+}
+
+// sandboxValue converts the value to strings, numbers, booleans and slices and maps of them.
+func sandboxValue(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Slice, reflect.Array:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = sandboxValue(v.Index(i))
+		}
+		return items
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		entries := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[iter.Key().String()] = sandboxValue(iter.Value())
+		}
+		return entries
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return sandboxValue(v.Elem())
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+	}
+
+	return formatReplacement(v.Interface())
+}
+
+// limitedWriter writes to a buffer and fails when more than max bytes are written or the execution is stopped.
+type limitedWriter struct {
+	b    strings.Builder
+	max  int
+	stop *atomic.Bool
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.stop.Load() {
+		return 0, errTemplateTimeout
+	}
+	if w.b.Len()+len(p) > w.max {
+		return 0, errTemplateOutputTooLarge
+	}
+
+	return w.b.Write(p)
+}
@@ -0,0 +1,38 @@
+package messages
+
+import (
+	"context"
+
+	"github.com/spf13/afero"
+)
+
+// SimpleTranslator translates messages in a single language without a context, see Simple.
+type SimpleTranslator struct {
+	translator *Translator
+	ctx        context.Context
+}
+
+// Simple returns a translator for the language with the translation files in dir on the OS filesystem.
+// It is meant for command line tools and scripts that use the same translation files as the application,
+// but have no request context to carry the language:
+//
+//	tr, err := messages.Simple(messages.LanguageID{Language: "nl"}, "./translations")
+//	fmt.Println(tr.T("welcome.login", map[string]any{"user": "Jan"}))
+func Simple(lang LanguageID, dir string, opts ...Opt) (*SimpleTranslator, error) {
+	t, err := NewTranslator(afero.NewOsFs(), dir, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SimpleTranslator{translator: t, ctx: toCtx(context.Background(), lang)}, nil
+}
+
+// T translates the message of key in the language of the translator, see Translator.Translate.
+func (s *SimpleTranslator) T(key Key, replacements map[string]any) string {
+	return s.translator.Translate(s.ctx, key, replacements)
+}
+
+// Language returns the language of the translator.
+func (s *SimpleTranslator) Language() LanguageID {
+	return FromCtx(s.ctx)
+}
@@ -0,0 +1,18 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimple(t *testing.T) {
+	tr, err := Simple(LanguageID{Language: "nl", Region: "NL"}, "./testdata/valid")
+	require.NoError(t, err)
+
+	require.Equal(t, LanguageID{Language: "nl", Region: "NL"}, tr.Language())
+	require.Equal(t, "Welkom jan", tr.T("welcome.login", map[string]any{"user": "jan"}))
+
+	_, err = Simple(LanguageID{Language: "nl"}, "./testdata/invalid-language")
+	require.Error(t, err)
+}
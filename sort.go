@@ -0,0 +1,23 @@
+package messages
+
+import (
+	"context"
+
+	"golang.org/x/text/collate"
+)
+
+// Sort sorts the items in place in the alphabetical order of the language of the ctx, e.g. for a list of translated options.
+// The order of letters with accents differs per language, e.g. "ä" is sorted after "z" in Swedish and after "a" in German.
+func (t *Translator) Sort(ctx context.Context, items []string) {
+	collate.New(t.tag(ctx), collate.IgnoreCase).SortStrings(items)
+}
+
+// Less returns a function that reports if a is sorted before b in the alphabetical order of the language of the ctx, see Sort.
+// It can be used with slices.SortFunc and sort.Slice to sort structs by a translated field. The function is not safe for concurrent use.
+func (t *Translator) Less(ctx context.Context) func(a, b string) bool {
+	collator := collate.New(t.tag(ctx), collate.IgnoreCase)
+
+	return func(a, b string) bool {
+		return collator.CompareString(a, b) < 0
+	}
+}
@@ -0,0 +1,36 @@
+package messages
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSort(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/de.json", []byte(`{}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/sv.json", []byte(`{}`), 0o644))
+
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+
+	de := ToCtx(context.Background(), "de")
+	sv := ToCtx(context.Background(), "sv")
+
+	items := []string{"Zucker", "Äpfel", "apfel", "Birne"}
+	tr.Sort(de, items)
+	require.Equal(t, []string{"apfel", "Äpfel", "Birne", "Zucker"}, items)
+
+	items = []string{"Zucker", "Äpfel", "apfel", "Birne"}
+	tr.Sort(sv, items)
+	require.Equal(t, []string{"apfel", "Birne", "Zucker", "Äpfel"}, items)
+
+	type option struct{ label string }
+	options := []option{{"Österreich"}, {"Niederlande"}, {"Belgien"}}
+	less := tr.Less(de)
+	sort.Slice(options, func(i, j int) bool { return less(options[i].label, options[j].label) })
+	require.Equal(t, []option{{"Belgien"}, {"Niederlande"}, {"Österreich"}}, options)
+}
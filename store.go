@@ -0,0 +1,82 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// Saver writes the messages of a language to a writable backend, like the translation files in a directory.
+type Saver interface {
+	Save(lang LanguageID, msgs *RawMessages) error
+}
+
+// FileStore is the filesystem backend for the translation files in a directory.
+type FileStore struct {
+	fs     afero.Fs
+	dir    string
+	parser *Parser
+	// Mu serializes the writes to the translation files and the load-modify-save of the archives.
+	mu sync.Mutex
+}
+
+var (
+	_ Saver          = (*FileStore)(nil)
+	_ MetadataLoader = (*FileStore)(nil)
+)
+
+// NewFileStore returns the filesystem backend for the translation files in dir.
+func NewFileStore(fs afero.Fs, dir string, opts ...ParserOpt) *FileStore {
+	return &FileStore{
+		fs:     fs,
+		dir:    dir,
+		parser: NewParser(fs, opts...),
+	}
+}
+
+// Save writes the messages to the translation file of the language, the file is created if it does not exist.
+// The file is replaced atomically, so a reader never sees a partially written file.
+func (s *FileStore) Save(lang LanguageID, msgs *RawMessages) error {
+	content, err := msgs.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshaling messages: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := s.parser.TranslationFilesFromDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	file, ok := files[lang.String()]
+	if !ok {
+		file = filepath.Join(s.dir, lang.String()+".json")
+	}
+
+	mode := os.FileMode(0o644)
+	if stat, err := s.fs.Stat(file); err == nil {
+		mode = stat.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("getting file info: %w", err)
+	}
+
+	// The temporary file is hidden, so it is skipped when the translation files are read.
+	tmp := filepath.Join(s.dir, "."+filepath.Base(file)+".tmp")
+	if err := afero.WriteFile(s.fs, tmp, content, mode); err != nil {
+		return fmt.Errorf("writing translations: %w", err)
+	}
+
+	if err := s.fs.Rename(tmp, file); err != nil {
+		_ = s.fs.Remove(tmp)
+		return fmt.Errorf("writing translations: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStoreSave(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en_US.json", []byte(`{"welcome": "Welcome"}`), 0o600))
+
+	store := NewFileStore(fs, "translations")
+
+	// The existing file of the language is replaced.
+	en, err := ParseLanguage("en-US")
+	require.NoError(t, err)
+
+	err = store.Save(en, &RawMessages{Messages: map[string]string{"welcome": "Hello"}, Attributes: map[string]string{}})
+	require.NoError(t, err)
+
+	raw, err := NewParser(fs).MessagesFromFile("translations/en_US.json")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"welcome": "Hello"}, raw.Messages)
+
+	stat, err := fs.Stat("translations/en_US.json")
+	require.NoError(t, err)
+	require.Equal(t, "-rw-------", stat.Mode().Perm().String())
+
+	// A new language creates the file.
+	nl, err := ParseLanguage("nl")
+	require.NoError(t, err)
+
+	err = store.Save(nl, &RawMessages{Messages: map[string]string{"welcome": "Welkom"}})
+	require.NoError(t, err)
+
+	files, err := NewParser(fs).TranslationFilesFromDir("translations")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"en-US": "translations/en_US.json", "nl": "translations/nl.json"}, files)
+
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+	require.Equal(t, "Welkom", tr.Translate(ToCtx(context.Background(), "nl"), "welcome", nil))
+}
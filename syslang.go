@@ -0,0 +1,45 @@
+package messages
+
+import (
+	"os"
+	"strings"
+)
+
+// systemLanguageEnv are the environment variables with the language of the system, in order of precedence.
+var systemLanguageEnv = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+// DetectSystemLanguage returns the language of the system, e.g. to translate the output of a command line tool.
+// The environment variables LC_ALL, LC_MESSAGES and LANG are used in that order, a value like "nl_NL.UTF-8" is "nl-NL".
+// The C and POSIX locales have no language. On Windows the language of the user is used when the variables are not set.
+// Ok is false if the language can not be detected.
+func DetectSystemLanguage() (lang LanguageID, ok bool) {
+	for _, name := range systemLanguageEnv {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		// The first variable that is set is used, like the C library does, also if it is the C locale.
+		return parseLocaleEnv(value)
+	}
+
+	return platformLanguage()
+}
+
+// parseLocaleEnv parses a POSIX locale like "nl_NL.UTF-8@euro".
+func parseLocaleEnv(value string) (LanguageID, bool) {
+	if i := strings.IndexAny(value, ".@"); i >= 0 {
+		value = value[:i]
+	}
+
+	if value == "" || value == "C" || value == "POSIX" {
+		return LanguageID{}, false
+	}
+
+	lang, err := ParseLanguage(value)
+	if err != nil {
+		return LanguageID{}, false
+	}
+
+	return lang, true
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package messages
+
+// platformLanguage returns the language of the system when it is not set in the environment.
+// Only Windows has a system language outside of the environment.
+func platformLanguage() (LanguageID, bool) {
+	return LanguageID{}, false
+}
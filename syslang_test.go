@@ -0,0 +1,41 @@
+package messages
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSystemLanguage(t *testing.T) {
+	tests := []struct {
+		lcAll, lcMessages, lang string
+		want                    LanguageID
+		ok                      bool
+	}{
+		{lang: "nl_NL.UTF-8", want: LanguageID{Language: "nl", Region: "NL"}, ok: true},
+		{lcMessages: "de_AT@euro", lang: "nl_NL.UTF-8", want: LanguageID{Language: "de", Region: "AT"}, ok: true},
+		{lcAll: "fr", lcMessages: "de_AT", want: LanguageID{Language: "fr"}, ok: true},
+		{lcAll: "C.UTF-8", lang: "nl_NL.UTF-8"},
+		{lang: "POSIX"},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_MESSAGES", tt.lcMessages)
+		t.Setenv("LANG", tt.lang)
+
+		lang, ok := DetectSystemLanguage()
+		require.Equal(t, tt.ok, ok)
+		require.Equal(t, tt.want, lang)
+	}
+
+	if runtime.GOOS != "windows" {
+		t.Setenv("LC_ALL", "")
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", "")
+
+		_, ok := DetectSystemLanguage()
+		require.False(t, ok)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package messages
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// localeNameMaxLength is LOCALE_NAME_MAX_LENGTH of the Windows API.
+const localeNameMaxLength = 85
+
+var procGetUserDefaultLocaleName = syscall.NewLazyDLL("kernel32.dll").NewProc("GetUserDefaultLocaleName")
+
+// platformLanguage returns the language of the user with GetUserDefaultLocaleName, e.g. "nl-NL".
+func platformLanguage() (LanguageID, bool) {
+	buf := make([]uint16, localeNameMaxLength)
+
+	n, _, _ := procGetUserDefaultLocaleName.Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	if n == 0 {
+		return LanguageID{}, false
+	}
+
+	lang, err := ParseLanguage(syscall.UTF16ToString(buf))
+	if err != nil {
+		return LanguageID{}, false
+	}
+
+	return lang, true
+}
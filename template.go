@@ -0,0 +1,184 @@
+package messages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// ErrInvalidTemplate is returned when a template message can not be parsed, see WithTemplates.
+var ErrInvalidTemplate = fmt.Errorf("invalid template message")
+
+// WithTemplates executes the messages that contain "{{" as Go text/template templates with the replacements as data,
+// for messages that need more than placeholders, like loops over a list:
+//
+//	"cart": "{{ range $i, $item := .items }}{{ if $i }}, {{ end }}{{ upper $item }}{{ end }}"
+//
+// Template messages have no placeholders, missing replacements are empty strings. The templates can use the functions of
+// text/template and upper, lower, trim, join, replace, contains, default, add and sub. Templates are parsed when the
+// translations are loaded, a template that can not be parsed is an ErrInvalidTemplate. Only enable it for translation files you trust,
+// use WithTemplateSandbox for translation files that are edited by others.
+func WithTemplates() Opt {
+	return func(t *Translator) {
+		t.templates = true
+	}
+}
+
+// messageTemplate is the template of a template message.
+type messageTemplate struct {
+	tmpl *template.Template
+	// Fields are the replacements that the template uses, missing replacements are set to an empty string.
+	fields []string
+	// Sandbox limits the execution, nil if the template is trusted. See WithTemplateSandbox.
+	sandbox *TemplateSandbox
+}
+
+// templateFuncs are the functions that templates can use next to the functions of text/template.
+var templateFuncs = template.FuncMap{
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"trim":     strings.TrimSpace,
+	"join":     func(sep string, items []string) string { return strings.Join(items, sep) },
+	"replace":  func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"contains": func(substr, s string) bool { return strings.Contains(s, substr) },
+	"default": func(def, value any) any {
+		if value == nil || value == "" {
+			return def
+		}
+		return value
+	},
+	"add": func(a, b int) int { return a + b },
+	"sub": func(a, b int) int { return a - b },
+}
+
+// isTemplateMessage reports if the message value is a template.
+func isTemplateMessage(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// compileTemplates parses the template messages of the language, when templates are enabled.
+func (t *Translator) compileTemplates(m *messages) error {
+	if !t.templates {
+		return nil
+	}
+
+	for key, i := range m.index {
+		msg, err := t.compileTemplate(key, m.messages[i])
+		if err != nil {
+			return err
+		}
+		m.messages[i] = msg
+	}
+
+	for _, regionMessages := range m.regions {
+		for key, msg := range regionMessages {
+			msg, err := t.compileTemplate(key, msg)
+			if err != nil {
+				return err
+			}
+			regionMessages[key] = msg
+		}
+	}
+
+	return nil
+}
+
+// compileTemplate parses the message of key as a template if it is a template message.
+func (t *Translator) compileTemplate(key Key, msg message) (message, error) {
+	if !t.templates || msg.condition != nil || !isTemplateMessage(msg.message) {
+		return msg, nil
+	}
+
+	tmpl, err := template.New(string(key)).Funcs(templateFuncs).Parse(msg.message)
+	if err != nil {
+		return msg, fmt.Errorf("%w: message %q: %w", ErrInvalidTemplate, key, err)
+	}
+
+	if t.templateSandbox != nil {
+		if err := t.templateSandbox.check(tmpl); err != nil {
+			return msg, fmt.Errorf("%w: message %q: %w", ErrInvalidTemplate, key, err)
+		}
+	}
+
+	var fields []string
+	walkTemplate(tmpl.Tree.Root, func(node parse.Node) {
+		if field, ok := node.(*parse.FieldNode); ok && !slices.Contains(fields, field.Ident[0]) {
+			fields = append(fields, field.Ident[0])
+		}
+	})
+
+	msg.template = &messageTemplate{tmpl: tmpl, fields: fields, sandbox: t.templateSandbox}
+	msg.replacements = nil
+
+	return msg, nil
+}
+
+// execute executes the template with the replacements, ok is false if the template fails.
+func (mt *messageTemplate) execute(replacements map[string]any) (string, bool) {
+	data := make(map[string]any, len(replacements)+len(mt.fields))
+	for _, field := range mt.fields {
+		data[field] = ""
+	}
+	for name, value := range replacements {
+		data[name] = value
+	}
+
+	if mt.sandbox != nil {
+		return mt.sandbox.execute(mt.tmpl, data)
+	}
+
+	var b strings.Builder
+	if err := mt.tmpl.Execute(&b, data); err != nil {
+		return "", false
+	}
+
+	return b.String(), true
+}
+
+// walkTemplate calls fn for every node of the template tree.
+func walkTemplate(node parse.Node, fn func(parse.Node)) {
+	fn(node)
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		for _, child := range n.Nodes {
+			walkTemplate(child, fn)
+		}
+	case *parse.ActionNode:
+		walkTemplate(n.Pipe, fn)
+	case *parse.PipeNode:
+		for _, decl := range n.Decl {
+			walkTemplate(decl, fn)
+		}
+		for _, cmd := range n.Cmds {
+			walkTemplate(cmd, fn)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			walkTemplate(arg, fn)
+		}
+	case *parse.ChainNode:
+		walkTemplate(n.Node, fn)
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			walkTemplate(n.Pipe, fn)
+		}
+	}
+}
+
+// walkBranch walks the pipeline and the lists of an if, range or with node.
+func walkBranch(n *parse.BranchNode, fn func(parse.Node)) {
+	walkTemplate(n.Pipe, fn)
+	walkTemplate(n.List, fn)
+	if n.ElseList != nil {
+		walkTemplate(n.ElseList, fn)
+	}
+}
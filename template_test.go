@@ -0,0 +1,123 @@
+package messages
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplates(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{
+		"cart": "Cart: {{ range $i, $item := .items }}{{ if $i }}, {{ end }}{{ upper $item }}{{ end }}",
+		"greeting": "Hello {{ default \"guest\" .user }}{{ .missing }}",
+		"broken": "{{ index .items 5 }}"
+	}`), 0o600))
+
+	ctx, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	tr, err := NewTranslator(fs, "translations", WithTemplates())
+	require.NoError(t, err)
+
+	require.Equal(t, "Cart: APPLE, PEAR", tr.Translate(ctx, "cart", map[string]any{"items": []string{"apple", "pear"}}))
+	require.Equal(t, "Hello guest", tr.Translate(ctx, "greeting", nil))
+	require.Equal(t, "Hello jan", tr.Translate(ctx, "greeting", map[string]any{"user": "jan"}))
+	// A template that fails returns the key.
+	require.Equal(t, "broken", tr.Translate(ctx, "broken", map[string]any{"items": []string{}}))
+
+	// Without the option the messages are not templates.
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"greeting": "Hello {{ .user }} :user"}`), 0o600))
+
+	tr, err = NewTranslator(fs, "translations")
+	require.NoError(t, err)
+	require.Equal(t, "Hello {{ .user }} jan", tr.Translate(ctx, "greeting", map[string]any{"user": "jan"}))
+
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"broken": "Hello {{ .user "}`), 0o600))
+
+	_, err = NewTranslator(fs, "translations", WithTemplates())
+	require.ErrorIs(t, err, ErrInvalidTemplate)
+}
+
+type sandboxUser struct {
+	Name     string
+	Password string
+}
+
+func TestTemplateSandbox(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	ctx, err := WithLanguage(context.Background(), "en")
+	require.NoError(t, err)
+
+	newTranslator := func(messages string, sandbox TemplateSandbox) (*Translator, error) {
+		require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(messages), 0o600))
+		return NewTranslator(fs, "translations", WithTemplateSandbox(sandbox))
+	}
+
+	tr, err := newTranslator(`{
+		"cart": "{{ range .items }}{{ upper . }} {{ end }}",
+		"user": "Hello {{ .user }}",
+		"password": "{{ .user.Password }}",
+		"long": "{{ range .items }}{{ . }}{{ . }}{{ end }}",
+		"padded": "{{ printf \"%05d|%-4s|%.2f\" 42 .user 1.5 }}",
+		"width": "{{ printf \"%01000000000d\" 1 }}",
+		"star": "{{ printf \"%*d\" 1000000000 1 }}"
+	}`, TemplateSandbox{MaxOutput: 16})
+	require.NoError(t, err)
+
+	require.Equal(t, "APPLE PEAR ", tr.Translate(ctx, "cart", map[string]any{"items": []any{"apple", "pear"}}))
+	// Values that are not plain data are formatted as a string, their fields can not be read.
+	user := sandboxUser{Name: "jan", Password: "secret"}
+	require.Equal(t, "Hello ", tr.Translate(ctx, "user", map[string]any{"user": user}))
+	require.Equal(t, "password", tr.Translate(ctx, "password", map[string]any{"user": user}))
+	// The output is limited.
+	require.Equal(t, "long", tr.Translate(ctx, "long", map[string]any{"items": []string{"abcdef", "ghijkl"}}))
+	// A printf width or precision larger than the output limit fails before it is formatted.
+	require.Equal(t, "00042|jan |1.50", tr.Translate(ctx, "padded", map[string]any{"user": "jan"}))
+	require.Equal(t, "width", tr.Translate(ctx, "width", nil))
+	require.Equal(t, "star", tr.Translate(ctx, "star", nil))
+
+	for _, message := range []string{
+		`{"x": "{{ call .fn }}"}`,
+		`{"x": "{{ printf \"%s\" .user | html }}"}`,
+		`{"x": "{{ define \"y\" }}y{{ end }}{{ template \"y\" }}"}`,
+		`{"x": "{{ range 1000000000 }}{{ end }}"}`,
+	} {
+		_, err := newTranslator(message, TemplateSandbox{})
+		require.ErrorIs(t, err, ErrInvalidTemplate, message)
+	}
+
+	// The allowed functions can be changed.
+	_, err = newTranslator(`{"x": "{{ upper .user }}"}`, TemplateSandbox{Funcs: []string{"lower"}})
+	require.ErrorIs(t, err, ErrInvalidTemplate)
+
+	// A range over a number that is not a literal fails when the template is executed.
+	tr, err = newTranslator(`{
+		"pipe": "{{ range (add 1000000 0) }}{{ range (add 1000000 0) }}{{ end }}{{ end }}",
+		"variable": "{{ $n := .count }}{{ range $n }}x{{ end }}",
+		"nested": "{{ range .items }}{{ range $.items }}{{ range $.items }}{{ end }}{{ end }}{{ end }}",
+		"empty": "{{ range .items }}x{{ else }}none{{ end }}"
+	}`, TemplateSandbox{Timeout: 20 * time.Millisecond})
+	require.NoError(t, err)
+
+	goroutines := runtime.NumGoroutine()
+	require.Equal(t, "pipe", tr.Translate(ctx, "pipe", nil))
+	require.Equal(t, "variable", tr.Translate(ctx, "variable", map[string]any{"count": 1000000}))
+	require.Equal(t, "none", tr.Translate(ctx, "empty", map[string]any{"items": []string{}}))
+
+	// A template that runs longer than the timeout is stopped, it does not keep running in the background.
+	items := make([]int, 5000)
+	for range 5 {
+		require.Equal(t, "nested", tr.Translate(ctx, "nested", map[string]any{"items": items}))
+	}
+	// Eventually runs the condition in a goroutine, so the goroutines are counted in the test.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), goroutines)
+}
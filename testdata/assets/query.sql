@@ -0,0 +1,2 @@
+-- Notifications reference their message key.
+SELECT * FROM notifications WHERE message_key = 'notification.sent';
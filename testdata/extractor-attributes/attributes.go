@@ -0,0 +1,24 @@
+package attributes
+
+import (
+	"context"
+
+	"github.com/wvell/messages"
+)
+
+const streetField = "street"
+
+var tr *messages.Translator
+
+type Replacements struct {
+	Attribute string
+}
+
+func Validate(ctx context.Context, field string) {
+	tr.Translate(ctx, "required", map[string]any{"attribute": "first_name"})
+	tr.Translate(ctx, "required", map[string]any{messages.AttributeKey: streetField})
+	tr.Translate(ctx, "required", map[string]any{"attribute": field})
+	tr.Translate(ctx, "required", map[string]any{"user": "not_an_attribute"})
+
+	_ = Replacements{Attribute: "zipcode"}
+}
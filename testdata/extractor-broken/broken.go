@@ -0,0 +1,15 @@
+package broken
+
+import (
+	"context"
+
+	"github.com/wvell/messages"
+)
+
+var tr *messages.Translator
+
+func Broken(ctx context.Context) {
+	tr.Translate(ctx, "broken.key", nil)
+
+	var unused int = "not an int"
+}
@@ -0,0 +1,46 @@
+package composite
+
+import "github.com/wvell/messages"
+
+type Route struct {
+	Path  string
+	Title messages.Key
+	// Subtitle is a pointer to a key, e.g. for optional titles.
+	Subtitle *messages.Key
+	Tabs     []messages.Key
+	Labels   map[string]messages.Key
+}
+
+type Menu struct {
+	Routes []Route
+	Footer struct {
+		Copyright messages.Key
+	}
+}
+
+const aboutTitle = "nav.about"
+
+var title = "nav.var"
+
+var Routes = []Route{
+	{Path: "/", Title: "nav.home", Tabs: []messages.Key{"nav.tab.overview", "nav.tab.settings"}},
+	{Path: "/about", Title: aboutTitle, Labels: map[string]messages.Key{"short": "nav.about.short"}},
+	{"/contact", "nav.contact", nil, nil, nil},
+	{Path: "/var", Title: messages.Key(title)},
+}
+
+var subtitle messages.Key = "nav.subtitle"
+
+var Main = Menu{
+	Routes: []Route{{Title: "nav.nested", Subtitle: &subtitle}},
+	Footer: struct{ Copyright messages.Key }{Copyright: "nav.copyright"},
+}
+
+var Untyped = map[string]string{"not": "a.key"}
+
+// keyFor returns a key, its argument is not a key.
+func keyFor(name string) messages.Key {
+	return messages.Key("nav." + name)
+}
+
+var Computed = Route{Title: keyFor("not.a.key")}
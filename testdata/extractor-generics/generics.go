@@ -0,0 +1,47 @@
+package generics
+
+import (
+	"context"
+
+	"github.com/wvell/messages"
+)
+
+var tr *messages.Translator
+
+// T is a generic helper that is instantiated with messages.Key.
+func T[K ~string](ctx context.Context, key K) string {
+	return tr.Translate(ctx, messages.Key(key), nil)
+}
+
+// Translator is a generic wrapper type.
+type Translator[K ~string] struct{}
+
+func (Translator[K]) T(ctx context.Context, key K, replacements map[string]any) string {
+	return tr.Translate(ctx, messages.Key(key), replacements)
+}
+
+// Pair is a generic helper with the key after another type parameter.
+func Pair[V any, K ~string](ctx context.Context, value V, key K) string {
+	return tr.Translate(ctx, messages.Key(key), map[string]any{"value": value})
+}
+
+func Use(ctx context.Context) {
+	T[messages.Key](ctx, "generic.explicit")
+	T(ctx, messages.Key("generic.conversion"))
+
+	var keys Translator[messages.Key]
+	keys.T(ctx, "generic.method", nil)
+
+	Pair[int, messages.Key](ctx, 1, "generic.pair")
+
+	var key messages.Key = "generic.var"
+	T(ctx, key)
+	keys.T(ctx, key, nil)
+
+	var inferred = "generic.inferred"
+	T[messages.Key](ctx, messages.Key(inferred))
+
+	// The type parameter is inferred as string, the key is only found by following wrappers.
+	T(ctx, "generic.wrapper")
+	T[string](ctx, "generic.wrapper.explicit")
+}
@@ -0,0 +1,9 @@
+//msgextractor:ignore test fixtures with invalid keys
+
+package ignore
+
+import "context"
+
+func moreFixtures(ctx context.Context) {
+	tr.Translate(ctx, "attributes", map[string]any{"attribute": "fixture"})
+}
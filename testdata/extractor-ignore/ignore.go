@@ -0,0 +1,39 @@
+package ignore
+
+import (
+	"context"
+
+	"github.com/wvell/messages"
+)
+
+var tr *messages.Translator
+
+func Greet(ctx context.Context, name string) {
+	tr.Translate(ctx, "greet.hello", nil)
+	tr.Translate(ctx, "greet.dynamic", nil) //msgextractor:ignore keys are loaded from the database
+	tr.Translate(ctx, "greet.bye", map[string]any{"attribute": "first_name"})
+
+	//msgextractor:ignore
+	tr.Translate(ctx, "greet.statement", map[string]any{"attribute": "last_name"})
+
+	//msgextractor:ignoreme is not the directive
+	tr.Translate(ctx, "greet.not_ignored", nil)
+}
+
+//msgextractor:ignore fixtures for the tests below
+func fixtures(ctx context.Context) {
+	tr.Translate(ctx, "attributes", nil)
+
+	if true {
+		tr.Translate(ctx, "fixtures.nested", nil)
+	}
+}
+
+func title(key string) string {
+	return tr.Translate(context.Background(), messages.Key(key), nil)
+}
+
+func titles() {
+	_ = title("title.home")
+	_ = title("title.dynamic") //msgextractor:ignore
+}
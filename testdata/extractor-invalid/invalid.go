@@ -8,6 +8,8 @@ import (
 
 func Invalid(ctx context.Context) {
 	Translate("attributes", nil)
+	Translate("welcome", nil)
+	Translate("meta", nil)
 }
 
 func Translate(key messages.Key, replacements map[string]interface{}) string {
@@ -0,0 +1,23 @@
+package i18n
+
+import (
+	"context"
+
+	"github.com/wvell/messages"
+)
+
+// MsgID is the key type of the facade.
+type MsgID string
+
+// Alias is an alias of messages.Key.
+type Alias = messages.Key
+
+var tr *messages.Translator
+
+func T(ctx context.Context, id MsgID) string {
+	return tr.Translate(ctx, messages.Key(id), nil)
+}
+
+func A(ctx context.Context, key Alias) string {
+	return tr.Translate(ctx, key, nil)
+}
@@ -0,0 +1,15 @@
+package keytypes
+
+import (
+	"context"
+
+	"github.com/wvell/messages/testdata/extractor-keytypes/i18n"
+)
+
+const welcome i18n.MsgID = "facade.const"
+
+func UseFacade(ctx context.Context) {
+	i18n.T(ctx, "facade.call")
+	i18n.T(ctx, welcome)
+	i18n.A(ctx, "facade.alias")
+}
@@ -0,0 +1,7 @@
+package excluded
+
+import "github.com/wvell/messages/testdata/extractor-options/i18n"
+
+func UseExcluded() {
+	i18n.Translate("excluded.key")
+}
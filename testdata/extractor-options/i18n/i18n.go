@@ -0,0 +1,28 @@
+package i18n
+
+import (
+	"context"
+
+	"github.com/wvell/messages"
+)
+
+var tr *messages.Translator
+
+// T uses key as a translation key.
+func T(ctx context.Context, key string) string {
+	return tr.Translate(ctx, messages.Key(key), nil)
+}
+
+// Chained passes key on to T.
+func Chained(key string) string {
+	return T(context.Background(), key)
+}
+
+// Plain does not use key as a translation key.
+func Plain(key string) string {
+	return key
+}
+
+func Translate(key messages.Key) string {
+	return tr.Translate(context.Background(), key, nil)
+}
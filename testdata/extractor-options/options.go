@@ -0,0 +1,13 @@
+package options
+
+import (
+	"context"
+
+	"github.com/wvell/messages/testdata/extractor-options/i18n"
+)
+
+func UseWrappers(ctx context.Context) {
+	i18n.T(ctx, "wrapper.direct")
+	i18n.Chained("wrapper.chained")
+	i18n.Plain("wrapper.none")
+}
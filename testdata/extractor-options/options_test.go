@@ -0,0 +1,11 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/wvell/messages/testdata/extractor-options/i18n"
+)
+
+func TestKey(t *testing.T) {
+	i18n.Translate("test.key")
+}
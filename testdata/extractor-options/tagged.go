@@ -0,0 +1,9 @@
+//go:build extra
+
+package options
+
+import "github.com/wvell/messages/testdata/extractor-options/i18n"
+
+func UseTagged() {
+	i18n.Translate("tagged.key")
+}
@@ -26,6 +26,7 @@ func UseMessagesTranslate(ctx context.Context) {
 	// Use zipcode twice.
 	tr.Translate(context.Background(), "zipcode", map[string]any{"user": "john"})
 	tr.Translate(context.Background(), "zipcode", map[string]any{"user": "john"})
+	tr.Translate(context.Background(), "zipcode!upper", map[string]any{"user": "john"})
 	fmt.Println(unusedVar)
 }
 
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"context"
+
+	"github.com/wvell/messages"
+)
+
+var tr *messages.Translator
+
+func Welcome(ctx context.Context, name string) (string, string) {
+	return tr.Translate(ctx, "welcome.login", map[string]any{"user": name}),
+		tr.Translate(ctx, "button.save!upper", nil)
+}
+
+func Errors(ctx context.Context) string {
+	return tr.Translate(ctx, "errors.min_length", nil)
+}
@@ -0,0 +1,19 @@
+package rename
+
+import (
+	"context"
+
+	"github.com/wvell/messages"
+)
+
+const userKey = "user"
+
+var tr *messages.Translator
+
+func Welcome(ctx context.Context, name string) {
+	tr.Translate(ctx, "welcome", map[string]any{"user": name})
+	tr.Translate(ctx, "welcome!upper", map[string]any{"User": name, "count": 1})
+	tr.Translate(ctx, "welcome", map[string]any{userKey: name})
+	tr.Translate(ctx, "welcome", map[string]any{"username": name})
+	tr.Translate(ctx, "bye", map[string]any{"user": name})
+}
@@ -0,0 +1,268 @@
+// Package tiny is a small Translator for tinygo and WebAssembly, e.g. an edge worker that serves the same catalogs as the server.
+// It loads the compiled catalogs of messages.MarshalBundle and "msgextractor pack", and has no dependencies on afero, go/packages,
+// golang.org/x/text or reflection.
+//
+// The messages are formatted like messages.Translator formats them without options: placeholders, capitalized placeholders like :User,
+// escaped placeholders like \:user and the attributes of the :attribute replacement. Modifiers, formatters, conditions, templates and
+// region overrides are not supported, a placeholder with a modifier is replaced by the plain value. Replacements of the basic types
+// are formatted like messages.Translator formats them, other types, like named types, structs and maps, are replaced with nothing.
+package tiny
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/wvell/messages/internal/format"
+	"github.com/wvell/messages/internal/wire"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var (
+	ErrInvalidCatalog = fmt.Errorf("invalid catalog")
+)
+
+// AttributeKey is the replacement that is looked up in the attributes of the language, see messages.AttributeKey.
+const AttributeKey = "attribute"
+
+// placeholderRe matches the placeholders of a message, it is the same as the placeholders of the messages package.
+var placeholderRe = regexp.MustCompile(format.Placeholder)
+
+// Translator translates the messages of a compiled catalog.
+type Translator struct {
+	languages       map[string]*catalog
+	defaultLanguage string
+}
+
+// catalog holds the messages and attributes of a language.
+type catalog struct {
+	messages   map[string]string
+	attributes map[string]string
+}
+
+// FromBundle returns a Translator for the protobuf Bundle, see messages.MarshalBundle. The default language, e.g. "en", is used
+// for the languages that the bundle does not have and for the attributes that are missing in a language, it can be empty.
+// Like messages.Translator, a key that is missing in a language that the bundle has is not looked up in the default language.
+func FromBundle(data []byte, defaultLanguage string) (*Translator, error) {
+	t := &Translator{languages: make(map[string]*catalog), defaultLanguage: normalizeLanguage(defaultLanguage)}
+	if err := t.add(data); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// FromPacks returns a Translator for the language packs of "msgextractor pack", see FromBundle for the default language.
+func FromPacks(defaultLanguage string, packs ...[]byte) (*Translator, error) {
+	t := &Translator{languages: make(map[string]*catalog), defaultLanguage: normalizeLanguage(defaultLanguage)}
+	for _, pack := range packs {
+		bundle, err := unpack(pack)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := t.add(bundle); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Translate translates the key in the language, e.g. "nl-BE" or "nl_BE". The base language, and then the default language, is used
+// when the catalog has no messages for the language. The key is returned when the language has no message for it.
+func (t *Translator) Translate(lang, key string, replacements map[string]any) string {
+	c, message, ok := t.lookup(normalizeLanguage(lang), key)
+	if !ok {
+		return key
+	}
+
+	return placeholderRe.ReplaceAllStringFunc(message, func(placeholder string) string {
+		// Remove the backslash of escaped placeholders.
+		if strings.HasPrefix(placeholder, `\`) {
+			return placeholder[1:]
+		}
+
+		match := placeholderRe.FindStringSubmatch(placeholder)
+		name := strings.ToLower(match[2])
+
+		value, ok := replacements[name]
+		if !ok {
+			return ""
+		}
+
+		formatted, _ := format.Basic(value)
+		if name == AttributeKey {
+			if attribute, ok := c.attribute(t, formatted); ok {
+				formatted = attribute
+			}
+		}
+
+		if formatted != "" && unicode.IsUpper(rune(match[2][0])) {
+			runes := []rune(formatted)
+			runes[0] = unicode.ToUpper(runes[0])
+			formatted = string(runes)
+		}
+
+		return formatted
+	})
+}
+
+// lookup returns the catalog of the language, its base language or the default language, and the message of the key in it.
+func (t *Translator) lookup(lang, key string) (*catalog, string, bool) {
+	candidates := []string{lang}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		candidates = append(candidates, base)
+	}
+	if t.defaultLanguage != "" {
+		candidates = append(candidates, t.defaultLanguage)
+	}
+
+	for _, candidate := range candidates {
+		if c, ok := t.languages[candidate]; ok {
+			message, ok := c.messages[key]
+			return c, message, ok
+		}
+	}
+
+	return nil, "", false
+}
+
+// attribute returns the attribute of the language, or of the default language when the language does not have it.
+func (c *catalog) attribute(t *Translator, name string) (string, bool) {
+	if attribute, ok := c.attributes[name]; ok {
+		return attribute, true
+	}
+
+	if defaults, ok := t.languages[t.defaultLanguage]; ok {
+		attribute, ok := defaults.attributes[name]
+		return attribute, ok
+	}
+
+	return "", false
+}
+
+// normalizeLanguage returns the language like "nl-BE" for "nl_be" or "nl-BE".
+func normalizeLanguage(lang string) string {
+	base, region, ok := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-")
+	if !ok {
+		return strings.ToLower(base)
+	}
+
+	return strings.ToLower(base) + "-" + strings.ToUpper(region)
+}
+
+// add decodes the languages of the bundle.
+func (t *Translator) add(bundle []byte) error {
+	return consumeFields(bundle, func(num protowire.Number, value []byte) error {
+		if num != wire.BundleLanguages {
+			return nil
+		}
+
+		lang, msgs, err := consumeMapEntry(value)
+		if err != nil {
+			return err
+		}
+
+		c := &catalog{messages: make(map[string]string), attributes: make(map[string]string)}
+		err = consumeFields(msgs, func(num protowire.Number, value []byte) error {
+			if num != wire.MessagesMessages && num != wire.MessagesAttributes {
+				return nil
+			}
+
+			key, value, err := consumeMapEntry(value)
+			if err != nil {
+				return err
+			}
+
+			if num == wire.MessagesMessages {
+				c.messages[string(key)] = string(value)
+			} else {
+				c.attributes[string(key)] = string(value)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		t.languages[normalizeLanguage(string(lang))] = c
+		return nil
+	})
+}
+
+// unpack returns the bundle of a language pack and verifies its checksum, see messages.Pack.
+func unpack(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCatalog, err)
+	}
+
+	bundle, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCatalog, err)
+	}
+
+	checksum, ok := strings.CutPrefix(zr.Comment, "sha256:")
+	sum := sha256.Sum256(bundle)
+	if !ok || hex.EncodeToString(sum[:]) != checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidCatalog)
+	}
+
+	return bundle, nil
+}
+
+// consumeFields calls fn for every length-delimited field in b, other fields are skipped.
+func consumeFields(b []byte, fn func(num protowire.Number, value []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("%w: %w", ErrInvalidCatalog, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return fmt.Errorf("%w: %w", ErrInvalidCatalog, protowire.ParseError(n))
+			}
+			b = b[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return fmt.Errorf("%w: %w", ErrInvalidCatalog, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		if err := fn(num, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// consumeMapEntry returns the key and value of a map entry.
+func consumeMapEntry(b []byte) (key, value []byte, err error) {
+	err = consumeFields(b, func(num protowire.Number, v []byte) error {
+		switch num {
+		case wire.MapKey:
+			key = v
+		case wire.MapValue:
+			value = v
+		}
+
+		return nil
+	})
+
+	return key, value, err
+}
@@ -0,0 +1,91 @@
+package tiny
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/messages"
+)
+
+func languages(t *testing.T) map[messages.LanguageID]*messages.RawMessages {
+	en, err := messages.ParseLanguage("en")
+	require.NoError(t, err)
+	nl, err := messages.ParseLanguage("nl")
+	require.NoError(t, err)
+	nlBE, err := messages.ParseLanguage("nl-BE")
+	require.NoError(t, err)
+
+	return map[messages.LanguageID]*messages.RawMessages{
+		en: {
+			Messages:   map[string]string{"welcome": "Welcome :User", "required": "The :attribute is required", "items": "You have :count|compact items", "bye": "Bye", "value": "Value :value"},
+			Attributes: map[string]string{"email": "email address"},
+		},
+		nl:   {Messages: map[string]string{"welcome": "Welkom :User", "required": "Het :attribute is verplicht", "price": `Prijs \:price`}, Attributes: map[string]string{}},
+		nlBE: {Messages: map[string]string{"welcome": "Dag :User"}, Attributes: map[string]string{}},
+	}
+}
+
+func TestFromBundle(t *testing.T) {
+	tr, err := FromBundle(messages.MarshalBundle(languages(t), messages.Version{ETag: "v1"}), "en")
+	require.NoError(t, err)
+
+	require.Equal(t, "Welcome Jan", tr.Translate("en", "welcome", map[string]any{"user": "jan"}))
+	require.Equal(t, "Dag Jan", tr.Translate("nl_BE", "welcome", map[string]any{"user": "jan"}))
+	require.Equal(t, "Het email address is verplicht", tr.Translate("nl", "required", map[string]any{"attribute": "email"}))
+	require.Equal(t, `Prijs :price`, tr.Translate("nl", "price", map[string]any{"price": 3}))
+	require.Equal(t, "You have 3 items", tr.Translate("en", "items", map[string]any{"count": 3}))
+	require.Equal(t, "Bye", tr.Translate("fr", "bye", nil))
+	// A key that is missing in a language of the bundle is not looked up in the default language, like messages.Translator.
+	require.Equal(t, "bye", tr.Translate("nl", "bye", nil))
+	require.Equal(t, "missing", tr.Translate("nl", "missing", nil))
+
+	_, err = FromBundle([]byte{0xff}, "en")
+	require.ErrorIs(t, err, ErrInvalidCatalog)
+}
+
+func TestFromPacks(t *testing.T) {
+	var packs [][]byte
+	for lang, raw := range languages(t) {
+		data, err := (&messages.Pack{Language: lang, Messages: raw}).MarshalBinary()
+		require.NoError(t, err)
+		packs = append(packs, data)
+	}
+
+	tr, err := FromPacks("en", packs...)
+	require.NoError(t, err)
+	require.Equal(t, "Welkom Jan", tr.Translate("nl", "welcome", map[string]any{"user": "jan"}))
+
+	_, err = FromPacks("en", packs[0][:len(packs[0])-4])
+	require.ErrorIs(t, err, ErrInvalidCatalog)
+}
+
+type stringer struct{}
+
+func (stringer) String() string { return "stringer" }
+
+// The formatting is the same as the messages package for the supported features.
+func TestSameAsTranslator(t *testing.T) {
+	loader := messages.NewPushLoader()
+	loader.Push(languages(t), messages.Version{ETag: "v1"})
+
+	tr, err := messages.NewTranslatorFromLoader(context.Background(), loader, messages.WithDefaultLanguage(messages.LanguageID{Language: "en"}))
+	require.NoError(t, err)
+
+	tinyTr, err := FromBundle(messages.MarshalBundle(languages(t), messages.Version{}), "en")
+	require.NoError(t, err)
+
+	replacements := map[string]any{"user": "jan", "attribute": "email", "price": 3}
+	for _, lang := range []string{"en", "nl", "nl-BE", "nl-NL", "fr"} {
+		ctx := messages.ToCtx(context.Background(), lang)
+		for _, key := range []string{"welcome", "required", "price", "bye"} {
+			require.Equal(t, tr.Translate(ctx, messages.Key(key), replacements), tinyTr.Translate(lang, key, replacements), "%s %s", lang, key)
+		}
+	}
+
+	ctx := messages.ToCtx(context.Background(), "en")
+	for _, value := range []any{"text", 3, int8(-3), uint16(3), 1.5, float32(1.25), true, []string{"a", "b"}, stringer{}, struct{}{}} {
+		replacements := map[string]any{"value": value}
+		require.Equal(t, tr.Translate(ctx, "value", replacements), tinyTr.Translate("en", "value", replacements), "%T", value)
+	}
+}
@@ -5,10 +5,18 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unicode"
 
 	"github.com/spf13/afero"
+	"github.com/wvell/messages/internal/format"
+	"github.com/wvell/messages/internal/sorted"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
@@ -18,6 +26,9 @@ const (
 
 	// AttributeKey is the key that is used for the :attribute replacement.
 	AttributeKey = "attribute"
+
+	// RegionSeparator separates a key from the region in region overrides, e.g. "color@GB".
+	regionSeparator = "@"
 )
 
 // Key is a type that represents a translation key.
@@ -39,38 +50,102 @@ var isFile = regexp.MustCompile(`^([a-zA-Z]{2}(?:[-_][a-zA-Z]{2})?)\.json$`)
 //		}
 //	}
 //
+// A translation file can have region overrides for single messages. The override is used when the context has the region.
+// This avoids a separate en_GB.json file for a few words:
+//
+//	{
+//		"color": "color",
+//		"color@GB": "colour"
+//	}
+//
 // A translation file can have attributes. An attribute changes the replacement value before it is inserted into the translation.
 // This can be useful for validation rules. Field names often have name like first_name or last_name.
 // When using the translation example above:
 //
 //	translator.Translate("validation.required", map[string]any{"attribute": "addr_street"})
 //	Output: Street is required.
+//
+// Use Reload to reload the translation files when they have changed.
 func NewTranslator(fs afero.Fs, dir string, opts ...Opt) (*Translator, error) {
 	t := newTranslator(opts...)
+	t.load = t.dirLoader(NewFileStore(fs, dir, t.parserOpts...))
 
-	parser := NewParser(fs)
-
-	files, err := parser.TranslationFilesFromDir(dir)
+	err := t.Reload(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("reading translations files: %w", err)
+		return nil, err
 	}
 
-	for languageID, file := range files {
-		messages, err := parser.parseFile(file)
+	return t, nil
+}
+
+// dirLoader returns the loadFunc for the translation files and the metadata file in the directory of the store.
+func (t *Translator) dirLoader(store *FileStore) loadFunc {
+	return func(ctx context.Context, since Version) (*catalog, error) {
+		version, err := store.version()
 		if err != nil {
-			return nil, fmt.Errorf("reading file %s: %w", file, err)
+			return nil, err
 		}
 
-		t.languages[languageID] = messages
-	}
+		if version.ETag == since.ETag {
+			return nil, ErrNotModified
+		}
 
-	return t, nil
+		// A new parser is used for every load, so the interned strings of old translations are not kept.
+		parser := NewParser(store.fs, t.parserOpts...)
+
+		files, err := parser.TranslationFilesFromDir(store.dir)
+		if err != nil {
+			return nil, fmt.Errorf("reading translations files: %w", err)
+		}
+
+		layers, err := t.loadLayers(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		c := &catalog{languages: make(map[string]*messages), loadErrors: make(map[LanguageID]error), version: version}
+		for _, languageID := range sorted.Keys(files) {
+			file := files[languageID]
+
+			layer := layerFor(layers, languageID)
+			if t.serveArchive {
+				layer, err = store.archiveLayer(languageID, layer)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			messages, err := parser.parseFile(languageID, file, layer)
+			if err == nil {
+				err = t.addLanguage(c.languages, languageID, messages)
+			}
+
+			if err != nil {
+				err = t.isolateLoadError(c, languageID, fmt.Errorf("reading file %s: %w", file, err))
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		metadata, err := parser.MetadataFromDir(store.dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.setMetadata(metadata); err != nil {
+			return nil, err
+		}
+
+		return c, nil
+	}
 }
 
 // NewTranslator creates a new translator with the given options.
 func newTranslator(opts ...Opt) *Translator {
 	t := &Translator{
-		languages: make(map[string]*messages),
+		modifiers:  defaultModifiers(),
+		formatters: defaultFormatters(),
 	}
 
 	for _, opt := range opts {
@@ -80,60 +155,298 @@ func newTranslator(opts ...Opt) *Translator {
 	return t
 }
 
+// addLanguage prepares the messages for the given language and adds them to languages.
+func (t *Translator) addLanguage(languages map[string]*messages, languageID string, messages *messages) error {
+	messages.lang = language.Make(languageID)
+	messages.modifiers = t.modifiers
+	messages.formatters = t.formatters
+	messages.titleCaseWords = t.titleCaseWords
+	messages.bidiIsolate = t.bidiIsolation && isRTL(messages.lang)
+
+	err := messages.validateModifiers()
+	if err != nil {
+		return err
+	}
+
+	err = t.compileTemplates(messages)
+	if err != nil {
+		return err
+	}
+
+	err = t.compileICUMessages(messages)
+	if err != nil {
+		return err
+	}
+
+	languages[languageID] = messages
+	return nil
+}
+
+// linkDefaultLanguage makes the messages of the default language the fallback of all other languages.
+func (t *Translator) linkDefaultLanguage(languages map[string]*messages) {
+	if t.defaultLanguage.Empty() {
+		return
+	}
+
+	defaultMessages, ok := languages[t.defaultLanguage.String()]
+	if !ok {
+		defaultMessages, ok = languages[t.defaultLanguage.Language]
+	}
+
+	if !ok {
+		return
+	}
+
+	for _, messages := range languages {
+		if messages != defaultMessages {
+			messages.fallback = defaultMessages
+		}
+	}
+}
+
 // Translator holds translations for all Languages. Use the Translate message to look up translations.
 type Translator struct {
-	languages map[string]*messages
+	// Current holds the loaded translations, it is replaced when the translations are reloaded.
+	current atomic.Pointer[catalog]
+	// Load loads the translations, see Reload.
+	load loadFunc
+	// ReloadMu serializes the reloads.
+	reloadMu sync.Mutex
 	// Optional default language to use when no language is set in the context or the selected language has no matching translation.
 	defaultLanguage LanguageID
+	// Modifiers that can be used in placeholders, e.g. :total|percent.
+	modifiers map[string]Modifier
+	// Formatters format replacement values by type when the placeholder has no modifier.
+	formatters map[reflect.Type]formatter
+	// Options for the parser that reads the translation files.
+	parserOpts []ParserOpt
+	// Title case every word of capitalized replacements instead of only the first letter.
+	titleCaseWords bool
+	// Load the languages that are valid when other languages fail to load, see WithLenientLoad.
+	lenientLoad bool
+	// Post processors that are applied in order to the translated message.
+	postProcessors []PostProcessor
+	// Key rewriters that are applied in order to the key before it is looked up.
+	keyRewriters []KeyRewriter
+	// Providers of replacement values from the context, keyed by the lowercase replacement name.
+	providers map[string]ReplacementProvider
+	// Sources of message overrides that are consulted before the translations, see WithOverrides.
+	overrideSources []OverrideSource
+	// Layers of messages below the translations, e.g. the built-in validation messages, see WithValidationMessages.
+	layers []Loader
+	// Prefix the translated messages with their key and language, see WithDebugMarkers.
+	debugMarkers bool
+	// Wrap the translated messages with invisible markers of their key, see WithInContextMarkers.
+	inContextMarkers bool
+	// Execute the messages that contain "{{" as templates, see WithTemplates.
+	templates bool
+	// The syntax of the messages, see WithMessageFormat.
+	messageFormat MessageFormat
+	// MissingHook is called for missing translations and, for the sampled placeholder audits, placeholders without a replacement.
+	missingHook      MissingHook
+	placeholderAudit float64
+	// TypeMismatchHook is called for replacements that do not match the type in the metadata, see WithTypeCheck.
+	typeMismatchHook TypeMismatchHook
+	// Metadata of the keys for NewTranslatorFromLoader, see WithMetadata.
+	metadata Metadata
+	// Usage counts the translations, see WithUsage.
+	usage *Usage
+	// The languages and minimum number of keys that Ready checks, see WithReadyLanguages and WithReadyMinKeys.
+	readyLanguages []LanguageID
+	readyMinKeys   int
+	// TemplateSandbox limits the templates, see WithTemplateSandbox.
+	templateSandbox *TemplateSandbox
+	// Isolate the replacement values in right-to-left languages, see WithBidiIsolation.
+	bidiIsolation bool
+	// Serve the archived messages below the translation files, see WithArchive.
+	serveArchive bool
+	// LoadTimeout limits the time of a load, see WithLoadTimeout.
+	loadTimeout time.Duration
+	// RefreshMaxAge is the age of the translations after which they are reloaded in the background, see WithBackgroundRefresh.
+	refreshMaxAge  time.Duration
+	refreshOnError func(error)
+	// LastRefresh is the time of the last background reload in unix nanoseconds, refreshing is true while it runs.
+	lastRefresh atomic.Int64
+	refreshing  atomic.Bool
 }
 
+// KeyRewriter rewrites the requested key before it is looked up, e.g. for experiments or tenant specific messages.
+type KeyRewriter func(ctx context.Context, key Key) Key
+
+// PostProcessor changes the translated message of key for the language, e.g. to add non-breaking spaces or smart quotes.
+type PostProcessor func(lang LanguageID, key Key, out string) string
+
 // Opt is a functional option for the Translator.
 type Opt func(*Translator)
 
 // Translate translates the key for the given lang(in ctx).
+// Translations without replacements are remembered when the ctx has a memo, see WithMemo.
 func (t *Translator) Translate(ctx context.Context, key Key, replacements map[string]any) string {
-	messages := t.messages(ctx)
+	if t.usage != nil {
+		t.usage.count(ctx, t.defaultLanguage, key)
+	}
+
+	return t.translateMemo(ctx, key, replacements)
+}
+
+// translateMemo translates the key with the memo of the ctx, see WithMemo. The translation is not counted in the usage.
+func (t *Translator) translateMemo(ctx context.Context, key Key, replacements map[string]any) string {
+	memo := memoFromCtx(ctx)
+	if memo == nil || len(replacements) > 0 {
+		return t.translate(ctx, key, replacements)
+	}
+
+	entry := memoEntry{translator: t, lang: FromCtx(ctx), key: key}
+	if out, ok := memo.get(entry); ok {
+		return out
+	}
+
+	out := t.translate(ctx, key, replacements)
+	memo.set(entry, out)
+
+	return out
+}
+
+func (t *Translator) translate(ctx context.Context, key Key, replacements map[string]any) string {
+	t.refreshIfStale()
+
+	messages, region := t.messages(ctx)
 	if messages == nil {
+		if t.missingHook != nil {
+			t.missingHook(ctx, Missing{Language: FromCtx(ctx), Key: key})
+		}
+
 		return string(key)
 	}
 
-	return messages.format(key, replacements)
+	t.checkTypes(ctx, messages, region, key, replacements)
+
+	key = t.rewriteKey(ctx, messages, region, key)
+	key = t.activeVariant(messages, region, key)
+	messages = t.override(ctx, messages, region, key)
+	replacements = t.provideReplacements(ctx, messages, region, key, replacements)
+	replacements = convertTimes(ctx, replacements)
+	t.reportMissing(ctx, messages, region, key, replacements)
+
+	out := messages.format(key, region, replacements)
+
+	if len(t.postProcessors) > 0 {
+		lang := messages.id(region)
+		for _, postProcess := range t.postProcessors {
+			out = postProcess(lang, key, out)
+		}
+	}
+
+	if t.debugMarkers {
+		out = "[" + string(key) + "|" + messages.id(region).String() + "] " + out
+	}
+
+	if t.inContextMarkers {
+		out = inContextMarker(key, out)
+	}
+
+	return out
 }
 
-// messages returns the messages for the given language in the context.
-func (t *Translator) messages(ctx context.Context) *messages {
+// rewriteKey applies the key rewriters to the key.
+// The original key is used when the rewritten key has no translation, so an experiment does not need a message in every language.
+func (t *Translator) rewriteKey(ctx context.Context, messages *messages, region string, key Key) Key {
+	if len(t.keyRewriters) == 0 {
+		return key
+	}
+
+	// The rewriters get the key without a casing directive, the directive is added to the rewritten key.
+	base, directive := key, ""
+	if b, _, ok := splitCasingDirective(key); ok {
+		base, directive = b, string(key[len(b):])
+	}
+
+	rewritten := base
+	for _, rewrite := range t.keyRewriters {
+		rewritten = rewrite(ctx, rewritten)
+	}
+
+	if _, ok := messages.lookup(rewritten, region); !ok {
+		return key
+	}
+
+	return rewritten + Key(directive)
+}
+
+// messages returns the messages for the given language in the context and the region that is used for region overrides.
+func (t *Translator) messages(ctx context.Context) (*messages, string) {
 	// Get the language from the context.
 	// Fallback to the defaultLanguage. If no language can be detected return the translation key.
-	lang := FromCtx(ctx)
-	if lang.Empty() {
-		if t.defaultLanguage.Empty() {
-			return nil
+	c := t.current.Load()
+	if c == nil {
+		return nil, ""
+	}
+
+	// Walk the acceptable languages in priority order, first with the region and then without.
+	for _, lang := range LanguagesFromCtx(ctx) {
+		if messages, ok := c.languages[lang.String()]; ok {
+			return messages, lang.Region
 		}
 
-		lang = t.defaultLanguage
+		if messages, ok := c.languages[lang.Language]; ok {
+			return messages, lang.Region
+		}
 	}
 
-	// Try to find a message that matches the language and the region if provided.
-	messages, ok := t.languages[lang.String()]
-	if ok {
-		return messages
+	// If a defaultLanguage is provided we retry using the defaultLanguage.
+	if !t.defaultLanguage.Empty() {
+		messages, ok := c.languages[t.defaultLanguage.String()]
+		if ok {
+			return messages, t.defaultLanguage.Region
+		}
+
+		messages, ok = c.languages[t.defaultLanguage.Language]
+		if ok {
+			return messages, t.defaultLanguage.Region
+		}
 	}
 
-	// Check if we can find a language without a region.
-	messages, ok = t.languages[lang.Language]
-	if ok {
-		return messages
+	return nil, ""
+}
+
+// WithTitleCaseWords title cases every word of capitalized replacements like :User, so "new york" becomes "New York".
+// By default only the first letter is capitalized. Use the title modifier, :user|title, to title case a single placeholder.
+func WithTitleCaseWords() Opt {
+	return func(t *Translator) {
+		t.titleCaseWords = true
 	}
+}
 
-	// If a defaultLanguage is provided and it is different from the current lang we retry using the defaultLanguage.
-	if !t.defaultLanguage.Empty() && t.defaultLanguage != lang {
-		messages, ok := t.languages[t.defaultLanguage.String()]
-		if ok {
-			return messages
-		}
+// WithDebugMarkers prefixes every translated message with its key and language, e.g. "[welcome.login|nl] Welkom Jan",
+// so a string on a screenshot can be mapped back to the translation file. Use it in QA environments only.
+func WithDebugMarkers() Opt {
+	return func(t *Translator) {
+		t.debugMarkers = true
 	}
+}
 
-	return nil
+// WithPostProcess adds a post processor that is applied to every translated message after it is formatted.
+// Post processors are applied in the order they are added.
+func WithPostProcess(postProcess PostProcessor) Opt {
+	return func(t *Translator) {
+		t.postProcessors = append(t.postProcessors, postProcess)
+	}
+}
+
+// WithKeyRewrite adds a key rewriter that can change the requested key before it is looked up,
+// e.g. to translate "welcome.login" with "welcome.login.v2" for an experiment. Key rewriters are applied in the order they are added.
+// The requested key is used when the rewritten key has no translation.
+func WithKeyRewrite(rewrite KeyRewriter) Opt {
+	return func(t *Translator) {
+		t.keyRewriters = append(t.keyRewriters, rewrite)
+	}
+}
+
+// WithParserOpts uses the given options for the parser that reads the translation files, e.g. WithWarnings.
+func WithParserOpts(opts ...ParserOpt) Opt {
+	return func(t *Translator) {
+		t.parserOpts = append(t.parserOpts, opts...)
+	}
 }
 
 // Use the given default language when the ctx has no language set or the language has no translations.
@@ -145,61 +458,260 @@ func WithDefaultLanguage(lang LanguageID) Opt {
 
 // Messages holds all messages for a specific language.
 type messages struct {
-	messages map[Key]message
+	// Messages holds the messages in a flat slice, index maps a key to its position in the slice.
+	// This uses less memory than a map of messages for large catalogs.
+	messages []message
+	index    map[Key]int32
+	// Regions holds the region overrides of messages per region, e.g. "color@GB".
+	regions map[string]map[Key]message
 	// Attributes can be used to transform the :attribute replacement before they are inserted into the translated message.
 	// This is used for validation field names.
 	attributes map[string]string
+	// Fallback holds the messages of the default language, nil for the default language itself.
+	// Attributes that are missing in this language are looked up in the fallback.
+	fallback *messages
+	// The language of the messages, used by modifiers for locale aware formatting.
+	lang language.Tag
+	// Modifiers that can be used in placeholders.
+	modifiers map[string]Modifier
+	// Formatters format replacement values by type.
+	formatters map[reflect.Type]formatter
+	// Title case every word of capitalized replacements.
+	titleCaseWords bool
+	// Wrap the replacement values with the bidi isolation characters, set for right-to-left languages.
+	bidiIsolate bool
+	// Overrides holds the override of a message for a single translation, see WithOverrides.
+	overrides map[Key]message
+	// OverrideCache holds the compiled overrides of these messages by overrideCacheKey, see Translator.override.
+	// It is dropped with the messages when the translations are reloaded.
+	overrideCache *sync.Map
+	// Source is the file or other source of the messages, see Resolve.
+	source string
+	// LayerKeys holds the keys of the messages that come from a layer below the translations, see WithValidationMessages.
+	layerKeys map[Key]struct{}
+}
+
+// validateModifiers checks that all modifiers that are used in the messages exist.
+func (m *messages) validateModifiers() error {
+	// The keys are validated in sorted order, so the same files always return the same error.
+	for _, key := range sorted.Keys(m.index) {
+		if err := m.validateMessageModifiers(key, m.messages[m.index[key]]); err != nil {
+			return err
+		}
+	}
+
+	for _, region := range sorted.Keys(m.regions) {
+		regionMessages := m.regions[region]
+
+		for _, key := range sorted.Keys(regionMessages) {
+			if err := m.validateMessageModifiers(key, regionMessages[key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateMessageModifiers checks that all modifiers that are used in the message exist.
+// A pipe with a name that is not a modifier and has no argument, like the |word in ":word|word", is text after the placeholder.
+func (m *messages) validateMessageModifiers(key Key, msg message) error {
+	if msg.condition != nil {
+		if err := m.validateMessageModifiers(key, msg.condition.then); err != nil {
+			return err
+		}
+
+		return m.validateMessageModifiers(key, msg.condition.otherwise)
+	}
+
+	for i, replacement := range msg.replacements {
+		if _, ok := m.modifiers[replacement.modifier]; replacement.modifier == "" || ok {
+			continue
+		}
+
+		if strings.HasSuffix(replacement.replacementKey, ")") {
+			return fmt.Errorf("%w: message %q modifier %q", ErrUnknownModifier, key, replacement.modifier)
+		}
+
+		msg.replacements[i].suffix = "|" + replacement.modifier
+		msg.replacements[i].modifier = ""
+	}
+
+	return nil
+}
+
+// add adds the message for key.
+func (m *messages) add(key Key, msg message) {
+	if i, ok := m.index[key]; ok {
+		m.messages[i] = msg
+		return
+	}
+
+	m.index[key] = int32(len(m.messages))
+	m.messages = append(m.messages, msg)
+}
+
+// addRegionOverride adds a message that overrides key for the given region.
+func (m *messages) addRegionOverride(region string, key Key, msg message) {
+	if m.regions[region] == nil {
+		m.regions[region] = make(map[Key]message)
+	}
+
+	m.regions[region][key] = msg
+}
+
+// lookup returns the message for the key, the region override is returned if it exists.
+// The keys in the messages are NFC normalized, a key in another normalization form is normalized before it is looked up.
+func (m *messages) lookup(key Key, region string) (message, bool) {
+	if message, ok := m.overrides[key]; ok {
+		return message, true
+	}
+
+	message, ok := m.regions[region][key]
+	if !ok {
+		var i int32
+		if i, ok = m.index[key]; ok {
+			message = m.messages[i]
+		}
+	}
+
+	if !ok && !norm.NFC.IsNormalString(string(key)) {
+		return m.lookup(Key(norm.NFC.String(string(key))), region)
+	}
+
+	return message, ok
+}
+
+// attribute returns the value of the attribute, the attribute of the default language is returned if it is missing.
+func (m *messages) attribute(name string) (string, bool) {
+	value, ok := m.attributes[name]
+	if !ok && m.fallback != nil {
+		value, ok = m.fallback.attributes[name]
+	}
+
+	return value, ok
+}
+
+// tag returns the language of the messages with the given region.
+func (m *messages) tag(region string) language.Tag {
+	regionTag, err := language.ParseRegion(region)
+	if err != nil {
+		return m.lang
+	}
+
+	lang, _ := language.Compose(m.lang, regionTag)
+	return lang
+}
+
+// id returns the language id of the messages with the given region.
+func (m *messages) id(region string) LanguageID {
+	base, _ := m.lang.Base()
+	id := LanguageID{Language: base.String(), Region: region}
+
+	// Use the region of the translation file, e.g. en-US, if the context has no region.
+	if tagRegion, confidence := m.lang.Region(); region == "" && confidence == language.Exact {
+		id.Region = tagRegion.String()
+	}
+
+	return id
 }
 
 // Format formats the message with the given replacements.
-func (m *messages) format(translationKey Key, replacements map[string]any) string {
-	message, ok := m.messages[translationKey]
+// The region override of the message is used if it exists for the given region.
+func (m *messages) format(translationKey Key, region string, replacements map[string]any) string {
+	message, ok := m.lookup(translationKey, region)
 	if !ok {
+		// A key with a casing directive, like "button.save!upper", formats the key without the directive and changes the case.
+		if base, casing, ok := splitCasingDirective(translationKey); ok {
+			if _, ok := m.lookup(base, region); ok {
+				return casing(m.tag(region), m.format(base, region, replacements))
+			}
+		}
+
 		return string(translationKey)
 	}
 
+	// Select the message of conditional messages.
+	for message.condition != nil {
+		message = message.condition.choose(replacements)
+	}
+
+	if message.template != nil {
+		out, ok := message.template.execute(replacements)
+		if !ok {
+			return string(translationKey)
+		}
+
+		return out
+	}
+
+	if message.icu != nil {
+		var b strings.Builder
+		m.formatICU(&b, m.tag(region), message.icu.parts, replacements, "")
+		return b.String()
+	}
+
+	// Modifiers and formatters get the region of the context, so region specific formatting like phone numbers works
+	// for messages without a region.
+	lang := m.tag(region)
+
 	// Replace all placeholders in the message.
-	translationMessage := message.message
-	for replacementName, replacement := range message.replacements {
+	return messageRe.ReplaceAllStringFunc(message.message, func(placeholder string) string {
+		// Remove the backslash of escaped placeholders.
+		if strings.HasPrefix(placeholder, `\`) {
+			return placeholder[1:]
+		}
+
+		replacement, _ := message.replacement(placeholder)
+
 		var formattedValue string
 
 		// Check if the replacement is given by the caller.
-		value, ok := replacements[replacementName]
+		value, ok := replacements[replacement.name]
 		if ok {
-			// No match formattedValue will be empty.
-			formattedValue = formatReplacement(value)
+			formattedValue = m.formatValue(lang, value, replacement.modifier, replacement.arg)
 		}
 
 		// Check if the replacement is :attribute.
-		if replacementName == AttributeKey {
-			if value, ok := m.attributes[formattedValue]; ok {
+		if replacement.name == AttributeKey {
+			if value, ok := m.attribute(formattedValue); ok {
 				formattedValue = value
 			}
 		}
 
 		// Uppercase the replacement if the replacemente indicated this.
-		if formattedValue != "" && replacement.isUpper {
+		if formattedValue != "" && replacement.isUpper && m.titleCaseWords {
+			formattedValue = titleModifier(lang, formattedValue, "")
+		} else if formattedValue != "" && replacement.isUpper {
 			runes := []rune(formattedValue)
 			runes[0] = unicode.ToUpper(runes[0])
 			formattedValue = string(runes)
 		}
 
-		translationMessage = strings.ReplaceAll(translationMessage, replacement.replacementKey, formattedValue)
+		if formattedValue != "" && m.bidiIsolate {
+			formattedValue = bidiIsolate(formattedValue)
+		}
+
+		return formattedValue + replacement.suffix
+	})
+}
+
+// formatValue formats a replacement value with the modifier, or with the formatter of its type when the modifier is empty.
+func (m *messages) formatValue(lang language.Tag, value any, modifier, arg string) string {
+	if modifier != "" {
+		return m.modifiers[modifier](lang, value, arg)
+	}
+
+	if formatter, ok := m.formatters[reflect.TypeOf(value)]; ok {
+		return formatter(lang, value)
 	}
 
-	return translationMessage
+	return formatReplacement(value)
 }
 
 func formatReplacement(value any) string {
-	switch v := value.(type) {
-	case string:
-		return v
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return fmt.Sprintf("%d", v)
-	case float32, float64:
-		return fmt.Sprintf("%.2f", v)
-	case bool:
-		return fmt.Sprintf("%t", v)
+	if formatted, ok := format.Basic(value); ok {
+		return formatted
 	}
 
 	valueOf := reflect.ValueOf(value)
@@ -225,6 +737,9 @@ func formatReplacement(value any) string {
 			strSlice = append(strSlice, fmt.Sprintf("%s: %s", keyStr, valueStr))
 		}
 
+		// Map iteration order is random, sort the entries so the output is the same for every call.
+		slices.Sort(strSlice)
+
 		return strings.Join(strSlice, ", ")
 	}
 
@@ -234,15 +749,39 @@ func formatReplacement(value any) string {
 // Message represents a message for a specific language.
 type message struct {
 	message string
-	// Replacements holds the replacement options for the message.
-	// true indicates the replacement should be title cased. False indicates the replacement should be left as is.
-	replacements map[string]replacement
+	// Condition is set for conditional messages, the message is then selected at format time.
+	condition *condition
+	// Template is set for template messages, see WithTemplates.
+	template *messageTemplate
+	// ICU is set for ICU messages, see WithMessageFormat.
+	icu *icuMessage
+	// Replacements holds the replacement options for every placeholder in the message.
+	// A slice is used because messages have few placeholders, and it is smaller than a map.
+	replacements []replacement
+}
+
+// replacement returns the replacement for the placeholder as it is used in the message, e.g. ":User".
+func (m message) replacement(placeholder string) (replacement, bool) {
+	for _, r := range m.replacements {
+		if r.replacementKey == placeholder {
+			return r, true
+		}
+	}
+
+	return replacement{}, false
 }
 
 type replacement struct {
+	// The lowercase name of the replacement, this is the key in the replacements given by the caller.
+	name string
 	// Indicates if the replacement should be title cased.
 	isUpper bool
 	// Contains the complete replacement key as it is defined in the translation message.
 	// For the translation "Hello :User" this would be ":User".
 	replacementKey string
+	// Optional modifier and its argument, for ":total|percent(1)" this would be "percent" and "1".
+	modifier string
+	arg      string
+	// Suffix is the text after the placeholder when the pipe is not followed by a modifier, for ":word|word" this would be "|word".
+	suffix string
 }
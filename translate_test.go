@@ -2,6 +2,7 @@ package messages
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/afero"
@@ -174,3 +175,159 @@ func TestAttribute(t *testing.T) {
 	message := tr.Translate(ctx, "required", map[string]any{"attribute": "first_name"})
 	require.Equal(t, "First name is required", message)
 }
+
+func TestAttributeFallbackToDefaultLanguage(t *testing.T) {
+	en, err := ParseLanguage("en")
+	require.NoError(t, err)
+
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/attributes-fallback", WithDefaultLanguage(en))
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "nl")
+	require.NoError(t, err)
+
+	require.Equal(t, "Voornaam is verplicht", tr.Translate(ctx, "required", map[string]any{"attribute": "first_name"}))
+	require.Equal(t, "Street is verplicht", tr.Translate(ctx, "required", map[string]any{"attribute": "addr_street"}))
+	require.Equal(t, "Unknown is verplicht", tr.Translate(ctx, "required", map[string]any{"attribute": "unknown"}))
+
+	// Without a default language there is no fallback.
+	tr, err = NewTranslator(afero.NewOsFs(), "./testdata/attributes-fallback")
+	require.NoError(t, err)
+
+	require.Equal(t, "Addr_street is verplicht", tr.Translate(ctx, "required", map[string]any{"attribute": "addr_street"}))
+}
+
+func TestPostProcess(t *testing.T) {
+	var gotLang LanguageID
+	var gotKey Key
+
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/valid",
+		WithPostProcess(func(lang LanguageID, key Key, out string) string {
+			gotLang, gotKey = lang, key
+			return out + "!"
+		}),
+		WithPostProcess(func(_ LanguageID, _ Key, out string) string {
+			return "\u201c" + out + "\u201d"
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "en_US")
+	require.NoError(t, err)
+
+	message := tr.Translate(ctx, "welcome.login", map[string]any{"user": "jan"})
+	require.Equal(t, "\u201cWelcome Jan!\u201d", message)
+	require.Equal(t, LanguageID{Language: "en", Region: "US"}, gotLang)
+	require.Equal(t, Key("welcome.login"), gotKey)
+}
+
+func TestDebugMarkers(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/valid",
+		WithDebugMarkers(),
+		WithPostProcess(func(_ LanguageID, _ Key, out string) string {
+			return out + "!"
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx, err := WithLanguage(context.Background(), "nl")
+	require.NoError(t, err)
+
+	// The marker is added after the post processors.
+	require.Equal(t, "[welcome.login|nl] Welkom jan!", tr.Translate(ctx, "welcome.login", map[string]any{"user": "jan"}))
+}
+
+func TestKeyRewrite(t *testing.T) {
+	type bucketKey struct{}
+
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/key-rewrite", WithKeyRewrite(func(ctx context.Context, key Key) Key {
+		if bucket, ok := ctx.Value(bucketKey{}).(string); ok {
+			return key + Key("."+bucket)
+		}
+
+		return key
+	}))
+	require.NoError(t, err)
+
+	cases := []struct {
+		lang     string
+		bucket   string
+		key      Key
+		expected string
+	}{
+		{lang: "en", key: "welcome.login", expected: "Welcome Jan"},
+		{lang: "en", bucket: "v2", key: "welcome.login", expected: "Hi Jan, good to see you"},
+		{lang: "en", bucket: "v2", key: "welcome.login!upper", expected: "HI JAN, GOOD TO SEE YOU"},
+		// The rewritten key has no translation, the requested key is used.
+		{lang: "nl", bucket: "v2", key: "welcome.login", expected: "Welkom Jan"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.lang+"_"+c.bucket, func(t *testing.T) {
+			ctx, err := WithLanguage(context.Background(), c.lang)
+			require.NoError(t, err)
+
+			if c.bucket != "" {
+				ctx = context.WithValue(ctx, bucketKey{}, c.bucket)
+			}
+
+			require.Equal(t, c.expected, tr.Translate(ctx, c.key, map[string]any{"user": "jan"}))
+		})
+	}
+}
+
+func TestRegionOverrides(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/region-overrides")
+	require.NoError(t, err)
+
+	cases := []struct {
+		lang     string
+		key      Key
+		expected string
+	}{
+		{lang: "en", key: "color", expected: "Pick a color for john"},
+		{lang: "en-US", key: "color", expected: "Pick a color for john"},
+		{lang: "en-GB", key: "color", expected: "Pick a colour for john"},
+		// Not a region override, the part after the @ is not a region.
+		{lang: "en", key: "email@domain", expected: "Email"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.lang+"_"+string(c.key), func(t *testing.T) {
+			ctx, err := WithLanguage(context.Background(), c.lang)
+			require.NoError(t, err)
+
+			message := tr.Translate(ctx, c.key, map[string]any{"name": "john"})
+			require.Equal(t, c.expected, message)
+		})
+	}
+}
+
+func TestMixedCasePlaceholders(t *testing.T) {
+	_, err := NewTranslator(afero.NewOsFs(), "./testdata/invalid-translation")
+	var caseErr *ReplacementCaseError
+	require.ErrorAs(t, err, &caseErr)
+	require.Equal(t, filepath.Join("testdata", "invalid-translation", "en.json"), caseErr.File)
+	require.Equal(t, "invalid", caseErr.Key)
+
+	// The policy only applies to the given languages.
+	_, err = NewTranslator(afero.NewOsFs(), "./testdata/invalid-translation", WithParserOpts(WithMixedCasePlaceholders("nl")))
+	require.ErrorIs(t, err, ErrDuplicateReplacementWithDifferentCase)
+
+	for _, languages := range [][]string{nil, {"en"}} {
+		tr, err := NewTranslator(afero.NewOsFs(), "./testdata/invalid-translation", WithParserOpts(WithMixedCasePlaceholders(languages...)))
+		require.NoError(t, err)
+
+		ctx, err := WithLanguage(context.Background(), "en")
+		require.NoError(t, err)
+
+		message := tr.Translate(ctx, "invalid", map[string]any{"user": "john"})
+		require.Equal(t, "John should use the same format not like this john", message)
+	}
+}
+
+func TestFormatMapReplacementIsSorted(t *testing.T) {
+	for range 20 {
+		require.Equal(t, "a: 1, b: 2, c: 3", formatReplacement(map[string]int{"c": 3, "a": 1, "b": 2}))
+	}
+}
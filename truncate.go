@@ -0,0 +1,101 @@
+package messages
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// ellipses holds the ellipsis per language that differs from "…".
+var ellipses = map[string]string{
+	"zh": "……",
+}
+
+// truncateModifier truncates the value to at most the number of characters in the argument, an ellipsis is added when it is truncated.
+// Characters are user-perceived characters (grapheme clusters), so emoji like 👍🏽, family emoji and letters with combining accents are never split.
+// The ellipsis counts as one character, the value is not changed when the argument is missing or not positive.
+func truncateModifier(lang language.Tag, value any, arg string) string {
+	s := formatReplacement(value)
+
+	limit := intArg(arg, 0)
+	if limit <= 0 {
+		return s
+	}
+
+	base, _ := lang.Base()
+	ellipsis, ok := ellipses[base.String()]
+	if !ok {
+		ellipsis = "…"
+	}
+
+	// Cut is the end of the last grapheme that fits with the ellipsis.
+	cut, n := 0, 0
+	for i := 0; i < len(s); n++ {
+		if n == limit {
+			return strings.TrimRightFunc(s[:cut], unicode.IsSpace) + ellipsis
+		}
+
+		i += graphemeLen(s[i:])
+		if n < limit-1 {
+			cut = i
+		}
+	}
+
+	return s
+}
+
+// graphemeLen returns the length in bytes of the first grapheme cluster in s. It implements the rules of unicode
+// text segmentation (UAX #29) that matter for messages: CR LF, combining marks, variation selectors, emoji modifiers,
+// zero width joiner sequences, tag sequences and regional indicator pairs (flags).
+func graphemeLen(s string) int {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return 0
+	}
+
+	if r == '\r' && strings.HasPrefix(s[size:], "\n") {
+		return size + 1
+	}
+
+	// A flag is a pair of regional indicators.
+	if isRegionalIndicator(r) {
+		if next, nextSize := utf8.DecodeRuneInString(s[size:]); isRegionalIndicator(next) {
+			size += nextSize
+		}
+	}
+
+	for size < len(s) {
+		next, nextSize := utf8.DecodeRuneInString(s[size:])
+
+		switch {
+		case isGraphemeExtend(next):
+			size += nextSize
+		case next == '\u200d':
+			// The zero width joiner joins the next character, e.g. the emoji of a family.
+			size += nextSize
+			if size < len(s) {
+				_, joinedSize := utf8.DecodeRuneInString(s[size:])
+				size += joinedSize
+			}
+		default:
+			return size
+		}
+	}
+
+	return size
+}
+
+// isGraphemeExtend reports if r extends the grapheme cluster before it.
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		(r >= 0xfe00 && r <= 0xfe0f) || // Variation selectors.
+		(r >= 0x1f3fb && r <= 0x1f3ff) || // Emoji skin tone modifiers.
+		(r >= 0xe0020 && r <= 0xe007f) // Tags, used by subdivision flags.
+}
+
+// isRegionalIndicator reports if r is one of the letters that form a flag emoji.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,34 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestTruncateModifier(t *testing.T) {
+	tests := []struct {
+		value string
+		arg   string
+		want  string
+	}{
+		{value: "Hello world", arg: "8", want: "Hello w…"},
+		{value: "Hello world", arg: "11", want: "Hello world"},
+		{value: "Hello world", arg: "7", want: "Hello…"},
+		{value: "Hello world", arg: "", want: "Hello world"},
+		{value: "Thumbs 👍🏽👍🏽", arg: "8", want: "Thumbs…"},
+		{value: "👍🏽👍🏽👍🏽", arg: "3", want: "👍🏽👍🏽👍🏽"},
+		{value: "👍🏽👍🏽👍🏽", arg: "2", want: "👍🏽…"},
+		{value: "Family \U0001F468\u200d\U0001F469\u200d\U0001F467!", arg: "9", want: "Family \U0001F468\u200d\U0001F469\u200d\U0001F467!"},
+		{value: "Family \U0001F468\u200d\U0001F469\u200d\U0001F467!", arg: "8", want: "Family…"},
+		{value: "🇳🇱🇧🇪🇩🇪", arg: "2", want: "🇳🇱…"},
+		{value: "Café crème", arg: "5", want: "Café…"},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, truncateModifier(language.English, tt.value, tt.arg), tt.value)
+	}
+
+	require.Equal(t, "你好……", truncateModifier(language.Chinese, "你好世界和平", "3"))
+}
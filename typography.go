@@ -0,0 +1,139 @@
+package messages
+
+import (
+	"strings"
+	"unicode"
+)
+
+// quoteStyle holds the typographic quotes of a language.
+type quoteStyle struct {
+	open, close             string
+	openSingle, closeSingle string
+}
+
+// quoteStyles are the quotes of the languages that Typography supports, keyed by the language without region.
+var quoteStyles = map[string]quoteStyle{
+	"en": {open: "“", close: "”", openSingle: "‘", closeSingle: "’"},
+	"nl": {open: "“", close: "”", openSingle: "‘", closeSingle: "’"},
+	"de": {open: "„", close: "“", openSingle: "‚", closeSingle: "‘"},
+	"fr": {open: "«\u00a0", close: "\u00a0»", openSingle: "‹\u00a0", closeSingle: "\u00a0›"},
+	"es": {open: "«", close: "»", openSingle: "“", closeSingle: "”"},
+	"it": {open: "«", close: "»", openSingle: "“", closeSingle: "”"},
+	"pt": {open: "“", close: "”", openSingle: "‘", closeSingle: "’"},
+	"pl": {open: "„", close: "”", openSingle: "«", closeSingle: "»"},
+}
+
+const (
+	apostrophe         = "’"
+	noBreakSpace       = '\u00a0'
+	narrowNoBreakSpace = '\u202f'
+)
+
+// Typography returns a post processor that replaces the straight quotes and apostrophes with the typographic ones of the language,
+// e.g. « » for French and „ “ for German, and adds the non-breaking spaces before the French punctuation ; : ! and ?.
+// Languages without typographic rules, and the quotes in HTML tags, are not changed. Add it with WithPostProcess:
+//
+//	tr, err := messages.NewTranslator(fs, "translations", messages.WithPostProcess(messages.Typography()))
+func Typography() PostProcessor {
+	return func(lang LanguageID, _ Key, out string) string {
+		style, ok := quoteStyles[lang.Language]
+		if !ok || !strings.ContainsAny(out, "\"'!?:;") {
+			return out
+		}
+
+		return typography(out, style, lang.Language == "fr")
+	}
+}
+
+// typography applies the quote style to s and adds the French non-breaking spaces when french is set.
+func typography(s string, style quoteStyle, french bool) string {
+	runes := []rune(s)
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+
+	inTag, openDouble, openSingle := false, false, false
+	// Prev is the last rune that is written, so the inserted quotes are seen by the next rune.
+	var prev rune
+	for i, r := range runes {
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case inTag:
+			inTag = r != '>'
+		case r == '<' && (unicode.IsLetter(next) || next == '/'):
+			inTag = true
+		case r == '"':
+			if !openDouble && isOpeningPosition(prev) {
+				b.WriteString(style.open)
+				openDouble = true
+			} else {
+				b.WriteString(style.close)
+				openDouble = false
+			}
+			prev = r
+			continue
+		case r == '\'':
+			switch {
+			case isWordRune(prev) && isWordRune(next):
+				b.WriteString(apostrophe)
+			case !openSingle && isOpeningPosition(prev):
+				b.WriteString(style.openSingle)
+				openSingle = true
+			case openSingle:
+				b.WriteString(style.closeSingle)
+				openSingle = false
+			default:
+				b.WriteString(apostrophe)
+			}
+			prev = r
+			continue
+		case french && isFrenchPunctuation(r, prev, next):
+			space := narrowNoBreakSpace
+			if r == ':' {
+				space = noBreakSpace
+			}
+
+			// A space before the punctuation is replaced, otherwise the space is added.
+			if prev == ' ' {
+				trimmed := strings.TrimSuffix(b.String(), " ")
+				b.Reset()
+				b.WriteString(trimmed)
+			}
+			b.WriteRune(space)
+		}
+
+		b.WriteRune(r)
+		prev = r
+	}
+
+	return b.String()
+}
+
+// isOpeningPosition reports if a quote after prev opens a quotation.
+func isOpeningPosition(prev rune) bool {
+	return prev == 0 || unicode.IsSpace(prev) || strings.ContainsRune("([{<>-–—/", prev)
+}
+
+// isWordRune reports if r is part of a word, an apostrophe between two word runes is not a quote.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isFrenchPunctuation reports if r is punctuation that needs a non-breaking space before it in French.
+// Punctuation at the start of the message, in times like 10:30 and in URLs like https://, is skipped.
+func isFrenchPunctuation(r, prev, next rune) bool {
+	if !strings.ContainsRune(";:!?", r) || prev == 0 || prev == noBreakSpace || prev == narrowNoBreakSpace {
+		return false
+	}
+
+	// Repeated punctuation like "?!" only gets a space before the first.
+	if strings.ContainsRune(";:!?", prev) {
+		return false
+	}
+
+	return next == 0 || unicode.IsSpace(next) || strings.ContainsRune(";:!?)\"»", next)
+}
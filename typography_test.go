@@ -0,0 +1,35 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypography(t *testing.T) {
+	typography := Typography()
+
+	tests := []struct {
+		lang string
+		in   string
+		want string
+	}{
+		{lang: "en", in: `He said "don't" and 'no'`, want: "He said “don’t” and ‘no’"},
+		{lang: "en-GB", in: `The users' files`, want: "The users’ files"},
+		{lang: "de", in: `Er sagte "Hallo" und 'Tschüss'`, want: "Er sagte „Hallo“ und ‚Tschüss‘"},
+		{lang: "fr", in: `Il a dit "Bonjour" !`, want: "Il a dit «\u00a0Bonjour\u00a0»\u202f!"},
+		{lang: "fr", in: `Attention: l'heure est 10:30; voir https://example.com?`, want: "Attention\u00a0: l’heure est 10:30\u202f; voir https://example.com\u202f?"},
+		{lang: "fr", in: `Vraiment ?!`, want: "Vraiment\u202f?!"},
+		// Quotes in HTML tags are kept.
+		{lang: "en", in: `Click <a href="/help">"here"</a>`, want: "Click <a href=\"/help\">“here”</a>"},
+		// Languages without rules are not changed.
+		{lang: "ja", in: `"Hello"`, want: `"Hello"`},
+	}
+
+	for _, tt := range tests {
+		lang, err := ParseLanguage(tt.lang)
+		require.NoError(t, err)
+
+		require.Equal(t, tt.want, typography(lang, "key", tt.in), tt.in)
+	}
+}
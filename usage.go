@@ -0,0 +1,171 @@
+package messages
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/spf13/afero"
+)
+
+// Usage counts the translations by requested language and key at runtime, see WithUsage.
+// Write the Counts to a file, e.g. usage.json, periodically and rank the keys with "msgextractor report -usage usage.json".
+type Usage struct {
+	// counters holds an *atomic.Int64 by usageKey.
+	counters sync.Map
+}
+
+// usageKey is the language and key of a counter.
+type usageKey struct {
+	lang LanguageID
+	key  Key
+}
+
+// UsageCounts holds the number of translations by language and key, e.g. {"nl": {"welcome": 12}}.
+type UsageCounts map[string]map[string]int64
+
+// WithUsage counts every translation in usage. The requested language is counted, or the default language when the ctx has no language,
+// so a key is also counted for a language without translations. Translate, TranslatePlural and ByCode are counted, a plural message
+// is counted by its key without the form. Previews, like Preview and CodeTable, are not counted.
+func WithUsage(usage *Usage) Opt {
+	return func(t *Translator) {
+		t.usage = usage
+	}
+}
+
+// count adds a translation of key for the language of the ctx.
+func (u *Usage) count(ctx context.Context, defaultLanguage LanguageID, key Key) {
+	lang := FromCtx(ctx)
+	if lang.Empty() {
+		lang = defaultLanguage
+	}
+
+	// The forms of a plural message are counted as the message itself.
+	base, _, _ := splitCasingDirective(key)
+	if pluralBase, _, ok := SplitPluralKey(string(base)); ok {
+		base = Key(pluralBase)
+	}
+	k := usageKey{lang: lang, key: base}
+
+	counter, ok := u.counters.Load(k)
+	if !ok {
+		counter, _ = u.counters.LoadOrStore(k, new(atomic.Int64))
+	}
+
+	counter.(*atomic.Int64).Add(1)
+}
+
+// Counts returns the number of translations by language and key so far.
+func (u *Usage) Counts() UsageCounts {
+	counts := make(UsageCounts)
+	u.counters.Range(func(k, counter any) bool {
+		key := k.(usageKey)
+		lang := key.lang.String()
+		if counts[lang] == nil {
+			counts[lang] = make(map[string]int64)
+		}
+
+		counts[lang][string(key.key)] += counter.(*atomic.Int64).Load()
+		return true
+	})
+
+	return counts
+}
+
+// LocaleUsage is the number of translations of a language.
+type LocaleUsage struct {
+	Language string `json:"language"`
+	Count    int64  `json:"count"`
+}
+
+// KeyUsage is the number of translations of a key in all languages.
+type KeyUsage struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+	// Missing are the popular locales that have no translation of the key.
+	Missing []string `json:"missing,omitempty"`
+	// InSource is false for a key that is not extracted from the source code, e.g. a dynamic key.
+	InSource bool `json:"in_source"`
+}
+
+// UsageReport ranks the keys by traffic, see UsageReportFromDir.
+type UsageReport struct {
+	// Locales are the popular locales, sorted by count.
+	Locales []LocaleUsage `json:"locales"`
+	// Keys are the keys with traffic and the keys of the source code, sorted by count.
+	Keys []KeyUsage `json:"keys"`
+}
+
+// UsageReportFromDir joins the runtime counts with the keys that are extracted from the source code and the translation files in dir.
+// The popularLocales languages with the most translations are the popular locales, a key is missing in a popular locale when the translation
+// file of the language, or of its base language, has no translation for it.
+func UsageReportFromDir(fs afero.Fs, dir string, counts UsageCounts, keys []string, popularLocales int, opts ...ParserOpt) (UsageReport, error) {
+	languages, err := rawMessagesFromDir(NewParser(fs, opts...), dir)
+	if err != nil {
+		return UsageReport{}, err
+	}
+
+	var report UsageReport
+	for lang, keyCounts := range counts {
+		locale := LocaleUsage{Language: lang}
+		for _, count := range keyCounts {
+			locale.Count += count
+		}
+		report.Locales = append(report.Locales, locale)
+	}
+
+	slices.SortFunc(report.Locales, func(a, b LocaleUsage) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Language, b.Language))
+	})
+	if len(report.Locales) > popularLocales {
+		report.Locales = report.Locales[:popularLocales]
+	}
+
+	totals := make(map[string]int64)
+	for _, keyCounts := range counts {
+		for key, count := range keyCounts {
+			totals[key] += count
+		}
+	}
+	inSource := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		totals[key] += 0
+		inSource[key] = true
+	}
+
+	for key, count := range totals {
+		usage := KeyUsage{Key: key, Count: count, InSource: inSource[key]}
+		for _, locale := range report.Locales {
+			if !translated(languages, locale.Language, key) {
+				usage.Missing = append(usage.Missing, locale.Language)
+			}
+		}
+
+		report.Keys = append(report.Keys, usage)
+	}
+
+	slices.SortFunc(report.Keys, func(a, b KeyUsage) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Key, b.Key))
+	})
+
+	return report, nil
+}
+
+// translated reports if the translation file of the language, or of its base language, has a translation for key,
+// or for the other form of a plural key.
+func translated(languages map[LanguageID]*RawMessages, language, key string) bool {
+	lang, err := ParseLanguage(language)
+	if err != nil {
+		return false
+	}
+
+	for _, id := range []LanguageID{lang, {Language: lang.Language}} {
+		if raw, ok := languages[id]; ok && (raw.Messages[key] != "" || raw.Messages[PluralKey(key, PluralOther)] != "") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{"welcome": "Welcome", "bye": "Bye", "cart": "Cart", "unused": "Unused"}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/nl.json", []byte(`{"welcome": "Welkom", "cart": ""}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/de.json", []byte(`{"welcome": "Willkommen", "bye": "Tschüss", "cart": "Warenkorb"}`), 0o644))
+
+	usage := &Usage{}
+	tr, err := NewTranslator(fs, "translations", WithDefaultLanguage(LanguageID{Language: "en"}), WithUsage(usage))
+	require.NoError(t, err)
+
+	nl := ToCtx(context.Background(), "nl-BE")
+	de := ToCtx(context.Background(), "de")
+	memo := WithMemo(ToCtx(context.Background(), "fr"))
+	for range 5 {
+		tr.Translate(nl, "welcome", nil)
+		tr.Translate(nl, "cart", nil)
+		tr.Translate(memo, "welcome", nil)
+	}
+	tr.Translate(nl, "welcome!upper", nil)
+	tr.Translate(de, "bye", nil)
+	tr.Translate(context.Background(), "dynamic.key", nil)
+
+	counts := usage.Counts()
+	require.Equal(t, UsageCounts{
+		"nl-BE": {"welcome": 6, "cart": 5},
+		"fr":    {"welcome": 5},
+		"de":    {"bye": 1},
+		"en":    {"dynamic.key": 1},
+	}, counts)
+
+	report, err := UsageReportFromDir(fs, "translations", counts, []string{"welcome", "cart", "bye", "unused"}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []LocaleUsage{{Language: "nl-BE", Count: 11}, {Language: "fr", Count: 5}}, report.Locales)
+	require.Equal(t, []KeyUsage{
+		{Key: "welcome", Count: 11, Missing: []string{"fr"}, InSource: true},
+		{Key: "cart", Count: 5, Missing: []string{"nl-BE", "fr"}, InSource: true},
+		{Key: "bye", Count: 1, Missing: []string{"nl-BE", "fr"}, InSource: true},
+		{Key: "dynamic.key", Count: 1, Missing: []string{"nl-BE", "fr"}},
+		{Key: "unused", Missing: []string{"nl-BE", "fr"}, InSource: true},
+	}, report.Keys)
+}
@@ -0,0 +1,82 @@
+package messages
+
+import (
+	"context"
+	"embed"
+	"fmt"
+
+	"github.com/spf13/afero"
+)
+
+//go:embed catalogs/validation/*.json
+var validationFS embed.FS
+
+// WithValidationMessages adds the built-in validation messages for en, nl, de, fr and es, e.g. "validation.required" and "validation.email".
+// The messages use the :attribute replacement, see AttributeKey. See catalogs/validation for all keys.
+//
+// The built-in messages are added to the languages of the translations, a key in the translations overrides the built-in message.
+func WithValidationMessages() Opt {
+	return func(t *Translator) {
+		t.layers = append(t.layers, NewFileStore(afero.FromIOFS{FS: validationFS}, "catalogs/validation"))
+	}
+}
+
+// loadLayers loads the messages of the layers below the translations, keyed by language.
+// Later layers override earlier layers.
+func (t *Translator) loadLayers(ctx context.Context) (map[string]*RawMessages, error) {
+	layers := make(map[string]*RawMessages)
+	for _, layer := range t.layers {
+		languages, _, err := layer.Load(ctx, Version{})
+		if err != nil {
+			return nil, fmt.Errorf("loading layer: %w", err)
+		}
+
+		for lang, raw := range languages {
+			base, ok := layers[lang.String()]
+			if !ok {
+				base = &RawMessages{Messages: make(map[string]string), Attributes: make(map[string]string)}
+				layers[lang.String()] = base
+			}
+
+			mergeMessages(base, raw)
+		}
+	}
+
+	return layers, nil
+}
+
+// mergeMessages copies the messages and attributes of src into dst, overriding the values in dst.
+func mergeMessages(dst, src *RawMessages) {
+	// MergeTheirs resolves every conflict, so Merge does not return an error.
+	_ = Merge(dst, src, MergeTheirs())
+}
+
+// layered returns raw with the messages of the layer below it, the messages in raw take precedence.
+func layered(layer, raw *RawMessages) *RawMessages {
+	if layer == nil {
+		return raw
+	}
+
+	merged := &RawMessages{
+		Messages:   make(map[string]string, len(layer.Messages)+len(raw.Messages)),
+		Attributes: make(map[string]string, len(layer.Attributes)+len(raw.Attributes)),
+	}
+	mergeMessages(merged, layer)
+	mergeMessages(merged, raw)
+
+	return merged
+}
+
+// layerFor returns the layer messages for languageID, the messages of the base language are used for a regional language like en-US.
+func layerFor(layers map[string]*RawMessages, languageID string) *RawMessages {
+	if layer, ok := layers[languageID]; ok {
+		return layer
+	}
+
+	lang, err := ParseLanguage(languageID)
+	if err != nil {
+		return nil
+	}
+
+	return layers[lang.Language]
+}
@@ -0,0 +1,51 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+)
+
+func TestValidationMessages(t *testing.T) {
+	tr, err := NewTranslator(afero.NewOsFs(), "./testdata/validation", WithValidationMessages())
+	require.NoError(t, err)
+
+	en, err := WithLanguage(context.Background(), "en-US")
+	require.NoError(t, err)
+
+	nl, err := WithLanguage(context.Background(), "nl")
+	require.NoError(t, err)
+
+	// The translations override the built-in messages.
+	require.Equal(t, "Street is mandatory.", tr.Translate(en, "validation.required", map[string]any{AttributeKey: "addr_street"}))
+	require.Equal(t, "Street must be a valid email address.", tr.Translate(en, "validation.email", map[string]any{AttributeKey: "addr_street"}))
+	require.Equal(t, "Straat is verplicht.", tr.Translate(nl, "validation.required", map[string]any{AttributeKey: "addr_street"}))
+	require.Equal(t, "Age moet tussen 18 en 99 liggen.", tr.Translate(nl, "validation.between", map[string]any{AttributeKey: "age", "min": 18, "max": 99}))
+	require.Equal(t, "Welkom", tr.Translate(nl, "welcome", nil))
+
+	// Only the languages of the translations are loaded.
+	require.ElementsMatch(t, []string{"en-US", "nl"}, maps.Keys(tr.current.Load().languages))
+
+	// The built-in messages are not used without the option.
+	tr, err = NewTranslator(afero.NewOsFs(), "./testdata/validation")
+	require.NoError(t, err)
+	require.Equal(t, "validation.email", tr.Translate(en, "validation.email", nil))
+}
+
+func TestValidationMessagesAreComplete(t *testing.T) {
+	store := NewFileStore(afero.FromIOFS{FS: validationFS}, "catalogs/validation")
+	languages, _, err := store.Load(context.Background(), Version{})
+	require.NoError(t, err)
+	require.Len(t, languages, 5)
+
+	en := languages[LanguageID{Language: "en"}]
+	for lang, raw := range languages {
+		require.ElementsMatch(t, maps.Keys(en.Messages), maps.Keys(raw.Messages), lang.String())
+
+		_, err := NewParser(nil).parseMessages(lang.String(), lang.String(), raw)
+		require.NoError(t, err, lang.String())
+	}
+}
@@ -0,0 +1,86 @@
+package messages
+
+import (
+	"time"
+
+	"github.com/wvell/messages/internal/sorted"
+)
+
+// timedVariant is a variant of a key that is only used between its valid from and valid until time.
+type timedVariant struct {
+	key        Key
+	validFrom  time.Time
+	validUntil time.Time
+}
+
+// activeAt returns true if the time is between the valid from and valid until time, a zero time is unbounded.
+func (v timedVariant) activeAt(now time.Time) bool {
+	return (v.validFrom.IsZero() || !now.Before(v.validFrom)) && (v.validUntil.IsZero() || now.Before(v.validUntil))
+}
+
+// timedVariants returns the variants with a valid from or valid until time by key, e.g. "banner#christmas" for "banner".
+func (m Metadata) timedVariants() map[Key][]timedVariant {
+	variants := make(map[Key][]timedVariant)
+	for _, key := range sorted.Keys(m) {
+		metadata := m[key]
+		if metadata.ValidFrom.IsZero() && metadata.ValidUntil.IsZero() {
+			continue
+		}
+
+		base, _, ok := SplitVariantKey(key)
+		if !ok {
+			continue
+		}
+
+		variants[Key(base)] = append(variants[Key(base)], timedVariant{key: Key(key), validFrom: metadata.ValidFrom, validUntil: metadata.ValidUntil})
+	}
+
+	return variants
+}
+
+// activeVariant returns the first active variant of the key that has a translation, the key is returned when no variant is active.
+// A casing directive of the key is kept.
+func (t *Translator) activeVariant(messages *messages, region string, key Key) Key {
+	c := t.current.Load()
+	if c == nil || len(c.timedVariants) == 0 {
+		return key
+	}
+
+	base, directive := key, ""
+	if b, _, ok := splitCasingDirective(key); ok {
+		base, directive = b, string(key[len(b):])
+	}
+
+	now := time.Now()
+	for _, variant := range c.timedVariants[base] {
+		if !variant.activeAt(now) {
+			continue
+		}
+
+		if _, ok := messages.lookup(variant.key, region); ok {
+			return variant.key + Key(directive)
+		}
+	}
+
+	return key
+}
+
+// ExpiredKeys returns the keys in the metadata whose valid until time has passed, sorted by key.
+// Remove the expired messages from the translation files, so seasonal copy does not keep shipping out of season.
+func (t *Translator) ExpiredKeys() []string {
+	c := t.current.Load()
+	if c == nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	var expired []string
+	for _, key := range sorted.Keys(c.metadata) {
+		if validUntil := c.metadata[key].ValidUntil; !validUntil.IsZero() && !now.Before(validUntil) {
+			expired = append(expired, key)
+		}
+	}
+
+	return expired
+}
@@ -0,0 +1,49 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimedVariants(t *testing.T) {
+	now := time.Now().UTC()
+	past, future := now.Add(-24*time.Hour).Format(time.RFC3339), now.Add(24*time.Hour).Format(time.RFC3339)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{
+		"banner": "Welcome",
+		"banner#christmas": "Merry Christmas",
+		"banner#easter": "Happy Easter",
+		"banner#sale": "Summer sale",
+		"footer": "Footer",
+		"footer#launch": "Launching soon"
+	}`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "translations/metadata.json", []byte(fmt.Sprintf(`{
+		"banner#christmas": {"valid_until": %q},
+		"banner#easter": {"valid_from": %q, "valid_until": %q},
+		"banner#sale": {"valid_from": %q},
+		"footer#launch": {"valid_from": %q}
+	}`, past, past, future, past, future)), 0o644))
+
+	tr, err := NewTranslator(fs, "translations")
+	require.NoError(t, err)
+
+	en := ToCtx(context.Background(), "en")
+
+	// The first active variant is used.
+	require.Equal(t, "Happy Easter", tr.Translate(en, "banner", nil))
+	require.Equal(t, "HAPPY EASTER", tr.Translate(en, "banner!upper", nil))
+
+	// The key is used when no variant is active yet.
+	require.Equal(t, "Footer", tr.Translate(en, "footer", nil))
+
+	// A variant can still be translated directly.
+	require.Equal(t, "Merry Christmas", tr.Translate(en, "banner#christmas", nil))
+
+	require.Equal(t, []string{"banner#christmas"}, tr.ExpiredKeys())
+}
@@ -0,0 +1,37 @@
+package messages
+
+import (
+	"context"
+	"strings"
+)
+
+// variantSeparator separates a key from the variant in variant keys, e.g. "checkout.cta#exp42".
+const variantSeparator = "#"
+
+// VariantResolver returns the variant of key for the ctx, e.g. the experiment the user is in. An empty variant uses the key itself.
+type VariantResolver func(ctx context.Context, key Key) string
+
+// WithVariantResolver translates a key with its variant when the resolver returns a variant, e.g. "checkout.cta#exp42" for "checkout.cta"
+// when the resolver returns "exp42". The key itself is used when the variant has no translation, so a variant only needs a message in the
+// languages of the experiment. The variant is resolved before the key rewriters that are added after this option.
+func WithVariantResolver(resolve VariantResolver) Opt {
+	return WithKeyRewrite(func(ctx context.Context, key Key) Key {
+		variant := resolve(ctx, key)
+		if variant == "" {
+			return key
+		}
+
+		return key + variantSeparator + Key(variant)
+	})
+}
+
+// SplitVariantKey splits a variant key like "checkout.cta#exp42" in the key "checkout.cta" and the variant "exp42".
+// Ok is false if the key has no variant.
+func SplitVariantKey(key string) (base, variant string, ok bool) {
+	i := strings.LastIndex(key, variantSeparator)
+	if i <= 0 || i == len(key)-1 {
+		return key, "", false
+	}
+
+	return key[:i], key[i+1:], true
+}
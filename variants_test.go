@@ -0,0 +1,51 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariantResolver(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "translations/en.json", []byte(`{
+		"checkout.cta": "Buy now",
+		"checkout.cta#exp42": "Get it today",
+		"checkout.cta#exp42@GB": "Get it today, mate",
+		"checkout.back": "Back"
+	}`), 0o644))
+
+	type experimentKey struct{}
+
+	tr, err := NewTranslator(fs, "translations", WithVariantResolver(func(ctx context.Context, key Key) string {
+		experiment, _ := ctx.Value(experimentKey{}).(string)
+		return experiment
+	}))
+	require.NoError(t, err)
+
+	en := ToCtx(context.Background(), "en")
+	exp := context.WithValue(en, experimentKey{}, "exp42")
+
+	require.Equal(t, "Buy now", tr.Translate(en, "checkout.cta", nil))
+	require.Equal(t, "Get it today", tr.Translate(exp, "checkout.cta", nil))
+	require.Equal(t, "GET IT TODAY", tr.Translate(exp, "checkout.cta!upper", nil))
+	require.Equal(t, "Get it today, mate", tr.Translate(ToCtx(context.WithValue(context.Background(), experimentKey{}, "exp42"), "en-GB"), "checkout.cta", nil))
+
+	// The key is used when the variant has no translation.
+	require.Equal(t, "Back", tr.Translate(exp, "checkout.back", nil))
+}
+
+func TestSplitVariantKey(t *testing.T) {
+	base, variant, ok := SplitVariantKey("checkout.cta#exp42")
+	require.True(t, ok)
+	require.Equal(t, "checkout.cta", base)
+	require.Equal(t, "exp42", variant)
+
+	_, _, ok = SplitVariantKey("checkout.cta")
+	require.False(t, ok)
+
+	_, _, ok = SplitVariantKey("checkout.cta#")
+	require.False(t, ok)
+}
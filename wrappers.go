@@ -0,0 +1,269 @@
+package messages
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// wrapperFinder finds translation keys that are passed as a string to wrapper functions.
+// A wrapper is a function that uses one of its string parameters as a translation key,
+// either directly or by passing it on to another wrapper.
+//
+// The finder collects all parameters and calls while the packages are processed,
+// the wrappers are resolved when all packages have been seen. This way the order in which packages are loaded does not matter.
+type wrapperFinder struct {
+	cfg *extractConfig
+	// Sinks are parameters that are used as a translation key directly.
+	sinks map[funcParam]bool
+	// Forwards maps a parameter to the parameters of other functions it is passed to.
+	forwards map[funcParam][]funcParam
+	// Calls holds the constant string arguments that are passed to a parameter.
+	calls map[funcParam][]foundKey
+}
+
+// funcParam identifies a parameter of a function by the full name of the function and the parameter index.
+type funcParam struct {
+	fn    string
+	index int
+}
+
+func newWrapperFinder(cfg *extractConfig) *wrapperFinder {
+	return &wrapperFinder{
+		cfg:      cfg,
+		sinks:    make(map[funcParam]bool),
+		forwards: make(map[funcParam][]funcParam),
+		calls:    make(map[funcParam][]foundKey),
+	}
+}
+
+// collect collects the parameters and calls from the given package, except for the calls in the ignored ranges.
+func (w *wrapperFinder) collect(pkg *packages.Package, ignored ignoredRanges) {
+	info := pkg.TypesInfo
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+
+			fn, ok := info.Defs[funcDecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+
+			w.collectParams(info, fn, funcDecl.Body)
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || ignored.contains(call.Pos()) {
+				return true
+			}
+
+			callee, sig := calledFunc(info, call)
+			if callee == nil {
+				return true
+			}
+
+			for i, arg := range call.Args {
+				if i >= sig.Params().Len() || !isStringType(sig.Params().At(i).Type()) {
+					continue
+				}
+
+				value := info.Types[arg].Value
+				if value == nil || value.Kind() != constant.String {
+					continue
+				}
+
+				param := funcParam{fn: callee.FullName(), index: i}
+				w.calls[param] = append(w.calls[param], foundKey{key: constant.StringVal(value), pos: pkg.Fset.Position(arg.Pos())})
+			}
+
+			return true
+		})
+	}
+}
+
+// collectParams finds the string parameters of fn that are used as a translation key or passed on to other functions.
+func (w *wrapperFinder) collectParams(info *types.Info, fn *types.Func, body *ast.BlockStmt) {
+	params := fn.Type().(*types.Signature).Params()
+
+	stringParams := make(map[*types.Var]int)
+	for i := range params.Len() {
+		if isStringType(params.At(i).Type()) && !w.cfg.isKeyType(params.At(i).Type()) {
+			stringParams[params.At(i)] = i
+		}
+	}
+
+	if len(stringParams) == 0 {
+		return
+	}
+
+	// paramOf returns the index of the string parameter the expression refers to.
+	paramOf := func(expr ast.Expr) (int, bool) {
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return 0, false
+		}
+
+		v, ok := info.Uses[ident].(*types.Var)
+		if !ok {
+			return 0, false
+		}
+
+		index, ok := stringParams[v]
+		return index, ok
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		// A conversion to the key type, like messages.Key(key).
+		if tv, ok := info.Types[call.Fun]; ok && tv.IsType() {
+			if w.cfg.isKeyType(tv.Type) && len(call.Args) == 1 {
+				if index, ok := paramOf(call.Args[0]); ok {
+					w.sinks[funcParam{fn: fn.FullName(), index: index}] = true
+				}
+			}
+
+			return true
+		}
+
+		callee, sig := calledFunc(info, call)
+		if callee == nil {
+			return true
+		}
+
+		for i, arg := range call.Args {
+			index, ok := paramOf(arg)
+			if !ok || i >= sig.Params().Len() {
+				continue
+			}
+
+			param := funcParam{fn: fn.FullName(), index: index}
+			if w.cfg.isKeyType(sig.Params().At(i).Type()) {
+				w.sinks[param] = true
+			} else if isStringType(sig.Params().At(i).Type()) {
+				w.forwards[param] = append(w.forwards[param], funcParam{fn: callee.FullName(), index: i})
+			}
+		}
+
+		return true
+	})
+}
+
+// keys returns all keys that are passed to wrappers.
+func (w *wrapperFinder) keys() []foundKey {
+	wrappers := make(map[funcParam]bool)
+	for param := range w.sinks {
+		wrappers[param] = true
+	}
+
+	// A parameter that is passed to a wrapper makes the function a wrapper as well.
+	for changed := true; changed; {
+		changed = false
+		for param, targets := range w.forwards {
+			if wrappers[param] {
+				continue
+			}
+
+			for _, target := range targets {
+				if wrappers[target] {
+					wrappers[param] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var keys []foundKey
+	for param := range wrappers {
+		keys = append(keys, w.calls[param]...)
+	}
+
+	return keys
+}
+
+// calledFunc returns the function and its signature that is called by the call expression.
+// Nil is returned for calls that are not a (non variadic) function or method call.
+func calledFunc(info *types.Info, call *ast.CallExpr) (*types.Func, *types.Signature) {
+	var ident *ast.Ident
+	switch fun := instantiatedFunc(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil, nil
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Variadic() {
+		return nil, nil
+	}
+
+	return fn.Origin(), sig
+}
+
+// instantiatedFunc returns the generic function of an explicit instantiation like T[messages.Key], other expressions are returned as is.
+func instantiatedFunc(fun ast.Expr) ast.Expr {
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		return f.X
+	case *ast.IndexListExpr:
+		return f.X
+	}
+
+	return fun
+}
+
+// isStringType reports if typ is a string type, or a type parameter that can only be a string type like K ~string.
+func isStringType(typ types.Type) bool {
+	if param, ok := typ.(*types.TypeParam); ok {
+		return isStringConstraint(param.Constraint())
+	}
+
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// isStringConstraint reports if every type in the type set of the constraint is a string type.
+func isStringConstraint(constraint types.Type) bool {
+	iface, ok := constraint.Underlying().(*types.Interface)
+	if !ok || iface.NumEmbeddeds() == 0 {
+		return false
+	}
+
+	for i := range iface.NumEmbeddeds() {
+		embedded := iface.EmbeddedType(i)
+
+		union, ok := embedded.(*types.Union)
+		if !ok {
+			if !isStringType(embedded) {
+				return false
+			}
+			continue
+		}
+
+		for j := range union.Len() {
+			if !isStringType(union.Term(j).Type()) {
+				return false
+			}
+		}
+	}
+
+	return true
+}